@@ -14,8 +14,8 @@
 //
 // Author: Evan Spendlove, GitHub: @evanSpendlove.
 //
-// Probe defines the probe interface for Hermes probes.
-// It is used in a variety of other files as it is the top-level probe interface.
+//  Probe defines the probe interface for Hermes probes.
+//  It is used in a variety of other files as it is the top-level probe interface.
 
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
@@ -46,6 +46,72 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
+// ErrorClass is a class of transient errors.
+type RetryPolicy_ErrorClass int32
+
+const (
+	RetryPolicy_ERROR_CLASS_UNSPECIFIED RetryPolicy_ErrorClass = 0
+	// HTTP 429, the storage system is throttling requests.
+	RetryPolicy_RATE_LIMITED RetryPolicy_ErrorClass = 1
+	// HTTP 5xx, the storage system failed to serve the request.
+	RetryPolicy_SERVER_ERROR RetryPolicy_ErrorClass = 2
+	// The connection to the storage system was reset or closed unexpectedly.
+	RetryPolicy_NETWORK_ERROR RetryPolicy_ErrorClass = 3
+)
+
+// Enum value maps for RetryPolicy_ErrorClass.
+var (
+	RetryPolicy_ErrorClass_name = map[int32]string{
+		0: "ERROR_CLASS_UNSPECIFIED",
+		1: "RATE_LIMITED",
+		2: "SERVER_ERROR",
+		3: "NETWORK_ERROR",
+	}
+	RetryPolicy_ErrorClass_value = map[string]int32{
+		"ERROR_CLASS_UNSPECIFIED": 0,
+		"RATE_LIMITED":            1,
+		"SERVER_ERROR":            2,
+		"NETWORK_ERROR":           3,
+	}
+)
+
+func (x RetryPolicy_ErrorClass) Enum() *RetryPolicy_ErrorClass {
+	p := new(RetryPolicy_ErrorClass)
+	*p = x
+	return p
+}
+
+func (x RetryPolicy_ErrorClass) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RetryPolicy_ErrorClass) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_googleinterns_step224_2020_config_proto_probe_proto_enumTypes[0].Descriptor()
+}
+
+func (RetryPolicy_ErrorClass) Type() protoreflect.EnumType {
+	return &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_enumTypes[0]
+}
+
+func (x RetryPolicy_ErrorClass) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *RetryPolicy_ErrorClass) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = RetryPolicy_ErrorClass(num)
+	return nil
+}
+
+// Deprecated: Use RetryPolicy_ErrorClass.Descriptor instead.
+func (RetryPolicy_ErrorClass) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_probe_proto_rawDescGZIP(), []int{2, 0}
+}
+
 // TargetSystem is expected to be a storage system.
 // It will stay constant until support for new storage systems is added.
 type HermesProbeDef_TargetSystem int32
@@ -78,11 +144,11 @@ func (x HermesProbeDef_TargetSystem) String() string {
 }
 
 func (HermesProbeDef_TargetSystem) Descriptor() protoreflect.EnumDescriptor {
-	return file_github_com_googleinterns_step224_2020_config_proto_probe_proto_enumTypes[0].Descriptor()
+	return file_github_com_googleinterns_step224_2020_config_proto_probe_proto_enumTypes[1].Descriptor()
 }
 
 func (HermesProbeDef_TargetSystem) Type() protoreflect.EnumType {
-	return &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_enumTypes[0]
+	return &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_enumTypes[1]
 }
 
 func (x HermesProbeDef_TargetSystem) Number() protoreflect.EnumNumber {
@@ -101,7 +167,561 @@ func (x *HermesProbeDef_TargetSystem) UnmarshalJSON(b []byte) error {
 
 // Deprecated: Use HermesProbeDef_TargetSystem.Descriptor instead.
 func (HermesProbeDef_TargetSystem) EnumDescriptor() ([]byte, []int) {
-	return file_github_com_googleinterns_step224_2020_config_proto_probe_proto_rawDescGZIP(), []int{0, 0}
+	return file_github_com_googleinterns_step224_2020_config_proto_probe_proto_rawDescGZIP(), []int{7, 0}
+}
+
+// LeaseConfig defines how the targets are leased between Hermes instances.
+type LeaseConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Identifies this instance in the leases it holds.
+	// Must be unique among the instances, default = <hostname>_<pid>.
+	Holder *string `protobuf:"bytes,1,opt,name=holder" json:"holder,omitempty"`
+	// Time in seconds after which a lease that was not renewed expires.
+	// Must be longer than the longest probing interval lengthened by its jitter
+	// plus the probe timeout, default = 3 probing intervals.
+	DurationSec *int32 `protobuf:"varint,2,opt,name=duration_sec,json=durationSec" json:"duration_sec,omitempty"`
+	// If specified, leases are stored as files in this directory instead of as a
+	// lock object in the target bucket. Only instances sharing the directory
+	// exclude each other, so this is intended for tests and single-host setups.
+	Dir *string `protobuf:"bytes,3,opt,name=dir" json:"dir,omitempty"`
+}
+
+func (x *LeaseConfig) Reset() {
+	*x = LeaseConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LeaseConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseConfig) ProtoMessage() {}
+
+func (x *LeaseConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseConfig.ProtoReflect.Descriptor instead.
+func (*LeaseConfig) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_probe_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LeaseConfig) GetHolder() string {
+	if x != nil && x.Holder != nil {
+		return *x.Holder
+	}
+	return ""
+}
+
+func (x *LeaseConfig) GetDurationSec() int32 {
+	if x != nil && x.DurationSec != nil {
+		return *x.DurationSec
+	}
+	return 0
+}
+
+func (x *LeaseConfig) GetDir() string {
+	if x != nil && x.Dir != nil {
+		return *x.Dir
+	}
+	return ""
+}
+
+// ShardConfig defines how the targets are sharded across a fleet of Hermes instances.
+// Every target is owned by one member of a consistent-hash ring of the instances,
+// so only the targets of an instance move when it joins or leaves the fleet.
+type ShardConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Identifies this instance on the ring.
+	// Default = the lease holder if leasing is configured, otherwise <hostname>_<pid>.
+	Instance *string `protobuf:"bytes,1,opt,name=instance" json:"instance,omitempty"`
+	// The instances of the fleet, including this instance. Membership can also
+	// be changed at runtime through the UpdateShardMembers RPC.
+	// If empty, this instance starts as the only member.
+	Members []string `protobuf:"bytes,2,rep,name=members" json:"members,omitempty"`
+	// Number of points each instance has on the ring, default = 100.
+	VirtualNodes *int32 `protobuf:"varint,3,opt,name=virtual_nodes,json=virtualNodes" json:"virtual_nodes,omitempty"`
+}
+
+func (x *ShardConfig) Reset() {
+	*x = ShardConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShardConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShardConfig) ProtoMessage() {}
+
+func (x *ShardConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShardConfig.ProtoReflect.Descriptor instead.
+func (*ShardConfig) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_probe_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ShardConfig) GetInstance() string {
+	if x != nil && x.Instance != nil {
+		return *x.Instance
+	}
+	return ""
+}
+
+func (x *ShardConfig) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *ShardConfig) GetVirtualNodes() int32 {
+	if x != nil && x.VirtualNodes != nil {
+		return *x.VirtualNodes
+	}
+	return 0
+}
+
+// RetryPolicy defines how the API calls that failed with a transient error are retried.
+type RetryPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The API call this policy applies to, as in the api_call label of the
+	// metrics, e.g. "get_file". If empty, the policy applies to every API call
+	// without a policy of its own.
+	ApiCall *string `protobuf:"bytes,1,opt,name=api_call,json=apiCall" json:"api_call,omitempty"`
+	// Maximum number of attempts of an API call, including the first attempt.
+	// Default = 1, which does not retry.
+	MaxAttempts *int32 `protobuf:"varint,2,opt,name=max_attempts,json=maxAttempts" json:"max_attempts,omitempty"`
+	// Delay before the first retry in milliseconds, default = 100.
+	InitialBackoffMs *int32 `protobuf:"varint,3,opt,name=initial_backoff_ms,json=initialBackoffMs" json:"initial_backoff_ms,omitempty"`
+	// Maximum delay between two attempts in milliseconds, default = 5000.
+	MaxBackoffMs *int32 `protobuf:"varint,4,opt,name=max_backoff_ms,json=maxBackoffMs" json:"max_backoff_ms,omitempty"`
+	// Factor the delay is multiplied by after every retry, default = 2.
+	BackoffMultiplier *float64 `protobuf:"fixed64,5,opt,name=backoff_multiplier,json=backoffMultiplier" json:"backoff_multiplier,omitempty"`
+	// The classes of errors that are retried, default = all of them.
+	RetryableErrors []RetryPolicy_ErrorClass `protobuf:"varint,6,rep,name=retryable_errors,json=retryableErrors,enum=hermes.RetryPolicy_ErrorClass" json:"retryable_errors,omitempty"`
+}
+
+func (x *RetryPolicy) Reset() {
+	*x = RetryPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RetryPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryPolicy) ProtoMessage() {}
+
+func (x *RetryPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryPolicy.ProtoReflect.Descriptor instead.
+func (*RetryPolicy) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_probe_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RetryPolicy) GetApiCall() string {
+	if x != nil && x.ApiCall != nil {
+		return *x.ApiCall
+	}
+	return ""
+}
+
+func (x *RetryPolicy) GetMaxAttempts() int32 {
+	if x != nil && x.MaxAttempts != nil {
+		return *x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetInitialBackoffMs() int32 {
+	if x != nil && x.InitialBackoffMs != nil {
+		return *x.InitialBackoffMs
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetMaxBackoffMs() int32 {
+	if x != nil && x.MaxBackoffMs != nil {
+		return *x.MaxBackoffMs
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetBackoffMultiplier() float64 {
+	if x != nil && x.BackoffMultiplier != nil {
+		return *x.BackoffMultiplier
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetRetryableErrors() []RetryPolicy_ErrorClass {
+	if x != nil {
+		return x.RetryableErrors
+	}
+	return nil
+}
+
+// SLOConfig declares the service level objectives of every target, over which
+// Hermes computes the service level indicators of the target from its
+// scheduled runs.
+type SLOConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Target ratio of successful probe runs, e.g. 0.999. Must be in (0, 1).
+	AvailabilityTarget *float64 `protobuf:"fixed64,1,opt,name=availability_target,json=availabilityTarget" json:"availability_target,omitempty"`
+	// Latency under which a successful probe run meets the latency SLO in
+	// seconds, default = the probe timeout.
+	LatencyThresholdSec *float64 `protobuf:"fixed64,2,opt,name=latency_threshold_sec,json=latencyThresholdSec" json:"latency_threshold_sec,omitempty"`
+	// Target ratio of probe runs that succeed within latency_threshold_sec.
+	// Must be in (0, 1), default = availability_target.
+	LatencyTarget *float64 `protobuf:"fixed64,3,opt,name=latency_target,json=latencyTarget" json:"latency_target,omitempty"`
+	// Lengths of the rolling windows the indicators are computed over in
+	// seconds, default = 1 hour, 1 day and 28 days.
+	WindowSec []int64 `protobuf:"varint,4,rep,name=window_sec,json=windowSec" json:"window_sec,omitempty"`
+}
+
+func (x *SLOConfig) Reset() {
+	*x = SLOConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SLOConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SLOConfig) ProtoMessage() {}
+
+func (x *SLOConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SLOConfig.ProtoReflect.Descriptor instead.
+func (*SLOConfig) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_probe_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SLOConfig) GetAvailabilityTarget() float64 {
+	if x != nil && x.AvailabilityTarget != nil {
+		return *x.AvailabilityTarget
+	}
+	return 0
+}
+
+func (x *SLOConfig) GetLatencyThresholdSec() float64 {
+	if x != nil && x.LatencyThresholdSec != nil {
+		return *x.LatencyThresholdSec
+	}
+	return 0
+}
+
+func (x *SLOConfig) GetLatencyTarget() float64 {
+	if x != nil && x.LatencyTarget != nil {
+		return *x.LatencyTarget
+	}
+	return 0
+}
+
+func (x *SLOConfig) GetWindowSec() []int64 {
+	if x != nil {
+		return x.WindowSec
+	}
+	return nil
+}
+
+// AlertRule defines when an alert fires for a target. Exactly one of
+// consecutive_failures, status and burn_rate must be set.
+type AlertRule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the rule, unique within the probe. Required.
+	Name *string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Fires once this many consecutive probe runs of a target failed, and
+	// resolves once a run succeeds.
+	ConsecutiveFailures *int32 `protobuf:"varint,2,opt,name=consecutive_failures,json=consecutiveFailures" json:"consecutive_failures,omitempty"`
+	// Fires when a probe run of a target exits with this status, as in the
+	// exit_status label of the metrics, e.g. "file_corrupted", and resolves once
+	// a run exits with another status.
+	Status *string `protobuf:"bytes,3,opt,name=status" json:"status,omitempty"`
+	// Fires when the error budget of a target is consumed at this rate or faster
+	// over the SLO window burn_rate_window_sec, and resolves once it is consumed
+	// more slowly. A rate of 1 consumes exactly the budget of the window.
+	// Requires an SLO config.
+	BurnRate *float64 `protobuf:"fixed64,4,opt,name=burn_rate,json=burnRate" json:"burn_rate,omitempty"`
+	// The SLO window the burn rate is computed over in seconds, one of
+	// SLOConfig.window_sec. Required with burn_rate.
+	BurnRateWindowSec *int64 `protobuf:"varint,5,opt,name=burn_rate_window_sec,json=burnRateWindowSec" json:"burn_rate_window_sec,omitempty"`
+}
+
+func (x *AlertRule) Reset() {
+	*x = AlertRule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AlertRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRule) ProtoMessage() {}
+
+func (x *AlertRule) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRule.ProtoReflect.Descriptor instead.
+func (*AlertRule) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_probe_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AlertRule) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *AlertRule) GetConsecutiveFailures() int32 {
+	if x != nil && x.ConsecutiveFailures != nil {
+		return *x.ConsecutiveFailures
+	}
+	return 0
+}
+
+func (x *AlertRule) GetStatus() string {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return ""
+}
+
+func (x *AlertRule) GetBurnRate() float64 {
+	if x != nil && x.BurnRate != nil {
+		return *x.BurnRate
+	}
+	return 0
+}
+
+func (x *AlertRule) GetBurnRateWindowSec() int64 {
+	if x != nil && x.BurnRateWindowSec != nil {
+		return *x.BurnRateWindowSec
+	}
+	return 0
+}
+
+// AlertingConfig defines the alert rules of every target and the webhook
+// their notifications are sent to. The alerts of a target are only known to
+// the instance probing it, which resolves them when it stops probing the
+// target, e.g. when the target moves to another shard or lease holder.
+type AlertingConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// URL the notifications are sent to in HTTP POST requests with a JSON body.
+	// Required.
+	WebhookUrl *string      `protobuf:"bytes,1,opt,name=webhook_url,json=webhookUrl" json:"webhook_url,omitempty"`
+	Rules      []*AlertRule `protobuf:"bytes,2,rep,name=rules" json:"rules,omitempty"`
+	// Timeout of a webhook request in seconds, default = 10.
+	TimeoutSec *int32 `protobuf:"varint,3,opt,name=timeout_sec,json=timeoutSec" json:"timeout_sec,omitempty"`
+	// Interval after which the alerts of a target that are still firing are
+	// notified again in seconds, default = 14400 (4 hours).
+	RepeatIntervalSec *int64 `protobuf:"varint,4,opt,name=repeat_interval_sec,json=repeatIntervalSec" json:"repeat_interval_sec,omitempty"`
+}
+
+func (x *AlertingConfig) Reset() {
+	*x = AlertingConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AlertingConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertingConfig) ProtoMessage() {}
+
+func (x *AlertingConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertingConfig.ProtoReflect.Descriptor instead.
+func (*AlertingConfig) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_probe_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AlertingConfig) GetWebhookUrl() string {
+	if x != nil && x.WebhookUrl != nil {
+		return *x.WebhookUrl
+	}
+	return ""
+}
+
+func (x *AlertingConfig) GetRules() []*AlertRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *AlertingConfig) GetTimeoutSec() int32 {
+	if x != nil && x.TimeoutSec != nil {
+		return *x.TimeoutSec
+	}
+	return 0
+}
+
+func (x *AlertingConfig) GetRepeatIntervalSec() int64 {
+	if x != nil && x.RepeatIntervalSec != nil {
+		return *x.RepeatIntervalSec
+	}
+	return 0
+}
+
+// HistoryConfig defines the store the results of every probe run are recorded in.
+type HistoryConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Directory the run history is stored in. Required.
+	Dir *string `protobuf:"bytes,1,opt,name=dir" json:"dir,omitempty"`
+	// Time runs are kept for in seconds, default = 604800 (7 days).
+	// Runs are removed a day at a time, so they may be kept up to a day longer.
+	RetentionSec *int64 `protobuf:"varint,2,opt,name=retention_sec,json=retentionSec" json:"retention_sec,omitempty"`
+}
+
+func (x *HistoryConfig) Reset() {
+	*x = HistoryConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HistoryConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryConfig) ProtoMessage() {}
+
+func (x *HistoryConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryConfig.ProtoReflect.Descriptor instead.
+func (*HistoryConfig) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_probe_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HistoryConfig) GetDir() string {
+	if x != nil && x.Dir != nil {
+		return *x.Dir
+	}
+	return ""
+}
+
+func (x *HistoryConfig) GetRetentionSec() int64 {
+	if x != nil && x.RetentionSec != nil {
+		return *x.RetentionSec
+	}
+	return 0
 }
 
 // HermesProbeDef defines the proto config for the Hermes monitor probe.
@@ -115,7 +735,8 @@ type HermesProbeDef struct {
 	TargetSystem *HermesProbeDef_TargetSystem `protobuf:"varint,3,opt,name=target_system,json=targetSystem,enum=hermes.HermesProbeDef_TargetSystem" json:"target_system,omitempty"`
 	// Probing interval in seconds, default = 3600
 	IntervalSec *int32 `protobuf:"varint,4,opt,name=interval_sec,json=intervalSec" json:"interval_sec,omitempty"`
-	// Probes will not retry, they will fail if they timeout.
+	// Probes fail if they timeout. API calls are retried as configured by
+	// retry_policies, within this timeout.
 	TimeoutSec *int32 `protobuf:"varint,5,opt,name=timeout_sec,json=timeoutSec" json:"timeout_sec,omitempty"` // Timeout in seconds, default = 60
 	// If specified, latency is stored as a distribution metric.
 	// Measures the latency of Hermes' probes, allowing you to diagnose a network issue or issue with Hermes.
@@ -128,12 +749,71 @@ type HermesProbeDef struct {
 	// Add as key-value pairs
 	ProbeLatencyAdditionalLabel   []*proto2.AdditionalLabel `protobuf:"bytes,9,rep,name=probe_latency_additional_label,json=probeLatencyAdditionalLabel" json:"probe_latency_additional_label,omitempty"`
 	ApiCallLatencyAdditionalLabel []*proto2.AdditionalLabel `protobuf:"bytes,10,rep,name=api_call_latency_additional_label,json=apiCallLatencyAdditionalLabel" json:"api_call_latency_additional_label,omitempty"`
+	// Measures the time taken for a target to reflect the creation or deletion of a file.
+	// The measurement unit of lag will be seconds.
+	// Defaults to the api_call_latency_distribution.
+	ConsistencyLagDistribution *proto1.Dist `protobuf:"bytes,11,opt,name=consistency_lag_distribution,json=consistencyLagDistribution" json:"consistency_lag_distribution,omitempty"`
+	// Directory in which a copy of the journal of each target is stored.
+	// If specified, Hermes writes the journal both to this directory and to the
+	// NIL file of the target bucket, and reconciles the two copies on startup.
+	JournalDir *string `protobuf:"bytes,12,opt,name=journal_dir,json=journalDir" json:"journal_dir,omitempty"`
+	// If specified, every target is leased to a single Hermes instance at a time,
+	// so that several instances can run for high availability without modifying
+	// the same bucket. Instances that do not hold the lease of a target skip it
+	// and take it over once the lease expires.
+	Lease *LeaseConfig `protobuf:"bytes,13,opt,name=lease" json:"lease,omitempty"`
+	// If specified, the targets are sharded across the Hermes instances of a
+	// fleet and this instance only probes the targets it owns.
+	Shard *ShardConfig `protobuf:"bytes,14,opt,name=shard" json:"shard,omitempty"`
+	// Maximum number of targets probed at the same time, default = 10.
+	// Every target runs on its own schedule, so a slow or hung target only
+	// delays its own next run, but it holds one of these slots until it times out.
+	MaxConcurrentTargets *int32 `protobuf:"varint,15,opt,name=max_concurrent_targets,json=maxConcurrentTargets" json:"max_concurrent_targets,omitempty"`
+	// Maximum number of API calls per second made to each storage system backend,
+	// across all of the targets of that backend. Per-target limits are set with
+	// Target.api_qps_limit. Default = 0, which does not limit the rate of API calls.
+	BackendApiQpsLimit *float64 `protobuf:"fixed64,16,opt,name=backend_api_qps_limit,json=backendApiQpsLimit" json:"backend_api_qps_limit,omitempty"`
+	// Fraction of the interval by which each interval of a target is randomly
+	// lengthened or shortened, in [0, 1), default = 0.1. Every target also starts
+	// at a random offset within its first interval, so that targets sharing a
+	// backend do not send their API calls in synchronised bursts.
+	// Targets can override it with Target.interval_jitter.
+	IntervalJitter *float64 `protobuf:"fixed64,17,opt,name=interval_jitter,json=intervalJitter" json:"interval_jitter,omitempty"`
+	// Planned maintenance windows of every target, e.g. of a shared backend.
+	// Targets are not probed, or their results are marked, during maintenance,
+	// as configured by Target.maintenance_mode.
+	MaintenanceWindows []*MaintenanceWindow `protobuf:"bytes,18,rep,name=maintenance_windows,json=maintenanceWindows" json:"maintenance_windows,omitempty"`
+	// Retry policies of the API calls, at most one per API call. The metrics of
+	// API call latency record the final outcome of each call after its retries,
+	// and hermes_api_attempts_total counts every attempt, so both the raw error
+	// rate and the availability seen by users can be derived.
+	// A retried write uploads its contents again, and a retried listing restarts
+	// after the last object it listed. Default = no retries.
+	RetryPolicies []*RetryPolicy `protobuf:"bytes,19,rep,name=retry_policies,json=retryPolicies" json:"retry_policies,omitempty"`
+	// If specified, Hermes computes the success ratio, the latency SLO
+	// compliance ratio and the error budget remaining of every target over
+	// rolling windows, and exports them as metrics and through the GetTargetSLO RPC.
+	// Runs during maintenance and runs of targets leased to another instance are
+	// not counted.
+	Slo *SLOConfig `protobuf:"bytes,20,opt,name=slo" json:"slo,omitempty"`
+	// If specified, the alert rules are evaluated after every probe run of a
+	// target, and the alerts of the target that fire or resolve are notified to
+	// the webhook, grouped in a single notification per target. An alert that
+	// is still firing is not notified again until the repeat interval elapses.
+	// Runs during maintenance and runs of targets leased to another instance are
+	// not evaluated.
+	Alerting *AlertingConfig `protobuf:"bytes,21,opt,name=alerting" json:"alerting,omitempty"`
+	// If specified, the result of every probe run of a target is recorded in a
+	// local store, with the status and latency of each operation, the files
+	// touched and the error of the run, and can be queried by target and time
+	// range through the GetRunHistory RPC.
+	History *HistoryConfig `protobuf:"bytes,22,opt,name=history" json:"history,omitempty"`
 }
 
 func (x *HermesProbeDef) Reset() {
 	*x = HermesProbeDef{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[0]
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -146,7 +826,7 @@ func (x *HermesProbeDef) String() string {
 func (*HermesProbeDef) ProtoMessage() {}
 
 func (x *HermesProbeDef) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[0]
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -159,7 +839,7 @@ func (x *HermesProbeDef) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HermesProbeDef.ProtoReflect.Descriptor instead.
 func (*HermesProbeDef) Descriptor() ([]byte, []int) {
-	return file_github_com_googleinterns_step224_2020_config_proto_probe_proto_rawDescGZIP(), []int{0}
+	return file_github_com_googleinterns_step224_2020_config_proto_probe_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *HermesProbeDef) GetProbeName() string {
@@ -225,6 +905,90 @@ func (x *HermesProbeDef) GetApiCallLatencyAdditionalLabel() []*proto2.Additional
 	return nil
 }
 
+func (x *HermesProbeDef) GetConsistencyLagDistribution() *proto1.Dist {
+	if x != nil {
+		return x.ConsistencyLagDistribution
+	}
+	return nil
+}
+
+func (x *HermesProbeDef) GetJournalDir() string {
+	if x != nil && x.JournalDir != nil {
+		return *x.JournalDir
+	}
+	return ""
+}
+
+func (x *HermesProbeDef) GetLease() *LeaseConfig {
+	if x != nil {
+		return x.Lease
+	}
+	return nil
+}
+
+func (x *HermesProbeDef) GetShard() *ShardConfig {
+	if x != nil {
+		return x.Shard
+	}
+	return nil
+}
+
+func (x *HermesProbeDef) GetMaxConcurrentTargets() int32 {
+	if x != nil && x.MaxConcurrentTargets != nil {
+		return *x.MaxConcurrentTargets
+	}
+	return 0
+}
+
+func (x *HermesProbeDef) GetBackendApiQpsLimit() float64 {
+	if x != nil && x.BackendApiQpsLimit != nil {
+		return *x.BackendApiQpsLimit
+	}
+	return 0
+}
+
+func (x *HermesProbeDef) GetIntervalJitter() float64 {
+	if x != nil && x.IntervalJitter != nil {
+		return *x.IntervalJitter
+	}
+	return 0
+}
+
+func (x *HermesProbeDef) GetMaintenanceWindows() []*MaintenanceWindow {
+	if x != nil {
+		return x.MaintenanceWindows
+	}
+	return nil
+}
+
+func (x *HermesProbeDef) GetRetryPolicies() []*RetryPolicy {
+	if x != nil {
+		return x.RetryPolicies
+	}
+	return nil
+}
+
+func (x *HermesProbeDef) GetSlo() *SLOConfig {
+	if x != nil {
+		return x.Slo
+	}
+	return nil
+}
+
+func (x *HermesProbeDef) GetAlerting() *AlertingConfig {
+	if x != nil {
+		return x.Alerting
+	}
+	return nil
+}
+
+func (x *HermesProbeDef) GetHistory() *HistoryConfig {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
 var file_github_com_googleinterns_step224_2020_config_proto_probe_proto_extTypes = []protoimpl.ExtensionInfo{
 	{
 		ExtendedType:  (*proto2.ProbeDef)(nil),
@@ -260,60 +1024,174 @@ var file_github_com_googleinterns_step224_2020_config_proto_probe_proto_rawDesc
 	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x69, 0x6e, 0x74, 0x65,
 	0x72, 0x6e, 0x73, 0x2f, 0x73, 0x74, 0x65, 0x70, 0x32, 0x32, 0x34, 0x2d, 0x32, 0x30, 0x32, 0x30,
 	0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x61,
-	0x72, 0x67, 0x65, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x90, 0x06, 0x0a, 0x0e,
-	0x48, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x44, 0x65, 0x66, 0x12, 0x1d,
-	0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x28, 0x0a,
-	0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e,
-	0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x07,
-	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x48, 0x0a, 0x0d, 0x74, 0x61, 0x72, 0x67, 0x65,
-	0x74, 0x5f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x23,
-	0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x48, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x50, 0x72,
-	0x6f, 0x62, 0x65, 0x44, 0x65, 0x66, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x53, 0x79, 0x73,
-	0x74, 0x65, 0x6d, 0x52, 0x0c, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x53, 0x79, 0x73, 0x74, 0x65,
-	0x6d, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x73, 0x65,
-	0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61,
-	0x6c, 0x53, 0x65, 0x63, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f,
-	0x73, 0x65, 0x63, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x6f,
-	0x75, 0x74, 0x53, 0x65, 0x63, 0x12, 0x57, 0x0a, 0x1a, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x5f, 0x6c,
-	0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74,
-	0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x6c, 0x6f, 0x75,
+	0x72, 0x67, 0x65, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x5a, 0x0a, 0x0b, 0x4c,
+	0x65, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x6f,
+	0x6c, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x68, 0x6f, 0x6c, 0x64,
+	0x65, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73,
+	0x65, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x53, 0x65, 0x63, 0x12, 0x10, 0x0a, 0x03, 0x64, 0x69, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x64, 0x69, 0x72, 0x22, 0x68, 0x0a, 0x0b, 0x53, 0x68, 0x61, 0x72, 0x64,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x12, 0x23, 0x0a, 0x0d,
+	0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0c, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4e, 0x6f, 0x64, 0x65,
+	0x73, 0x22, 0xfb, 0x02, 0x0a, 0x0b, 0x52, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x70, 0x69, 0x5f, 0x63, 0x61, 0x6c, 0x6c, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x70, 0x69, 0x43, 0x61, 0x6c, 0x6c, 0x12, 0x21, 0x0a, 0x0c,
+	0x6d, 0x61, 0x78, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12,
+	0x2c, 0x0a, 0x12, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x6f,
+	0x66, 0x66, 0x5f, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x69, 0x6e, 0x69,
+	0x74, 0x69, 0x61, 0x6c, 0x42, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x4d, 0x73, 0x12, 0x24, 0x0a,
+	0x0e, 0x6d, 0x61, 0x78, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x5f, 0x6d, 0x73, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x42, 0x61, 0x63, 0x6b, 0x6f, 0x66,
+	0x66, 0x4d, 0x73, 0x12, 0x2d, 0x0a, 0x12, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x5f, 0x6d,
+	0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x11, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69,
+	0x65, 0x72, 0x12, 0x49, 0x0a, 0x10, 0x72, 0x65, 0x74, 0x72, 0x79, 0x61, 0x62, 0x6c, 0x65, 0x5f,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x68,
+	0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x52, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x52, 0x0f, 0x72, 0x65,
+	0x74, 0x72, 0x79, 0x61, 0x62, 0x6c, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x22, 0x60, 0x0a,
+	0x0a, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x1b, 0x0a, 0x17, 0x45,
+	0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4c, 0x41, 0x53, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x52, 0x41, 0x54, 0x45,
+	0x5f, 0x4c, 0x49, 0x4d, 0x49, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x45,
+	0x52, 0x56, 0x45, 0x52, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d,
+	0x4e, 0x45, 0x54, 0x57, 0x4f, 0x52, 0x4b, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x03, 0x22,
+	0xb6, 0x01, 0x0a, 0x09, 0x53, 0x4c, 0x4f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x2f, 0x0a,
+	0x13, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x5f, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x12, 0x61, 0x76, 0x61, 0x69,
+	0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x32,
+	0x0a, 0x15, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68,
+	0x6f, 0x6c, 0x64, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x13, 0x6c,
+	0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x53,
+	0x65, 0x63, 0x12, 0x25, 0x0a, 0x0e, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x6c, 0x61, 0x74, 0x65,
+	0x6e, 0x63, 0x79, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x69, 0x6e,
+	0x64, 0x6f, 0x77, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x04, 0x20, 0x03, 0x28, 0x03, 0x52, 0x09, 0x77,
+	0x69, 0x6e, 0x64, 0x6f, 0x77, 0x53, 0x65, 0x63, 0x22, 0xb8, 0x01, 0x0a, 0x09, 0x41, 0x6c, 0x65,
+	0x72, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x31, 0x0a, 0x14, 0x63, 0x6f,
+	0x6e, 0x73, 0x65, 0x63, 0x75, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x13, 0x63, 0x6f, 0x6e, 0x73, 0x65, 0x63,
+	0x75, 0x74, 0x69, 0x76, 0x65, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x75, 0x72, 0x6e, 0x5f, 0x72, 0x61,
+	0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x62, 0x75, 0x72, 0x6e, 0x52, 0x61,
+	0x74, 0x65, 0x12, 0x2f, 0x0a, 0x14, 0x62, 0x75, 0x72, 0x6e, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f,
+	0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x11, 0x62, 0x75, 0x72, 0x6e, 0x52, 0x61, 0x74, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77,
+	0x53, 0x65, 0x63, 0x22, 0xab, 0x01, 0x0a, 0x0e, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x69, 0x6e, 0x67,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x65, 0x62, 0x68, 0x6f, 0x6f,
+	0x6b, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x77, 0x65, 0x62,
+	0x68, 0x6f, 0x6f, 0x6b, 0x55, 0x72, 0x6c, 0x12, 0x27, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e,
+	0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73,
+	0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65,
+	0x63, 0x12, 0x2e, 0x0a, 0x13, 0x72, 0x65, 0x70, 0x65, 0x61, 0x74, 0x5f, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11,
+	0x72, 0x65, 0x70, 0x65, 0x61, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x53, 0x65,
+	0x63, 0x22, 0x46, 0x0a, 0x0d, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x64, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x64, 0x69, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x72, 0x65, 0x74,
+	0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x63, 0x22, 0x88, 0x0b, 0x0a, 0x0e, 0x48, 0x65,
+	0x72, 0x6d, 0x65, 0x73, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x44, 0x65, 0x66, 0x12, 0x1d, 0x0a, 0x0a,
+	0x70, 0x72, 0x6f, 0x62, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x28, 0x0a, 0x07, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x68,
+	0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x07, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x48, 0x0a, 0x0d, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f,
+	0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x23, 0x2e, 0x68,
+	0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x48, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x50, 0x72, 0x6f, 0x62,
+	0x65, 0x44, 0x65, 0x66, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x53, 0x79, 0x73, 0x74, 0x65,
+	0x6d, 0x52, 0x0c, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12,
+	0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x63, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x53,
+	0x65, 0x63, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x65,
+	0x63, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x53, 0x65, 0x63, 0x12, 0x57, 0x0a, 0x1a, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x5f, 0x6c, 0x61, 0x74,
+	0x65, 0x6e, 0x63, 0x79, 0x5f, 0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70,
+	0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x44, 0x69,
+	0x73, 0x74, 0x52, 0x18, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79,
+	0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x5c, 0x0a, 0x1d,
+	0x61, 0x70, 0x69, 0x5f, 0x63, 0x61, 0x6c, 0x6c, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79,
+	0x5f, 0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65,
+	0x72, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x52, 0x1a,
+	0x61, 0x70, 0x69, 0x43, 0x61, 0x6c, 0x6c, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x44, 0x69,
+	0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x68, 0x0a, 0x1e, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x61, 0x64, 0x64, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x09, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x23, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72,
+	0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x61, 0x6c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x1b, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x4c, 0x61,
+	0x74, 0x65, 0x6e, 0x63, 0x79, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x12, 0x6d, 0x0a, 0x21, 0x61, 0x70, 0x69, 0x5f, 0x63, 0x61, 0x6c, 0x6c,
+	0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x61, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x61, 0x6c, 0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x23, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x73, 0x2e, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x52, 0x1d, 0x61, 0x70, 0x69, 0x43, 0x61, 0x6c, 0x6c, 0x4c, 0x61, 0x74,
+	0x65, 0x6e, 0x63, 0x79, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x4c, 0x61,
+	0x62, 0x65, 0x6c, 0x12, 0x5b, 0x0a, 0x1c, 0x63, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e,
+	0x63, 0x79, 0x5f, 0x6c, 0x61, 0x67, 0x5f, 0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x6c, 0x6f, 0x75,
 	0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e,
-	0x44, 0x69, 0x73, 0x74, 0x52, 0x18, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x4c, 0x61, 0x74, 0x65, 0x6e,
-	0x63, 0x79, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x5c,
-	0x0a, 0x1d, 0x61, 0x70, 0x69, 0x5f, 0x63, 0x61, 0x6c, 0x6c, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e,
-	0x63, 0x79, 0x5f, 0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18,
-	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f,
-	0x62, 0x65, 0x72, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x44, 0x69, 0x73, 0x74,
-	0x52, 0x1a, 0x61, 0x70, 0x69, 0x43, 0x61, 0x6c, 0x6c, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79,
-	0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x68, 0x0a, 0x1e,
-	0x70, 0x72, 0x6f, 0x62, 0x65, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x61, 0x64,
-	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x09,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62,
-	0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69,
-	0x6f, 0x6e, 0x61, 0x6c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x1b, 0x70, 0x72, 0x6f, 0x62, 0x65,
-	0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61,
-	0x6c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x6d, 0x0a, 0x21, 0x61, 0x70, 0x69, 0x5f, 0x63, 0x61,
-	0x6c, 0x6c, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x61, 0x64, 0x64, 0x69, 0x74,
-	0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x0a, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x23, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e,
-	0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61,
-	0x6c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x1d, 0x61, 0x70, 0x69, 0x43, 0x61, 0x6c, 0x6c, 0x4c,
-	0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c,
-	0x4c, 0x61, 0x62, 0x65, 0x6c, 0x22, 0x36, 0x0a, 0x0c, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x53,
-	0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x1d, 0x0a, 0x19, 0x54, 0x41, 0x52, 0x47, 0x45, 0x54, 0x5f,
-	0x53, 0x59, 0x53, 0x54, 0x45, 0x4d, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49,
-	0x45, 0x44, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x47, 0x43, 0x53, 0x10, 0x01, 0x32, 0x5f, 0x0a,
-	0x10, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x5f, 0x64, 0x65,
-	0x66, 0x12, 0x1c, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e,
-	0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x44, 0x65, 0x66, 0x18,
-	0xc8, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e,
-	0x48, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x44, 0x65, 0x66, 0x52, 0x0e,
-	0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x44, 0x65, 0x66, 0x42, 0x34,
-	0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x73, 0x2f, 0x73, 0x74, 0x65, 0x70, 0x32,
-	0x32, 0x34, 0x2d, 0x32, 0x30, 0x32, 0x30, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x70,
-	0x72, 0x6f, 0x74, 0x6f,
+	0x44, 0x69, 0x73, 0x74, 0x52, 0x1a, 0x63, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63,
+	0x79, 0x4c, 0x61, 0x67, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x1f, 0x0a, 0x0b, 0x6a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x64, 0x69, 0x72, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x44, 0x69,
+	0x72, 0x12, 0x29, 0x0a, 0x05, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x05, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x05,
+	0x73, 0x68, 0x61, 0x72, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x68, 0x65,
+	0x72, 0x6d, 0x65, 0x73, 0x2e, 0x53, 0x68, 0x61, 0x72, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x52, 0x05, 0x73, 0x68, 0x61, 0x72, 0x64, 0x12, 0x34, 0x0a, 0x16, 0x6d, 0x61, 0x78, 0x5f, 0x63,
+	0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x14, 0x6d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x31, 0x0a,
+	0x15, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x5f, 0x61, 0x70, 0x69, 0x5f, 0x71, 0x70, 0x73,
+	0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x01, 0x52, 0x12, 0x62, 0x61,
+	0x63, 0x6b, 0x65, 0x6e, 0x64, 0x41, 0x70, 0x69, 0x51, 0x70, 0x73, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x6a, 0x69, 0x74,
+	0x74, 0x65, 0x72, 0x18, 0x11, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x76, 0x61, 0x6c, 0x4a, 0x69, 0x74, 0x74, 0x65, 0x72, 0x12, 0x4a, 0x0a, 0x13, 0x6d, 0x61, 0x69,
+	0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x73,
+	0x18, 0x12, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e,
+	0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f,
+	0x77, 0x52, 0x12, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x57, 0x69,
+	0x6e, 0x64, 0x6f, 0x77, 0x73, 0x12, 0x3a, 0x0a, 0x0e, 0x72, 0x65, 0x74, 0x72, 0x79, 0x5f, 0x70,
+	0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x18, 0x13, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x52, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x52, 0x0d, 0x72, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65,
+	0x73, 0x12, 0x23, 0x0a, 0x03, 0x73, 0x6c, 0x6f, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x53, 0x4c, 0x4f, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x52, 0x03, 0x73, 0x6c, 0x6f, 0x12, 0x32, 0x0a, 0x08, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x69,
+	0x6e, 0x67, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65,
+	0x73, 0x2e, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x52, 0x08, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x2f, 0x0a, 0x07, 0x68, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x68, 0x65,
+	0x72, 0x6d, 0x65, 0x73, 0x2e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x52, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x36, 0x0a, 0x0c, 0x54,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x1d, 0x0a, 0x19, 0x54,
+	0x41, 0x52, 0x47, 0x45, 0x54, 0x5f, 0x53, 0x59, 0x53, 0x54, 0x45, 0x4d, 0x5f, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x47, 0x43,
+	0x53, 0x10, 0x01, 0x32, 0x5f, 0x0a, 0x10, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x5f, 0x70, 0x72,
+	0x6f, 0x62, 0x65, 0x5f, 0x64, 0x65, 0x66, 0x12, 0x1c, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x70,
+	0x72, 0x6f, 0x62, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x73, 0x2e, 0x50, 0x72, 0x6f,
+	0x62, 0x65, 0x44, 0x65, 0x66, 0x18, 0xc8, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x68,
+	0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x48, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x50, 0x72, 0x6f, 0x62,
+	0x65, 0x44, 0x65, 0x66, 0x52, 0x0e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x50, 0x72, 0x6f, 0x62,
+	0x65, 0x44, 0x65, 0x66, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x73,
+	0x2f, 0x73, 0x74, 0x65, 0x70, 0x32, 0x32, 0x34, 0x2d, 0x32, 0x30, 0x32, 0x30, 0x2f, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 }
 
 var (
@@ -328,30 +1206,49 @@ func file_github_com_googleinterns_step224_2020_config_proto_probe_proto_rawDesc
 	return file_github_com_googleinterns_step224_2020_config_proto_probe_proto_rawDescData
 }
 
-var file_github_com_googleinterns_step224_2020_config_proto_probe_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_googleinterns_step224_2020_config_proto_probe_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_github_com_googleinterns_step224_2020_config_proto_probe_proto_goTypes = []interface{}{
-	(HermesProbeDef_TargetSystem)(0), // 0: hermes.HermesProbeDef.TargetSystem
-	(*HermesProbeDef)(nil),           // 1: hermes.HermesProbeDef
-	(*Target)(nil),                   // 2: hermes.Target
-	(*proto1.Dist)(nil),              // 3: cloudprober.metrics.Dist
-	(*proto2.AdditionalLabel)(nil),   // 4: cloudprober.probes.AdditionalLabel
-	(*proto2.ProbeDef)(nil),          // 5: cloudprober.probes.ProbeDef
+	(RetryPolicy_ErrorClass)(0),      // 0: hermes.RetryPolicy.ErrorClass
+	(HermesProbeDef_TargetSystem)(0), // 1: hermes.HermesProbeDef.TargetSystem
+	(*LeaseConfig)(nil),              // 2: hermes.LeaseConfig
+	(*ShardConfig)(nil),              // 3: hermes.ShardConfig
+	(*RetryPolicy)(nil),              // 4: hermes.RetryPolicy
+	(*SLOConfig)(nil),                // 5: hermes.SLOConfig
+	(*AlertRule)(nil),                // 6: hermes.AlertRule
+	(*AlertingConfig)(nil),           // 7: hermes.AlertingConfig
+	(*HistoryConfig)(nil),            // 8: hermes.HistoryConfig
+	(*HermesProbeDef)(nil),           // 9: hermes.HermesProbeDef
+	(*Target)(nil),                   // 10: hermes.Target
+	(*proto1.Dist)(nil),              // 11: cloudprober.metrics.Dist
+	(*proto2.AdditionalLabel)(nil),   // 12: cloudprober.probes.AdditionalLabel
+	(*MaintenanceWindow)(nil),        // 13: hermes.MaintenanceWindow
+	(*proto2.ProbeDef)(nil),          // 14: cloudprober.probes.ProbeDef
 }
 var file_github_com_googleinterns_step224_2020_config_proto_probe_proto_depIdxs = []int32{
-	2, // 0: hermes.HermesProbeDef.targets:type_name -> hermes.Target
-	0, // 1: hermes.HermesProbeDef.target_system:type_name -> hermes.HermesProbeDef.TargetSystem
-	3, // 2: hermes.HermesProbeDef.probe_latency_distribution:type_name -> cloudprober.metrics.Dist
-	3, // 3: hermes.HermesProbeDef.api_call_latency_distribution:type_name -> cloudprober.metrics.Dist
-	4, // 4: hermes.HermesProbeDef.probe_latency_additional_label:type_name -> cloudprober.probes.AdditionalLabel
-	4, // 5: hermes.HermesProbeDef.api_call_latency_additional_label:type_name -> cloudprober.probes.AdditionalLabel
-	5, // 6: hermes.HermesProbeDef.hermes_probe_def:extendee -> cloudprober.probes.ProbeDef
-	1, // 7: hermes.HermesProbeDef.hermes_probe_def:type_name -> hermes.HermesProbeDef
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	7, // [7:8] is the sub-list for extension type_name
-	6, // [6:7] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	0,  // 0: hermes.RetryPolicy.retryable_errors:type_name -> hermes.RetryPolicy.ErrorClass
+	6,  // 1: hermes.AlertingConfig.rules:type_name -> hermes.AlertRule
+	10, // 2: hermes.HermesProbeDef.targets:type_name -> hermes.Target
+	1,  // 3: hermes.HermesProbeDef.target_system:type_name -> hermes.HermesProbeDef.TargetSystem
+	11, // 4: hermes.HermesProbeDef.probe_latency_distribution:type_name -> cloudprober.metrics.Dist
+	11, // 5: hermes.HermesProbeDef.api_call_latency_distribution:type_name -> cloudprober.metrics.Dist
+	12, // 6: hermes.HermesProbeDef.probe_latency_additional_label:type_name -> cloudprober.probes.AdditionalLabel
+	12, // 7: hermes.HermesProbeDef.api_call_latency_additional_label:type_name -> cloudprober.probes.AdditionalLabel
+	11, // 8: hermes.HermesProbeDef.consistency_lag_distribution:type_name -> cloudprober.metrics.Dist
+	2,  // 9: hermes.HermesProbeDef.lease:type_name -> hermes.LeaseConfig
+	3,  // 10: hermes.HermesProbeDef.shard:type_name -> hermes.ShardConfig
+	13, // 11: hermes.HermesProbeDef.maintenance_windows:type_name -> hermes.MaintenanceWindow
+	4,  // 12: hermes.HermesProbeDef.retry_policies:type_name -> hermes.RetryPolicy
+	5,  // 13: hermes.HermesProbeDef.slo:type_name -> hermes.SLOConfig
+	7,  // 14: hermes.HermesProbeDef.alerting:type_name -> hermes.AlertingConfig
+	8,  // 15: hermes.HermesProbeDef.history:type_name -> hermes.HistoryConfig
+	14, // 16: hermes.HermesProbeDef.hermes_probe_def:extendee -> cloudprober.probes.ProbeDef
+	9,  // 17: hermes.HermesProbeDef.hermes_probe_def:type_name -> hermes.HermesProbeDef
+	18, // [18:18] is the sub-list for method output_type
+	18, // [18:18] is the sub-list for method input_type
+	17, // [17:18] is the sub-list for extension type_name
+	16, // [16:17] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_github_com_googleinterns_step224_2020_config_proto_probe_proto_init() }
@@ -362,6 +1259,90 @@ func file_github_com_googleinterns_step224_2020_config_proto_probe_proto_init()
 	file_github_com_googleinterns_step224_2020_config_proto_targets_proto_init()
 	if !protoimpl.UnsafeEnabled {
 		file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LeaseConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShardConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RetryPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SLOConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AlertRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AlertingConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HistoryConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_probe_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*HermesProbeDef); i {
 			case 0:
 				return &v.state
@@ -379,8 +1360,8 @@ func file_github_com_googleinterns_step224_2020_config_proto_probe_proto_init()
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_github_com_googleinterns_step224_2020_config_proto_probe_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   1,
+			NumEnums:      2,
+			NumMessages:   8,
 			NumExtensions: 1,
 			NumServices:   0,
 		},
@@ -14,7 +14,7 @@
 //
 // Author: Evan Spendlove, GitHub: @evanSpendlove.
 //
-//  This proto defines the service-level config for Hermes.
+//  Service defines the service-level config for Hermes.
 //  This is also the external service API for Hermes.
 
 // Code generated by protoc-gen-go. DO NOT EDIT.
@@ -264,6 +264,8 @@ type ListMonitoredSystemsResponse struct {
 
 	// TODO(#29) Add exit status to probe response.
 	Targets []*Target `protobuf:"bytes,1,rep,name=targets,proto3" json:"targets,omitempty"`
+	// The shard owning each target, in the same order as the targets.
+	MonitoredTargets []*MonitoredTarget `protobuf:"bytes,2,rep,name=monitored_targets,json=monitoredTargets,proto3" json:"monitored_targets,omitempty"`
 }
 
 func (x *ListMonitoredSystemsResponse) Reset() {
@@ -305,6 +307,1113 @@ func (x *ListMonitoredSystemsResponse) GetTargets() []*Target {
 	return nil
 }
 
+func (x *ListMonitoredSystemsResponse) GetMonitoredTargets() []*MonitoredTarget {
+	if x != nil {
+		return x.MonitoredTargets
+	}
+	return nil
+}
+
+// MonitoredTarget holds a target and the shard owning it.
+type MonitoredTarget struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Target *Target `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	// The Hermes instance that probes the target.
+	// Empty if the targets are not sharded, in which case every instance probes the target.
+	Shard string `protobuf:"bytes,2,opt,name=shard,proto3" json:"shard,omitempty"`
+	// Whether the target is probed by the instance that answered the request.
+	Local bool `protobuf:"varint,3,opt,name=local,proto3" json:"local,omitempty"`
+}
+
+func (x *MonitoredTarget) Reset() {
+	*x = MonitoredTarget{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MonitoredTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MonitoredTarget) ProtoMessage() {}
+
+func (x *MonitoredTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MonitoredTarget.ProtoReflect.Descriptor instead.
+func (*MonitoredTarget) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MonitoredTarget) GetTarget() *Target {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *MonitoredTarget) GetShard() string {
+	if x != nil {
+		return x.Shard
+	}
+	return ""
+}
+
+func (x *MonitoredTarget) GetLocal() bool {
+	if x != nil {
+		return x.Local
+	}
+	return false
+}
+
+// UpdateShardMembersRequest holds the instances that joined and left the fleet.
+type UpdateShardMembersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Joined []string `protobuf:"bytes,1,rep,name=joined,proto3" json:"joined,omitempty"`
+	Left   []string `protobuf:"bytes,2,rep,name=left,proto3" json:"left,omitempty"`
+}
+
+func (x *UpdateShardMembersRequest) Reset() {
+	*x = UpdateShardMembersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateShardMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateShardMembersRequest) ProtoMessage() {}
+
+func (x *UpdateShardMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateShardMembersRequest.ProtoReflect.Descriptor instead.
+func (*UpdateShardMembersRequest) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateShardMembersRequest) GetJoined() []string {
+	if x != nil {
+		return x.Joined
+	}
+	return nil
+}
+
+func (x *UpdateShardMembersRequest) GetLeft() []string {
+	if x != nil {
+		return x.Left
+	}
+	return nil
+}
+
+// UpdateShardMembersResponse holds the members of the ring and the shard owning each target after the update.
+type UpdateShardMembersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Members          []string           `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	MonitoredTargets []*MonitoredTarget `protobuf:"bytes,2,rep,name=monitored_targets,json=monitoredTargets,proto3" json:"monitored_targets,omitempty"`
+}
+
+func (x *UpdateShardMembersResponse) Reset() {
+	*x = UpdateShardMembersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateShardMembersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateShardMembersResponse) ProtoMessage() {}
+
+func (x *UpdateShardMembersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateShardMembersResponse.ProtoReflect.Descriptor instead.
+func (*UpdateShardMembersResponse) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateShardMembersResponse) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *UpdateShardMembersResponse) GetMonitoredTargets() []*MonitoredTarget {
+	if x != nil {
+		return x.MonitoredTargets
+	}
+	return nil
+}
+
+// PauseTargetRequest holds the targets to pause and why they are paused.
+type PauseTargetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The targets to pause, identified by their name and bucket name.
+	Targets []*Target `protobuf:"bytes,1,rep,name=targets,proto3" json:"targets,omitempty"`
+	// Why the targets are paused, e.g. a link to the maintenance ticket.
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Time after which the targets resume automatically in seconds.
+	// Default = 0, which pauses the targets until ResumeTarget is called.
+	DurationSec int64 `protobuf:"varint,3,opt,name=duration_sec,json=durationSec,proto3" json:"duration_sec,omitempty"`
+}
+
+func (x *PauseTargetRequest) Reset() {
+	*x = PauseTargetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PauseTargetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseTargetRequest) ProtoMessage() {}
+
+func (x *PauseTargetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseTargetRequest.ProtoReflect.Descriptor instead.
+func (*PauseTargetRequest) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PauseTargetRequest) GetTargets() []*Target {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *PauseTargetRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PauseTargetRequest) GetDurationSec() int64 {
+	if x != nil {
+		return x.DurationSec
+	}
+	return 0
+}
+
+// PauseTargetResponse holds the maintenance state of the targets after they were paused.
+type PauseTargetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Targets []*TargetMaintenanceState `protobuf:"bytes,1,rep,name=targets,proto3" json:"targets,omitempty"`
+}
+
+func (x *PauseTargetResponse) Reset() {
+	*x = PauseTargetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PauseTargetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseTargetResponse) ProtoMessage() {}
+
+func (x *PauseTargetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseTargetResponse.ProtoReflect.Descriptor instead.
+func (*PauseTargetResponse) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PauseTargetResponse) GetTargets() []*TargetMaintenanceState {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+// ResumeTargetRequest holds the targets to resume.
+type ResumeTargetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The targets to resume, identified by their name and bucket name.
+	Targets []*Target `protobuf:"bytes,1,rep,name=targets,proto3" json:"targets,omitempty"`
+}
+
+func (x *ResumeTargetRequest) Reset() {
+	*x = ResumeTargetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResumeTargetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeTargetRequest) ProtoMessage() {}
+
+func (x *ResumeTargetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeTargetRequest.ProtoReflect.Descriptor instead.
+func (*ResumeTargetRequest) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ResumeTargetRequest) GetTargets() []*Target {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+// ResumeTargetResponse holds the maintenance state of the targets after they were resumed.
+type ResumeTargetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Targets []*TargetMaintenanceState `protobuf:"bytes,1,rep,name=targets,proto3" json:"targets,omitempty"`
+}
+
+func (x *ResumeTargetResponse) Reset() {
+	*x = ResumeTargetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResumeTargetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeTargetResponse) ProtoMessage() {}
+
+func (x *ResumeTargetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeTargetResponse.ProtoReflect.Descriptor instead.
+func (*ResumeTargetResponse) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ResumeTargetResponse) GetTargets() []*TargetMaintenanceState {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+// TargetMaintenanceState holds whether a target is paused or in a maintenance window.
+type TargetMaintenanceState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Target *Target `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	// Whether the target is paused by PauseTarget.
+	Paused bool `protobuf:"varint,2,opt,name=paused,proto3" json:"paused,omitempty"`
+	// The reason given to PauseTarget.
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Time at which the target resumes automatically in seconds since the Unix epoch.
+	// 0 if the target is not paused or paused until ResumeTarget is called.
+	ResumeUnixSec int64 `protobuf:"varint,4,opt,name=resume_unix_sec,json=resumeUnixSec,proto3" json:"resume_unix_sec,omitempty"`
+	// Whether the target is in one of its configured maintenance windows.
+	// Resuming a target does not end its maintenance windows.
+	InMaintenanceWindow bool `protobuf:"varint,5,opt,name=in_maintenance_window,json=inMaintenanceWindow,proto3" json:"in_maintenance_window,omitempty"`
+}
+
+func (x *TargetMaintenanceState) Reset() {
+	*x = TargetMaintenanceState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TargetMaintenanceState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TargetMaintenanceState) ProtoMessage() {}
+
+func (x *TargetMaintenanceState) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TargetMaintenanceState.ProtoReflect.Descriptor instead.
+func (*TargetMaintenanceState) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TargetMaintenanceState) GetTarget() *Target {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *TargetMaintenanceState) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *TargetMaintenanceState) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *TargetMaintenanceState) GetResumeUnixSec() int64 {
+	if x != nil {
+		return x.ResumeUnixSec
+	}
+	return 0
+}
+
+func (x *TargetMaintenanceState) GetInMaintenanceWindow() bool {
+	if x != nil {
+		return x.InMaintenanceWindow
+	}
+	return false
+}
+
+// RunProbeRequest holds the target to probe.
+type RunProbeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The target to probe, identified by its name and bucket name.
+	Target *Target `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+}
+
+func (x *RunProbeRequest) Reset() {
+	*x = RunProbeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunProbeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunProbeRequest) ProtoMessage() {}
+
+func (x *RunProbeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunProbeRequest.ProtoReflect.Descriptor instead.
+func (*RunProbeRequest) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RunProbeRequest) GetTarget() *Target {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+// RunProbeResult holds the result of an operation of a probe run.
+type RunProbeResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The operation, e.g. check_nil, or total_probe_run for the whole run.
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	// The exit status of the operation, e.g. success or file_missing.
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// The error the operation failed with, empty on success.
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// Time taken by the operation in seconds.
+	LatencySec float64 `protobuf:"fixed64,4,opt,name=latency_sec,json=latencySec,proto3" json:"latency_sec,omitempty"`
+	// Whether this is the result of the whole run, which is the last result streamed.
+	Final bool `protobuf:"varint,5,opt,name=final,proto3" json:"final,omitempty"`
+}
+
+func (x *RunProbeResult) Reset() {
+	*x = RunProbeResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunProbeResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunProbeResult) ProtoMessage() {}
+
+func (x *RunProbeResult) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunProbeResult.ProtoReflect.Descriptor instead.
+func (*RunProbeResult) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RunProbeResult) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *RunProbeResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RunProbeResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *RunProbeResult) GetLatencySec() float64 {
+	if x != nil {
+		return x.LatencySec
+	}
+	return 0
+}
+
+func (x *RunProbeResult) GetFinal() bool {
+	if x != nil {
+		return x.Final
+	}
+	return false
+}
+
+// GetTargetSLORequest holds the targets to return the service level indicators of.
+type GetTargetSLORequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The targets, identified by their name and bucket name.
+	// If empty, the indicators of every target probed by this instance are returned.
+	Targets []*Target `protobuf:"bytes,1,rep,name=targets,proto3" json:"targets,omitempty"`
+}
+
+func (x *GetTargetSLORequest) Reset() {
+	*x = GetTargetSLORequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTargetSLORequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTargetSLORequest) ProtoMessage() {}
+
+func (x *GetTargetSLORequest) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTargetSLORequest.ProtoReflect.Descriptor instead.
+func (*GetTargetSLORequest) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetTargetSLORequest) GetTargets() []*Target {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+// GetTargetSLOResponse holds the service level indicators of the targets.
+type GetTargetSLOResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Targets []*TargetSLO `protobuf:"bytes,1,rep,name=targets,proto3" json:"targets,omitempty"`
+}
+
+func (x *GetTargetSLOResponse) Reset() {
+	*x = GetTargetSLOResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTargetSLOResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTargetSLOResponse) ProtoMessage() {}
+
+func (x *GetTargetSLOResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTargetSLOResponse.ProtoReflect.Descriptor instead.
+func (*GetTargetSLOResponse) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetTargetSLOResponse) GetTargets() []*TargetSLO {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+// TargetSLO holds the service level indicators of a target, one per rolling window.
+type TargetSLO struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Target     *Target `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	Indicators []*SLI  `protobuf:"bytes,2,rep,name=indicators,proto3" json:"indicators,omitempty"`
+}
+
+func (x *TargetSLO) Reset() {
+	*x = TargetSLO{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TargetSLO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TargetSLO) ProtoMessage() {}
+
+func (x *TargetSLO) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TargetSLO.ProtoReflect.Descriptor instead.
+func (*TargetSLO) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *TargetSLO) GetTarget() *Target {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *TargetSLO) GetIndicators() []*SLI {
+	if x != nil {
+		return x.Indicators
+	}
+	return nil
+}
+
+// SLI holds the service level indicators of a target over a rolling window.
+type SLI struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Length of the rolling window in seconds.
+	WindowSec int64 `protobuf:"varint,1,opt,name=window_sec,json=windowSec,proto3" json:"window_sec,omitempty"`
+	// Number of probe runs in the window.
+	Runs int64 `protobuf:"varint,2,opt,name=runs,proto3" json:"runs,omitempty"`
+	// Ratio of the runs that succeeded, 1 if there were no runs.
+	SuccessRatio float64 `protobuf:"fixed64,3,opt,name=success_ratio,json=successRatio,proto3" json:"success_ratio,omitempty"`
+	// Ratio of the runs that succeeded within the latency threshold, 1 if there were no runs.
+	LatencyRatio float64 `protobuf:"fixed64,4,opt,name=latency_ratio,json=latencyRatio,proto3" json:"latency_ratio,omitempty"`
+	// Fraction of the error budget of the availability target left in the window.
+	// Negative once the budget is exhausted.
+	ErrorBudgetRemaining float64 `protobuf:"fixed64,5,opt,name=error_budget_remaining,json=errorBudgetRemaining,proto3" json:"error_budget_remaining,omitempty"`
+	// Fraction of the error budget of the latency target left in the window.
+	// Negative once the budget is exhausted.
+	LatencyBudgetRemaining float64 `protobuf:"fixed64,6,opt,name=latency_budget_remaining,json=latencyBudgetRemaining,proto3" json:"latency_budget_remaining,omitempty"`
+}
+
+func (x *SLI) Reset() {
+	*x = SLI{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SLI) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SLI) ProtoMessage() {}
+
+func (x *SLI) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SLI.ProtoReflect.Descriptor instead.
+func (*SLI) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SLI) GetWindowSec() int64 {
+	if x != nil {
+		return x.WindowSec
+	}
+	return 0
+}
+
+func (x *SLI) GetRuns() int64 {
+	if x != nil {
+		return x.Runs
+	}
+	return 0
+}
+
+func (x *SLI) GetSuccessRatio() float64 {
+	if x != nil {
+		return x.SuccessRatio
+	}
+	return 0
+}
+
+func (x *SLI) GetLatencyRatio() float64 {
+	if x != nil {
+		return x.LatencyRatio
+	}
+	return 0
+}
+
+func (x *SLI) GetErrorBudgetRemaining() float64 {
+	if x != nil {
+		return x.ErrorBudgetRemaining
+	}
+	return 0
+}
+
+func (x *SLI) GetLatencyBudgetRemaining() float64 {
+	if x != nil {
+		return x.LatencyBudgetRemaining
+	}
+	return 0
+}
+
+// RunRecord is the structured result of a probe run of a target, as recorded
+// in the run history.
+type RunRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Target *Target `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	// Times the run started and ended in milliseconds since the Unix epoch.
+	StartUnixMs int64 `protobuf:"varint,2,opt,name=start_unix_ms,json=startUnixMs,proto3" json:"start_unix_ms,omitempty"`
+	EndUnixMs   int64 `protobuf:"varint,3,opt,name=end_unix_ms,json=endUnixMs,proto3" json:"end_unix_ms,omitempty"`
+	// The exit status recorded for the run, e.g. success, or maintenance for a
+	// run during maintenance.
+	Status string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	// The error the run failed with, empty on success.
+	Error string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	// The result of every operation of the run, in the order they ran.
+	Operations []*RunProbeResult `protobuf:"bytes,6,rep,name=operations,proto3" json:"operations,omitempty"`
+	// Names of the objects the run made API calls on, sorted.
+	Files []string `protobuf:"bytes,7,rep,name=files,proto3" json:"files,omitempty"`
+	// Whether the run was triggered by the RunProbe RPC. On-demand runs do not
+	// count towards the service level indicators of the target.
+	OnDemand bool `protobuf:"varint,8,opt,name=on_demand,json=onDemand,proto3" json:"on_demand,omitempty"`
+}
+
+func (x *RunRecord) Reset() {
+	*x = RunRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunRecord) ProtoMessage() {}
+
+func (x *RunRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunRecord.ProtoReflect.Descriptor instead.
+func (*RunRecord) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RunRecord) GetTarget() *Target {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *RunRecord) GetStartUnixMs() int64 {
+	if x != nil {
+		return x.StartUnixMs
+	}
+	return 0
+}
+
+func (x *RunRecord) GetEndUnixMs() int64 {
+	if x != nil {
+		return x.EndUnixMs
+	}
+	return 0
+}
+
+func (x *RunRecord) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RunRecord) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *RunRecord) GetOperations() []*RunProbeResult {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *RunRecord) GetFiles() []string {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *RunRecord) GetOnDemand() bool {
+	if x != nil {
+		return x.OnDemand
+	}
+	return false
+}
+
+// GetRunHistoryRequest holds the targets and the time range to return the probe runs of.
+type GetRunHistoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The targets, identified by their name and bucket name.
+	// If empty, the runs of every target of the probe are returned.
+	Targets []*Target `protobuf:"bytes,1,rep,name=targets,proto3" json:"targets,omitempty"`
+	// The runs that started within [start_unix_sec, end_unix_sec) are returned.
+	// An end of 0 is the current time.
+	StartUnixSec int64 `protobuf:"varint,2,opt,name=start_unix_sec,json=startUnixSec,proto3" json:"start_unix_sec,omitempty"`
+	EndUnixSec   int64 `protobuf:"varint,3,opt,name=end_unix_sec,json=endUnixSec,proto3" json:"end_unix_sec,omitempty"`
+	// Maximum number of runs returned, the latest ones. 0 returns every run.
+	Limit int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *GetRunHistoryRequest) Reset() {
+	*x = GetRunHistoryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRunHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRunHistoryRequest) ProtoMessage() {}
+
+func (x *GetRunHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRunHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetRunHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetRunHistoryRequest) GetTargets() []*Target {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *GetRunHistoryRequest) GetStartUnixSec() int64 {
+	if x != nil {
+		return x.StartUnixSec
+	}
+	return 0
+}
+
+func (x *GetRunHistoryRequest) GetEndUnixSec() int64 {
+	if x != nil {
+		return x.EndUnixSec
+	}
+	return 0
+}
+
+func (x *GetRunHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// GetRunHistoryResponse holds the probe runs, ordered by start time.
+type GetRunHistoryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Runs []*RunRecord `protobuf:"bytes,1,rep,name=runs,proto3" json:"runs,omitempty"`
+}
+
+func (x *GetRunHistoryResponse) Reset() {
+	*x = GetRunHistoryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRunHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRunHistoryResponse) ProtoMessage() {}
+
+func (x *GetRunHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRunHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetRunHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetRunHistoryResponse) GetRuns() []*RunRecord {
+	if x != nil {
+		return x.Runs
+	}
+	return nil
+}
+
 var File_github_com_googleinterns_step224_2020_config_proto_service_proto protoreflect.FileDescriptor
 
 var file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDesc = []byte{
@@ -335,36 +1444,197 @@ var file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDes
 	0x1c, 0x53, 0x74, 0x6f, 0x70, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x53,
 	0x79, 0x73, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1d, 0x0a,
 	0x1b, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x53, 0x79,
-	0x73, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x48, 0x0a, 0x1c,
-	0x4c, 0x69, 0x73, 0x74, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x53, 0x79, 0x73,
-	0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x07,
-	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
-	0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x07, 0x74,
-	0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x32, 0xbb, 0x02, 0x0a, 0x06, 0x48, 0x65, 0x72, 0x6d, 0x65,
-	0x73, 0x12, 0x59, 0x0a, 0x1c, 0x53, 0x74, 0x61, 0x72, 0x74, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f,
-	0x72, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x53, 0x79, 0x73, 0x74, 0x65,
-	0x6d, 0x12, 0x1a, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x48, 0x65, 0x72, 0x6d, 0x65,
-	0x73, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e,
-	0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x48, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x50, 0x72, 0x6f,
-	0x62, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x6a, 0x0a, 0x1b,
-	0x53, 0x74, 0x6f, 0x70, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x53, 0x74,
-	0x6f, 0x72, 0x61, 0x67, 0x65, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x23, 0x2e, 0x68, 0x65,
-	0x72, 0x6d, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72,
-	0x69, 0x6e, 0x67, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x24, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x4d, 0x6f,
+	0x73, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x8e, 0x01, 0x0a,
+	0x1c, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x53, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a,
+	0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x07,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x44, 0x0a, 0x11, 0x6d, 0x6f, 0x6e, 0x69, 0x74,
+	0x6f, 0x72, 0x65, 0x64, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x4d, 0x6f, 0x6e, 0x69,
+	0x74, 0x6f, 0x72, 0x65, 0x64, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x10, 0x6d, 0x6f, 0x6e,
+	0x69, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x22, 0x65, 0x0a,
+	0x0f, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x12, 0x26, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0e, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x61, 0x72,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x68, 0x61, 0x72, 0x64, 0x12, 0x14,
+	0x0a, 0x05, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x6c,
+	0x6f, 0x63, 0x61, 0x6c, 0x22, 0x47, 0x0a, 0x19, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x68,
+	0x61, 0x72, 0x64, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x6a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x06, 0x6a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x65, 0x66,
+	0x74, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x65, 0x66, 0x74, 0x22, 0x7c, 0x0a,
+	0x1a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x68, 0x61, 0x72, 0x64, 0x4d, 0x65, 0x6d, 0x62,
+	0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d,
+	0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65,
+	0x6d, 0x62, 0x65, 0x72, 0x73, 0x12, 0x44, 0x0a, 0x11, 0x6d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72,
+	0x65, 0x64, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f,
+	0x72, 0x65, 0x64, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x10, 0x6d, 0x6f, 0x6e, 0x69, 0x74,
+	0x6f, 0x72, 0x65, 0x64, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x22, 0x79, 0x0a, 0x12, 0x50,
+	0x61, 0x75, 0x73, 0x65, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x28, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x73, 0x65, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x64, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x53, 0x65, 0x63, 0x22, 0x4f, 0x0a, 0x13, 0x50, 0x61, 0x75, 0x73, 0x65, 0x54,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a,
+	0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e,
+	0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x4d, 0x61,
+	0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x07,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x22, 0x3f, 0x0a, 0x13, 0x52, 0x65, 0x73, 0x75, 0x6d,
+	0x65, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28,
+	0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52,
+	0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x22, 0x50, 0x0a, 0x14, 0x52, 0x65, 0x73, 0x75,
+	0x6d, 0x65, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x38, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1e, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x22, 0xcc, 0x01, 0x0a, 0x16, 0x54,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x26, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70,
+	0x61, 0x75, 0x73, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x26, 0x0a,
+	0x0f, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x73, 0x65, 0x63,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x55, 0x6e,
+	0x69, 0x78, 0x53, 0x65, 0x63, 0x12, 0x32, 0x0a, 0x15, 0x69, 0x6e, 0x5f, 0x6d, 0x61, 0x69, 0x6e,
+	0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x13, 0x69, 0x6e, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61,
+	0x6e, 0x63, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x22, 0x39, 0x0a, 0x0f, 0x52, 0x75, 0x6e,
+	0x50, 0x72, 0x6f, 0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x06,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x68,
+	0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x06, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x22, 0x93, 0x01, 0x0a, 0x0e, 0x52, 0x75, 0x6e, 0x50, 0x72, 0x6f, 0x62,
+	0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x73,
+	0x65, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63,
+	0x79, 0x53, 0x65, 0x63, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x22, 0x3f, 0x0a, 0x13, 0x47, 0x65,
+	0x74, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x53, 0x4c, 0x4f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x28, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x22, 0x43, 0x0a, 0x14, 0x47,
+	0x65, 0x74, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x53, 0x4c, 0x4f, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x53, 0x4c, 0x4f, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73,
+	0x22, 0x60, 0x0a, 0x09, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x53, 0x4c, 0x4f, 0x12, 0x26, 0x0a,
+	0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x06, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x2b, 0x0a, 0x0a, 0x69, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74,
+	0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x68, 0x65, 0x72, 0x6d,
+	0x65, 0x73, 0x2e, 0x53, 0x4c, 0x49, 0x52, 0x0a, 0x69, 0x6e, 0x64, 0x69, 0x63, 0x61, 0x74, 0x6f,
+	0x72, 0x73, 0x22, 0xf2, 0x01, 0x0a, 0x03, 0x53, 0x4c, 0x49, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x69,
+	0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x53, 0x65, 0x63, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x75, 0x6e,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x72, 0x75, 0x6e, 0x73, 0x12, 0x23, 0x0a,
+	0x0d, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x52, 0x61, 0x74,
+	0x69, 0x6f, 0x12, 0x23, 0x0a, 0x0d, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x6c, 0x61, 0x74, 0x65, 0x6e,
+	0x63, 0x79, 0x52, 0x61, 0x74, 0x69, 0x6f, 0x12, 0x34, 0x0a, 0x16, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x5f, 0x62, 0x75, 0x64, 0x67, 0x65, 0x74, 0x5f, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e,
+	0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x14, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x42, 0x75,
+	0x64, 0x67, 0x65, 0x74, 0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x38, 0x0a,
+	0x18, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x62, 0x75, 0x64, 0x67, 0x65, 0x74, 0x5f,
+	0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x16, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74, 0x52, 0x65,
+	0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x22, 0x90, 0x02, 0x0a, 0x09, 0x52, 0x75, 0x6e, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x26, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x22, 0x0a,
+	0x0d, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6d, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x73, 0x74, 0x61, 0x72, 0x74, 0x55, 0x6e, 0x69, 0x78, 0x4d,
+	0x73, 0x12, 0x1e, 0x0a, 0x0b, 0x65, 0x6e, 0x64, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6d, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x65, 0x6e, 0x64, 0x55, 0x6e, 0x69, 0x78, 0x4d,
+	0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12,
+	0x36, 0x0a, 0x0a, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x52, 0x75, 0x6e,
+	0x50, 0x72, 0x6f, 0x62, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x0a, 0x6f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73,
+	0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1b, 0x0a,
+	0x09, 0x6f, 0x6e, 0x5f, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x08, 0x6f, 0x6e, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x22, 0x9e, 0x01, 0x0a, 0x14, 0x47,
+	0x65, 0x74, 0x52, 0x75, 0x6e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x12, 0x24, 0x0a,
+	0x0e, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x73, 0x65, 0x63, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x73, 0x74, 0x61, 0x72, 0x74, 0x55, 0x6e, 0x69, 0x78,
+	0x53, 0x65, 0x63, 0x12, 0x20, 0x0a, 0x0c, 0x65, 0x6e, 0x64, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f,
+	0x73, 0x65, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x65, 0x6e, 0x64, 0x55, 0x6e,
+	0x69, 0x78, 0x53, 0x65, 0x63, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x3e, 0x0a, 0x15, 0x47,
+	0x65, 0x74, 0x52, 0x75, 0x6e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a, 0x04, 0x72, 0x75, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x52, 0x75, 0x6e, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x04, 0x72, 0x75, 0x6e, 0x73, 0x32, 0x8f, 0x06, 0x0a, 0x06,
+	0x48, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x12, 0x59, 0x0a, 0x1c, 0x53, 0x74, 0x61, 0x72, 0x74, 0x4d,
+	0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65,
+	0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x1a, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e,
+	0x48, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x48, 0x65, 0x72, 0x6d,
+	0x65, 0x73, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x6a, 0x0a, 0x1b, 0x53, 0x74, 0x6f, 0x70, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72,
+	0x69, 0x6e, 0x67, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d,
+	0x12, 0x23, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x4d, 0x6f,
 	0x6e, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x6a, 0x0a, 0x1b, 0x4c, 0x69, 0x73, 0x74,
-	0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65,
-	0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x23, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73,
-	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x53, 0x79,
-	0x73, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x68,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x53,
+	0x74, 0x6f, 0x70, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x53, 0x79, 0x73,
+	0x74, 0x65, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x6a, 0x0a,
+	0x1b, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x53, 0x74,
+	0x6f, 0x72, 0x61, 0x67, 0x65, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x23, 0x2e, 0x68,
 	0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x6f, 0x6e, 0x69, 0x74, 0x6f,
-	0x72, 0x65, 0x64, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x00, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
-	0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x73,
-	0x2f, 0x73, 0x74, 0x65, 0x70, 0x32, 0x32, 0x34, 0x2d, 0x32, 0x30, 0x32, 0x30, 0x2f, 0x63, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x72, 0x65, 0x64, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x24, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4d,
+	0x6f, 0x6e, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5d, 0x0a, 0x12, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x53, 0x68, 0x61, 0x72, 0x64, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x12,
+	0x21, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53,
+	0x68, 0x61, 0x72, 0x64, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x22, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x53, 0x68, 0x61, 0x72, 0x64, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x48, 0x0a, 0x0b, 0x50, 0x61, 0x75, 0x73,
+	0x65, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x1a, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73,
+	0x2e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x50, 0x61, 0x75,
+	0x73, 0x65, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x4b, 0x0a, 0x0c, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x54, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x12, 0x1b, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x75,
+	0x6d, 0x65, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1c, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x54,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x3f, 0x0a, 0x08, 0x52, 0x75, 0x6e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x17, 0x2e, 0x68, 0x65,
+	0x72, 0x6d, 0x65, 0x73, 0x2e, 0x52, 0x75, 0x6e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x52, 0x75,
+	0x6e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x00, 0x30, 0x01,
+	0x12, 0x4b, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x53, 0x4c, 0x4f,
+	0x12, 0x1b, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x53, 0x4c, 0x4f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e,
+	0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x53, 0x4c, 0x4f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4e, 0x0a,
+	0x0d, 0x47, 0x65, 0x74, 0x52, 0x75, 0x6e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1c,
+	0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x75, 0x6e, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x68,
+	0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x75, 0x6e, 0x48, 0x69, 0x73, 0x74,
+	0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x34, 0x5a,
+	0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x73, 0x2f, 0x73, 0x74, 0x65, 0x70, 0x32, 0x32,
+	0x34, 0x2d, 0x32, 0x30, 0x32, 0x30, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -379,7 +1649,7 @@ func file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDe
 	return file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDescData
 }
 
-var file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_github_com_googleinterns_step224_2020_config_proto_service_proto_goTypes = []interface{}{
 	(*HermesProbeRequest)(nil),           // 0: hermes.HermesProbeRequest
 	(*HermesProbeResponse)(nil),          // 1: hermes.HermesProbeResponse
@@ -387,24 +1657,70 @@ var file_github_com_googleinterns_step224_2020_config_proto_service_proto_goType
 	(*StopMonitoringSystemResponse)(nil), // 3: hermes.StopMonitoringSystemResponse
 	(*ListMonitoredSystemsRequest)(nil),  // 4: hermes.ListMonitoredSystemsRequest
 	(*ListMonitoredSystemsResponse)(nil), // 5: hermes.ListMonitoredSystemsResponse
-	(*HermesProbeDef)(nil),               // 6: hermes.HermesProbeDef
-	(*Target)(nil),                       // 7: hermes.Target
+	(*MonitoredTarget)(nil),              // 6: hermes.MonitoredTarget
+	(*UpdateShardMembersRequest)(nil),    // 7: hermes.UpdateShardMembersRequest
+	(*UpdateShardMembersResponse)(nil),   // 8: hermes.UpdateShardMembersResponse
+	(*PauseTargetRequest)(nil),           // 9: hermes.PauseTargetRequest
+	(*PauseTargetResponse)(nil),          // 10: hermes.PauseTargetResponse
+	(*ResumeTargetRequest)(nil),          // 11: hermes.ResumeTargetRequest
+	(*ResumeTargetResponse)(nil),         // 12: hermes.ResumeTargetResponse
+	(*TargetMaintenanceState)(nil),       // 13: hermes.TargetMaintenanceState
+	(*RunProbeRequest)(nil),              // 14: hermes.RunProbeRequest
+	(*RunProbeResult)(nil),               // 15: hermes.RunProbeResult
+	(*GetTargetSLORequest)(nil),          // 16: hermes.GetTargetSLORequest
+	(*GetTargetSLOResponse)(nil),         // 17: hermes.GetTargetSLOResponse
+	(*TargetSLO)(nil),                    // 18: hermes.TargetSLO
+	(*SLI)(nil),                          // 19: hermes.SLI
+	(*RunRecord)(nil),                    // 20: hermes.RunRecord
+	(*GetRunHistoryRequest)(nil),         // 21: hermes.GetRunHistoryRequest
+	(*GetRunHistoryResponse)(nil),        // 22: hermes.GetRunHistoryResponse
+	(*HermesProbeDef)(nil),               // 23: hermes.HermesProbeDef
+	(*Target)(nil),                       // 24: hermes.Target
 }
 var file_github_com_googleinterns_step224_2020_config_proto_service_proto_depIdxs = []int32{
-	6, // 0: hermes.HermesProbeRequest.probe_config:type_name -> hermes.HermesProbeDef
-	7, // 1: hermes.StopMonitoringSystemRequest.targets:type_name -> hermes.Target
-	7, // 2: hermes.ListMonitoredSystemsResponse.targets:type_name -> hermes.Target
-	0, // 3: hermes.Hermes.StartMonitoringStorageSystem:input_type -> hermes.HermesProbeRequest
-	2, // 4: hermes.Hermes.StopMonitoringStorageSystem:input_type -> hermes.StopMonitoringSystemRequest
-	4, // 5: hermes.Hermes.ListMonitoredStorageSystems:input_type -> hermes.ListMonitoredSystemsRequest
-	1, // 6: hermes.Hermes.StartMonitoringStorageSystem:output_type -> hermes.HermesProbeResponse
-	3, // 7: hermes.Hermes.StopMonitoringStorageSystem:output_type -> hermes.StopMonitoringSystemResponse
-	5, // 8: hermes.Hermes.ListMonitoredStorageSystems:output_type -> hermes.ListMonitoredSystemsResponse
-	6, // [6:9] is the sub-list for method output_type
-	3, // [3:6] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	23, // 0: hermes.HermesProbeRequest.probe_config:type_name -> hermes.HermesProbeDef
+	24, // 1: hermes.StopMonitoringSystemRequest.targets:type_name -> hermes.Target
+	24, // 2: hermes.ListMonitoredSystemsResponse.targets:type_name -> hermes.Target
+	6,  // 3: hermes.ListMonitoredSystemsResponse.monitored_targets:type_name -> hermes.MonitoredTarget
+	24, // 4: hermes.MonitoredTarget.target:type_name -> hermes.Target
+	6,  // 5: hermes.UpdateShardMembersResponse.monitored_targets:type_name -> hermes.MonitoredTarget
+	24, // 6: hermes.PauseTargetRequest.targets:type_name -> hermes.Target
+	13, // 7: hermes.PauseTargetResponse.targets:type_name -> hermes.TargetMaintenanceState
+	24, // 8: hermes.ResumeTargetRequest.targets:type_name -> hermes.Target
+	13, // 9: hermes.ResumeTargetResponse.targets:type_name -> hermes.TargetMaintenanceState
+	24, // 10: hermes.TargetMaintenanceState.target:type_name -> hermes.Target
+	24, // 11: hermes.RunProbeRequest.target:type_name -> hermes.Target
+	24, // 12: hermes.GetTargetSLORequest.targets:type_name -> hermes.Target
+	18, // 13: hermes.GetTargetSLOResponse.targets:type_name -> hermes.TargetSLO
+	24, // 14: hermes.TargetSLO.target:type_name -> hermes.Target
+	19, // 15: hermes.TargetSLO.indicators:type_name -> hermes.SLI
+	24, // 16: hermes.RunRecord.target:type_name -> hermes.Target
+	15, // 17: hermes.RunRecord.operations:type_name -> hermes.RunProbeResult
+	24, // 18: hermes.GetRunHistoryRequest.targets:type_name -> hermes.Target
+	20, // 19: hermes.GetRunHistoryResponse.runs:type_name -> hermes.RunRecord
+	0,  // 20: hermes.Hermes.StartMonitoringStorageSystem:input_type -> hermes.HermesProbeRequest
+	2,  // 21: hermes.Hermes.StopMonitoringStorageSystem:input_type -> hermes.StopMonitoringSystemRequest
+	4,  // 22: hermes.Hermes.ListMonitoredStorageSystems:input_type -> hermes.ListMonitoredSystemsRequest
+	7,  // 23: hermes.Hermes.UpdateShardMembers:input_type -> hermes.UpdateShardMembersRequest
+	9,  // 24: hermes.Hermes.PauseTarget:input_type -> hermes.PauseTargetRequest
+	11, // 25: hermes.Hermes.ResumeTarget:input_type -> hermes.ResumeTargetRequest
+	14, // 26: hermes.Hermes.RunProbe:input_type -> hermes.RunProbeRequest
+	16, // 27: hermes.Hermes.GetTargetSLO:input_type -> hermes.GetTargetSLORequest
+	21, // 28: hermes.Hermes.GetRunHistory:input_type -> hermes.GetRunHistoryRequest
+	1,  // 29: hermes.Hermes.StartMonitoringStorageSystem:output_type -> hermes.HermesProbeResponse
+	3,  // 30: hermes.Hermes.StopMonitoringStorageSystem:output_type -> hermes.StopMonitoringSystemResponse
+	5,  // 31: hermes.Hermes.ListMonitoredStorageSystems:output_type -> hermes.ListMonitoredSystemsResponse
+	8,  // 32: hermes.Hermes.UpdateShardMembers:output_type -> hermes.UpdateShardMembersResponse
+	10, // 33: hermes.Hermes.PauseTarget:output_type -> hermes.PauseTargetResponse
+	12, // 34: hermes.Hermes.ResumeTarget:output_type -> hermes.ResumeTargetResponse
+	15, // 35: hermes.Hermes.RunProbe:output_type -> hermes.RunProbeResult
+	17, // 36: hermes.Hermes.GetTargetSLO:output_type -> hermes.GetTargetSLOResponse
+	22, // 37: hermes.Hermes.GetRunHistory:output_type -> hermes.GetRunHistoryResponse
+	29, // [29:38] is the sub-list for method output_type
+	20, // [20:29] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_github_com_googleinterns_step224_2020_config_proto_service_proto_init() }
@@ -487,6 +1803,210 @@ func file_github_com_googleinterns_step224_2020_config_proto_service_proto_init(
 				return nil
 			}
 		}
+		file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MonitoredTarget); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateShardMembersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateShardMembersResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PauseTargetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PauseTargetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResumeTargetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResumeTargetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TargetMaintenanceState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunProbeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunProbeResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTargetSLORequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTargetSLOResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TargetSLO); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SLI); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRunHistoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_service_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRunHistoryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -494,7 +2014,7 @@ func file_github_com_googleinterns_step224_2020_config_proto_service_proto_init(
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_github_com_googleinterns_step224_2020_config_proto_service_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
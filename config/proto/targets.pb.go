@@ -142,6 +142,353 @@ func (Target_ConnectionType) EnumDescriptor() ([]byte, []int) {
 	return file_github_com_googleinterns_step224_2020_config_proto_targets_proto_rawDescGZIP(), []int{0, 1}
 }
 
+// ChecksumAlgorithm is the algorithm used to checksum the contents of the
+// files Hermes creates on this target. It is encoded in every filename.
+type Target_ChecksumAlgorithm int32
+
+const (
+	// Defaults to SHA1.
+	Target_CHECKSUM_ALGORITHM_UNSPECIFIED Target_ChecksumAlgorithm = 0
+	Target_SHA1                           Target_ChecksumAlgorithm = 1
+	Target_SHA256                         Target_ChecksumAlgorithm = 2
+	Target_CRC32C                         Target_ChecksumAlgorithm = 3
+	Target_MD5                            Target_ChecksumAlgorithm = 4
+)
+
+// Enum value maps for Target_ChecksumAlgorithm.
+var (
+	Target_ChecksumAlgorithm_name = map[int32]string{
+		0: "CHECKSUM_ALGORITHM_UNSPECIFIED",
+		1: "SHA1",
+		2: "SHA256",
+		3: "CRC32C",
+		4: "MD5",
+	}
+	Target_ChecksumAlgorithm_value = map[string]int32{
+		"CHECKSUM_ALGORITHM_UNSPECIFIED": 0,
+		"SHA1":                           1,
+		"SHA256":                         2,
+		"CRC32C":                         3,
+		"MD5":                            4,
+	}
+)
+
+func (x Target_ChecksumAlgorithm) Enum() *Target_ChecksumAlgorithm {
+	p := new(Target_ChecksumAlgorithm)
+	*p = x
+	return p
+}
+
+func (x Target_ChecksumAlgorithm) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Target_ChecksumAlgorithm) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_googleinterns_step224_2020_config_proto_targets_proto_enumTypes[2].Descriptor()
+}
+
+func (Target_ChecksumAlgorithm) Type() protoreflect.EnumType {
+	return &file_github_com_googleinterns_step224_2020_config_proto_targets_proto_enumTypes[2]
+}
+
+func (x Target_ChecksumAlgorithm) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Target_ChecksumAlgorithm.Descriptor instead.
+func (Target_ChecksumAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_targets_proto_rawDescGZIP(), []int{0, 2}
+}
+
+// ReadMode is the way Hermes reads files back to verify their contents.
+type Target_ReadMode int32
+
+const (
+	// Defaults to FULL.
+	Target_READ_MODE_UNSPECIFIED Target_ReadMode = 0
+	// Read each file in full and verify its checksums.
+	Target_FULL Target_ReadMode = 1
+	// Read random byte ranges of each file and verify their contents.
+	Target_RANGE Target_ReadMode = 2
+)
+
+// Enum value maps for Target_ReadMode.
+var (
+	Target_ReadMode_name = map[int32]string{
+		0: "READ_MODE_UNSPECIFIED",
+		1: "FULL",
+		2: "RANGE",
+	}
+	Target_ReadMode_value = map[string]int32{
+		"READ_MODE_UNSPECIFIED": 0,
+		"FULL":                  1,
+		"RANGE":                 2,
+	}
+)
+
+func (x Target_ReadMode) Enum() *Target_ReadMode {
+	p := new(Target_ReadMode)
+	*p = x
+	return p
+}
+
+func (x Target_ReadMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Target_ReadMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_googleinterns_step224_2020_config_proto_targets_proto_enumTypes[3].Descriptor()
+}
+
+func (Target_ReadMode) Type() protoreflect.EnumType {
+	return &file_github_com_googleinterns_step224_2020_config_proto_targets_proto_enumTypes[3]
+}
+
+func (x Target_ReadMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Target_ReadMode.Descriptor instead.
+func (Target_ReadMode) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_targets_proto_rawDescGZIP(), []int{0, 3}
+}
+
+// UploadMode is the way Hermes uploads the files it creates.
+type Target_UploadMode int32
+
+const (
+	// Defaults to SINGLE.
+	Target_UPLOAD_MODE_UNSPECIFIED Target_UploadMode = 0
+	// Upload each file in a single request.
+	Target_SINGLE Target_UploadMode = 1
+	// Upload each file in chunks using a resumable upload and probe the
+	// abort of an incomplete resumable upload.
+	// Ceph S3 targets will use multipart uploads once they are supported.
+	Target_RESUMABLE Target_UploadMode = 2
+)
+
+// Enum value maps for Target_UploadMode.
+var (
+	Target_UploadMode_name = map[int32]string{
+		0: "UPLOAD_MODE_UNSPECIFIED",
+		1: "SINGLE",
+		2: "RESUMABLE",
+	}
+	Target_UploadMode_value = map[string]int32{
+		"UPLOAD_MODE_UNSPECIFIED": 0,
+		"SINGLE":                  1,
+		"RESUMABLE":               2,
+	}
+)
+
+func (x Target_UploadMode) Enum() *Target_UploadMode {
+	p := new(Target_UploadMode)
+	*p = x
+	return p
+}
+
+func (x Target_UploadMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Target_UploadMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_googleinterns_step224_2020_config_proto_targets_proto_enumTypes[4].Descriptor()
+}
+
+func (Target_UploadMode) Type() protoreflect.EnumType {
+	return &file_github_com_googleinterns_step224_2020_config_proto_targets_proto_enumTypes[4]
+}
+
+func (x Target_UploadMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Target_UploadMode.Descriptor instead.
+func (Target_UploadMode) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_targets_proto_rawDescGZIP(), []int{0, 4}
+}
+
+// RemediationPolicy is the action Hermes takes on Hermes files found in the
+// bucket that are not in its journal, e.g. files left behind by a crash.
+// Files not created by Hermes are only ever reported.
+type Target_RemediationPolicy int32
+
+const (
+	// Defaults to REPORT.
+	Target_REMEDIATION_POLICY_UNSPECIFIED Target_RemediationPolicy = 0
+	// Report the files and leave them in place.
+	Target_REPORT Target_RemediationPolicy = 1
+	// Move the files under the quarantine/ prefix for review.
+	Target_QUARANTINE Target_RemediationPolicy = 2
+	// Delete the files once they are older than the grace period.
+	Target_DELETE Target_RemediationPolicy = 3
+)
+
+// Enum value maps for Target_RemediationPolicy.
+var (
+	Target_RemediationPolicy_name = map[int32]string{
+		0: "REMEDIATION_POLICY_UNSPECIFIED",
+		1: "REPORT",
+		2: "QUARANTINE",
+		3: "DELETE",
+	}
+	Target_RemediationPolicy_value = map[string]int32{
+		"REMEDIATION_POLICY_UNSPECIFIED": 0,
+		"REPORT":                         1,
+		"QUARANTINE":                     2,
+		"DELETE":                         3,
+	}
+)
+
+func (x Target_RemediationPolicy) Enum() *Target_RemediationPolicy {
+	p := new(Target_RemediationPolicy)
+	*p = x
+	return p
+}
+
+func (x Target_RemediationPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Target_RemediationPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_googleinterns_step224_2020_config_proto_targets_proto_enumTypes[5].Descriptor()
+}
+
+func (Target_RemediationPolicy) Type() protoreflect.EnumType {
+	return &file_github_com_googleinterns_step224_2020_config_proto_targets_proto_enumTypes[5]
+}
+
+func (x Target_RemediationPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Target_RemediationPolicy.Descriptor instead.
+func (Target_RemediationPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_targets_proto_rawDescGZIP(), []int{0, 5}
+}
+
+// DeleteStrategy is the way Hermes picks the rotating file to delete.
+type Target_DeleteStrategy int32
+
+const (
+	// Defaults to RANDOM.
+	Target_DELETE_STRATEGY_UNSPECIFIED Target_DeleteStrategy = 0
+	// Pick a rotating file uniformly at random.
+	Target_RANDOM Target_DeleteStrategy = 1
+	// Pick the rotating files in turn, in ascending order of ID.
+	Target_ROUND_ROBIN Target_DeleteStrategy = 2
+	// Pick the rotating file that was created first.
+	Target_OLDEST_FIRST Target_DeleteStrategy = 3
+	// Pick the rotating file whose contents were verified least recently.
+	Target_LEAST_RECENTLY_VERIFIED Target_DeleteStrategy = 4
+	// Pick the rotating files in a random order, deterministically from
+	// delete_seed and the number of files deleted, so that every rotating file
+	// is picked once in every cycle of deletions. Intended for tests.
+	Target_SEEDED Target_DeleteStrategy = 5
+)
+
+// Enum value maps for Target_DeleteStrategy.
+var (
+	Target_DeleteStrategy_name = map[int32]string{
+		0: "DELETE_STRATEGY_UNSPECIFIED",
+		1: "RANDOM",
+		2: "ROUND_ROBIN",
+		3: "OLDEST_FIRST",
+		4: "LEAST_RECENTLY_VERIFIED",
+		5: "SEEDED",
+	}
+	Target_DeleteStrategy_value = map[string]int32{
+		"DELETE_STRATEGY_UNSPECIFIED": 0,
+		"RANDOM":                      1,
+		"ROUND_ROBIN":                 2,
+		"OLDEST_FIRST":                3,
+		"LEAST_RECENTLY_VERIFIED":     4,
+		"SEEDED":                      5,
+	}
+)
+
+func (x Target_DeleteStrategy) Enum() *Target_DeleteStrategy {
+	p := new(Target_DeleteStrategy)
+	*p = x
+	return p
+}
+
+func (x Target_DeleteStrategy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Target_DeleteStrategy) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_googleinterns_step224_2020_config_proto_targets_proto_enumTypes[6].Descriptor()
+}
+
+func (Target_DeleteStrategy) Type() protoreflect.EnumType {
+	return &file_github_com_googleinterns_step224_2020_config_proto_targets_proto_enumTypes[6]
+}
+
+func (x Target_DeleteStrategy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Target_DeleteStrategy.Descriptor instead.
+func (Target_DeleteStrategy) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_targets_proto_rawDescGZIP(), []int{0, 6}
+}
+
+// MaintenanceMode is how Hermes treats this target during its maintenance
+// windows and while it is paused.
+type Target_MaintenanceMode int32
+
+const (
+	// Defaults to SUPPRESS.
+	Target_MAINTENANCE_MODE_UNSPECIFIED Target_MaintenanceMode = 0
+	// Do not probe the target.
+	Target_SUPPRESS Target_MaintenanceMode = 1
+	// Probe the target and report the result of every run with the
+	// maintenance exit status.
+	Target_MARK Target_MaintenanceMode = 2
+)
+
+// Enum value maps for Target_MaintenanceMode.
+var (
+	Target_MaintenanceMode_name = map[int32]string{
+		0: "MAINTENANCE_MODE_UNSPECIFIED",
+		1: "SUPPRESS",
+		2: "MARK",
+	}
+	Target_MaintenanceMode_value = map[string]int32{
+		"MAINTENANCE_MODE_UNSPECIFIED": 0,
+		"SUPPRESS":                     1,
+		"MARK":                         2,
+	}
+)
+
+func (x Target_MaintenanceMode) Enum() *Target_MaintenanceMode {
+	p := new(Target_MaintenanceMode)
+	*p = x
+	return p
+}
+
+func (x Target_MaintenanceMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Target_MaintenanceMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_googleinterns_step224_2020_config_proto_targets_proto_enumTypes[7].Descriptor()
+}
+
+func (Target_MaintenanceMode) Type() protoreflect.EnumType {
+	return &file_github_com_googleinterns_step224_2020_config_proto_targets_proto_enumTypes[7]
+}
+
+func (x Target_MaintenanceMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Target_MaintenanceMode.Descriptor instead.
+func (Target_MaintenanceMode) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_targets_proto_rawDescGZIP(), []int{0, 7}
+}
+
 // TargetDefinition contains all of the metadata necessary for Hermes to establish a connection to a storage system.
 // Every probe request will require one or more targets.
 type Target struct {
@@ -165,6 +512,58 @@ type Target struct {
 	TargetUrl string `protobuf:"bytes,7,opt,name=target_url,json=targetUrl,proto3" json:"target_url,omitempty"`
 	// Name for bucket used by Hermes on this target storage system.
 	BucketName string `protobuf:"bytes,8,opt,name=bucket_name,json=bucketName,proto3" json:"bucket_name,omitempty"`
+	// Algorithm used to checksum the contents of files on this target.
+	ChecksumAlgorithm Target_ChecksumAlgorithm `protobuf:"varint,9,opt,name=checksum_algorithm,json=checksumAlgorithm,proto3,enum=hermes.Target_ChecksumAlgorithm" json:"checksum_algorithm,omitempty"`
+	// Mode used to read files on this target.
+	ReadMode Target_ReadMode `protobuf:"varint,10,opt,name=read_mode,json=readMode,proto3,enum=hermes.Target_ReadMode" json:"read_mode,omitempty"`
+	// Number of random byte ranges read per file in RANGE read mode, default = 4.
+	RangeReadsPerFile int32 `protobuf:"varint,11,opt,name=range_reads_per_file,json=rangeReadsPerFile,proto3" json:"range_reads_per_file,omitempty"`
+	// Mode used to upload files on this target.
+	UploadMode Target_UploadMode `protobuf:"varint,12,opt,name=upload_mode,json=uploadMode,proto3,enum=hermes.Target_UploadMode" json:"upload_mode,omitempty"`
+	// Size of each chunk in RESUMABLE upload mode, default = 256 KiB.
+	// GCS rounds the chunk size up to a multiple of 256 KiB.
+	UploadChunkSizeBytes int32 `protobuf:"varint,13,opt,name=upload_chunk_size_bytes,json=uploadChunkSizeBytes,proto3" json:"upload_chunk_size_bytes,omitempty"`
+	// Configuration of the consistency lag measurements on this target.
+	Consistency *Target_ConsistencyConfig `protobuf:"bytes,14,opt,name=consistency,proto3" json:"consistency,omitempty"`
+	// Action taken on unknown Hermes files found on this target.
+	RemediationPolicy Target_RemediationPolicy `protobuf:"varint,15,opt,name=remediation_policy,json=remediationPolicy,proto3,enum=hermes.Target_RemediationPolicy" json:"remediation_policy,omitempty"`
+	// Minimum age of an unknown Hermes file before it is deleted by the DELETE
+	// remediation policy in seconds, default = 86400.
+	RemediationGracePeriodSec int64 `protobuf:"varint,16,opt,name=remediation_grace_period_sec,json=remediationGracePeriodSec,proto3" json:"remediation_grace_period_sec,omitempty"`
+	// Create the bucket when bootstrapping this target if it does not exist.
+	CreateBucket bool `protobuf:"varint,17,opt,name=create_bucket,json=createBucket,proto3" json:"create_bucket,omitempty"`
+	// Maximum rate at which files are created when bootstrapping this target
+	// in files per second, default = 1.
+	BootstrapFilesPerSec float64 `protobuf:"fixed64,18,opt,name=bootstrap_files_per_sec,json=bootstrapFilesPerSec,proto3" json:"bootstrap_files_per_sec,omitempty"`
+	// Size of the files created on this target in bytes, default = 1000.
+	FileSizeBytes int32 `protobuf:"varint,19,opt,name=file_size_bytes,json=fileSizeBytes,proto3" json:"file_size_bytes,omitempty"`
+	// Layout of the file IDs on this target.
+	FileLayout *Target_FileLayout `protobuf:"bytes,20,opt,name=file_layout,json=fileLayout,proto3" json:"file_layout,omitempty"`
+	// Minimum time between two verifications of the contents of each permanent
+	// file in seconds, default = 86400.
+	DurabilityCheckIntervalSec int64 `protobuf:"varint,21,opt,name=durability_check_interval_sec,json=durabilityCheckIntervalSec,proto3" json:"durability_check_interval_sec,omitempty"`
+	// Age in days above which the corruption of a permanent file is reported
+	// with the durable_file_corrupted status, default = 30.
+	DurabilityMinAgeDays int32 `protobuf:"varint,22,opt,name=durability_min_age_days,json=durabilityMinAgeDays,proto3" json:"durability_min_age_days,omitempty"`
+	// Strategy used to pick the rotating file to delete on this target.
+	DeleteStrategy Target_DeleteStrategy `protobuf:"varint,23,opt,name=delete_strategy,json=deleteStrategy,proto3,enum=hermes.Target_DeleteStrategy" json:"delete_strategy,omitempty"`
+	// Seed of the SEEDED delete strategy.
+	DeleteSeed int64 `protobuf:"varint,24,opt,name=delete_seed,json=deleteSeed,proto3" json:"delete_seed,omitempty"`
+	// Maximum number of API calls per second made to this target.
+	// Default = 0, which does not limit the rate of API calls.
+	ApiQpsLimit float64 `protobuf:"fixed64,25,opt,name=api_qps_limit,json=apiQpsLimit,proto3" json:"api_qps_limit,omitempty"`
+	// Interval between the runs of the probe on this target in seconds.
+	// Default = 0, which uses the interval_sec of the probe.
+	IntervalSec int32 `protobuf:"varint,26,opt,name=interval_sec,json=intervalSec,proto3" json:"interval_sec,omitempty"`
+	// Fraction of the interval by which each interval of this target is randomly
+	// lengthened or shortened, in [0, 1). Default = 0, which uses the
+	// interval_jitter of the probe.
+	IntervalJitter float64 `protobuf:"fixed64,27,opt,name=interval_jitter,json=intervalJitter,proto3" json:"interval_jitter,omitempty"`
+	// Planned maintenance windows of this target, in addition to the
+	// maintenance windows of the probe.
+	MaintenanceWindows []*MaintenanceWindow `protobuf:"bytes,28,rep,name=maintenance_windows,json=maintenanceWindows,proto3" json:"maintenance_windows,omitempty"`
+	// How this target is treated during maintenance, default = SUPPRESS.
+	MaintenanceMode Target_MaintenanceMode `protobuf:"varint,29,opt,name=maintenance_mode,json=maintenanceMode,proto3,enum=hermes.Target_MaintenanceMode" json:"maintenance_mode,omitempty"`
 }
 
 func (x *Target) Reset() {
@@ -255,6 +654,392 @@ func (x *Target) GetBucketName() string {
 	return ""
 }
 
+func (x *Target) GetChecksumAlgorithm() Target_ChecksumAlgorithm {
+	if x != nil {
+		return x.ChecksumAlgorithm
+	}
+	return Target_CHECKSUM_ALGORITHM_UNSPECIFIED
+}
+
+func (x *Target) GetReadMode() Target_ReadMode {
+	if x != nil {
+		return x.ReadMode
+	}
+	return Target_READ_MODE_UNSPECIFIED
+}
+
+func (x *Target) GetRangeReadsPerFile() int32 {
+	if x != nil {
+		return x.RangeReadsPerFile
+	}
+	return 0
+}
+
+func (x *Target) GetUploadMode() Target_UploadMode {
+	if x != nil {
+		return x.UploadMode
+	}
+	return Target_UPLOAD_MODE_UNSPECIFIED
+}
+
+func (x *Target) GetUploadChunkSizeBytes() int32 {
+	if x != nil {
+		return x.UploadChunkSizeBytes
+	}
+	return 0
+}
+
+func (x *Target) GetConsistency() *Target_ConsistencyConfig {
+	if x != nil {
+		return x.Consistency
+	}
+	return nil
+}
+
+func (x *Target) GetRemediationPolicy() Target_RemediationPolicy {
+	if x != nil {
+		return x.RemediationPolicy
+	}
+	return Target_REMEDIATION_POLICY_UNSPECIFIED
+}
+
+func (x *Target) GetRemediationGracePeriodSec() int64 {
+	if x != nil {
+		return x.RemediationGracePeriodSec
+	}
+	return 0
+}
+
+func (x *Target) GetCreateBucket() bool {
+	if x != nil {
+		return x.CreateBucket
+	}
+	return false
+}
+
+func (x *Target) GetBootstrapFilesPerSec() float64 {
+	if x != nil {
+		return x.BootstrapFilesPerSec
+	}
+	return 0
+}
+
+func (x *Target) GetFileSizeBytes() int32 {
+	if x != nil {
+		return x.FileSizeBytes
+	}
+	return 0
+}
+
+func (x *Target) GetFileLayout() *Target_FileLayout {
+	if x != nil {
+		return x.FileLayout
+	}
+	return nil
+}
+
+func (x *Target) GetDurabilityCheckIntervalSec() int64 {
+	if x != nil {
+		return x.DurabilityCheckIntervalSec
+	}
+	return 0
+}
+
+func (x *Target) GetDurabilityMinAgeDays() int32 {
+	if x != nil {
+		return x.DurabilityMinAgeDays
+	}
+	return 0
+}
+
+func (x *Target) GetDeleteStrategy() Target_DeleteStrategy {
+	if x != nil {
+		return x.DeleteStrategy
+	}
+	return Target_DELETE_STRATEGY_UNSPECIFIED
+}
+
+func (x *Target) GetDeleteSeed() int64 {
+	if x != nil {
+		return x.DeleteSeed
+	}
+	return 0
+}
+
+func (x *Target) GetApiQpsLimit() float64 {
+	if x != nil {
+		return x.ApiQpsLimit
+	}
+	return 0
+}
+
+func (x *Target) GetIntervalSec() int32 {
+	if x != nil {
+		return x.IntervalSec
+	}
+	return 0
+}
+
+func (x *Target) GetIntervalJitter() float64 {
+	if x != nil {
+		return x.IntervalJitter
+	}
+	return 0
+}
+
+func (x *Target) GetMaintenanceWindows() []*MaintenanceWindow {
+	if x != nil {
+		return x.MaintenanceWindows
+	}
+	return nil
+}
+
+func (x *Target) GetMaintenanceMode() Target_MaintenanceMode {
+	if x != nil {
+		return x.MaintenanceMode
+	}
+	return Target_MAINTENANCE_MODE_UNSPECIFIED
+}
+
+// MaintenanceWindow is a period of planned maintenance of a storage system,
+// either a one-off range of time or a recurring window.
+type MaintenanceWindow struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Start and end of a one-off window in seconds since the Unix epoch.
+	StartUnixSec int64 `protobuf:"varint,1,opt,name=start_unix_sec,json=startUnixSec,proto3" json:"start_unix_sec,omitempty"`
+	EndUnixSec   int64 `protobuf:"varint,2,opt,name=end_unix_sec,json=endUnixSec,proto3" json:"end_unix_sec,omitempty"`
+	// Cron schedule of the start of a recurring window, with five fields:
+	// minute, hour, day of month, month and day of week, e.g. "0 2 * * 6" for
+	// 02:00 every Saturday.
+	Schedule string `protobuf:"bytes,3,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	// Duration of each recurring window in seconds.
+	DurationSec int64 `protobuf:"varint,4,opt,name=duration_sec,json=durationSec,proto3" json:"duration_sec,omitempty"`
+	// Time zone of the schedule, e.g. "Europe/Dublin", default = UTC.
+	TimeZone string `protobuf:"bytes,5,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+}
+
+func (x *MaintenanceWindow) Reset() {
+	*x = MaintenanceWindow{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_targets_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MaintenanceWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaintenanceWindow) ProtoMessage() {}
+
+func (x *MaintenanceWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_targets_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaintenanceWindow.ProtoReflect.Descriptor instead.
+func (*MaintenanceWindow) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_targets_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MaintenanceWindow) GetStartUnixSec() int64 {
+	if x != nil {
+		return x.StartUnixSec
+	}
+	return 0
+}
+
+func (x *MaintenanceWindow) GetEndUnixSec() int64 {
+	if x != nil {
+		return x.EndUnixSec
+	}
+	return 0
+}
+
+func (x *MaintenanceWindow) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *MaintenanceWindow) GetDurationSec() int64 {
+	if x != nil {
+		return x.DurationSec
+	}
+	return 0
+}
+
+func (x *MaintenanceWindow) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+// ConsistencyConfig configures how Hermes measures the time taken for the
+// target to reflect the creation and deletion of files.
+// After every change Hermes polls the target with an exponential backoff
+// until the change is visible or the timeout expires.
+type Target_ConsistencyConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Delay before the second poll in milliseconds, default = 100.
+	InitialBackoffMs int32 `protobuf:"varint,1,opt,name=initial_backoff_ms,json=initialBackoffMs,proto3" json:"initial_backoff_ms,omitempty"`
+	// Maximum delay between polls in milliseconds, default = 5000.
+	MaxBackoffMs int32 `protobuf:"varint,2,opt,name=max_backoff_ms,json=maxBackoffMs,proto3" json:"max_backoff_ms,omitempty"`
+	// Factor the delay is multiplied by after every poll, default = 2.
+	BackoffMultiplier float64 `protobuf:"fixed64,3,opt,name=backoff_multiplier,json=backoffMultiplier,proto3" json:"backoff_multiplier,omitempty"`
+	// Time after which a change is considered lost in seconds, default = 60.
+	TimeoutSec int32 `protobuf:"varint,4,opt,name=timeout_sec,json=timeoutSec,proto3" json:"timeout_sec,omitempty"`
+	// Lag above which a consistency check fails in seconds, default = 5.
+	LagSloSec float64 `protobuf:"fixed64,5,opt,name=lag_slo_sec,json=lagSloSec,proto3" json:"lag_slo_sec,omitempty"`
+}
+
+func (x *Target_ConsistencyConfig) Reset() {
+	*x = Target_ConsistencyConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_targets_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Target_ConsistencyConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Target_ConsistencyConfig) ProtoMessage() {}
+
+func (x *Target_ConsistencyConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_targets_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Target_ConsistencyConfig.ProtoReflect.Descriptor instead.
+func (*Target_ConsistencyConfig) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_targets_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *Target_ConsistencyConfig) GetInitialBackoffMs() int32 {
+	if x != nil {
+		return x.InitialBackoffMs
+	}
+	return 0
+}
+
+func (x *Target_ConsistencyConfig) GetMaxBackoffMs() int32 {
+	if x != nil {
+		return x.MaxBackoffMs
+	}
+	return 0
+}
+
+func (x *Target_ConsistencyConfig) GetBackoffMultiplier() float64 {
+	if x != nil {
+		return x.BackoffMultiplier
+	}
+	return 0
+}
+
+func (x *Target_ConsistencyConfig) GetTimeoutSec() int32 {
+	if x != nil {
+		return x.TimeoutSec
+	}
+	return 0
+}
+
+func (x *Target_ConsistencyConfig) GetLagSloSec() float64 {
+	if x != nil {
+		return x.LagSloSec
+	}
+	return 0
+}
+
+// FileLayout configures the IDs of the files Hermes keeps on this target.
+// File ID 0 is reserved for the NIL file. Files 1 to num_files are created
+// when the target is bootstrapped. Files 1 to num_permanent_files are never
+// deleted and are used to track long-term durability. The remaining files,
+// num_permanent_files + 1 to num_files, are rotated by deleting and
+// recreating them.
+type Target_FileLayout struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Number of files kept on the target, default = 50.
+	NumFiles int32 `protobuf:"varint,1,opt,name=num_files,json=numFiles,proto3" json:"num_files,omitempty"`
+	// Number of permanent files, default = num_files / 5.
+	// Must be less than num_files so that at least one file is rotated.
+	NumPermanentFiles int32 `protobuf:"varint,2,opt,name=num_permanent_files,json=numPermanentFiles,proto3" json:"num_permanent_files,omitempty"`
+}
+
+func (x *Target_FileLayout) Reset() {
+	*x = Target_FileLayout{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_googleinterns_step224_2020_config_proto_targets_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Target_FileLayout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Target_FileLayout) ProtoMessage() {}
+
+func (x *Target_FileLayout) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_googleinterns_step224_2020_config_proto_targets_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Target_FileLayout.ProtoReflect.Descriptor instead.
+func (*Target_FileLayout) Descriptor() ([]byte, []int) {
+	return file_github_com_googleinterns_step224_2020_config_proto_targets_proto_rawDescGZIP(), []int{0, 1}
+}
+
+func (x *Target_FileLayout) GetNumFiles() int32 {
+	if x != nil {
+		return x.NumFiles
+	}
+	return 0
+}
+
+func (x *Target_FileLayout) GetNumPermanentFiles() int32 {
+	if x != nil {
+		return x.NumPermanentFiles
+	}
+	return 0
+}
+
 var File_github_com_googleinterns_step224_2020_config_proto_targets_proto protoreflect.FileDescriptor
 
 var file_github_com_googleinterns_step224_2020_config_proto_targets_proto_rawDesc = []byte{
@@ -262,7 +1047,7 @@ var file_github_com_googleinterns_step224_2020_config_proto_targets_proto_rawDes
 	0x67, 0x6c, 0x65, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x73, 0x2f, 0x73, 0x74, 0x65, 0x70, 0x32,
 	0x32, 0x34, 0x2d, 0x32, 0x30, 0x32, 0x30, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x12, 0x06, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x22, 0xdf, 0x03, 0x0a, 0x06, 0x54,
+	0x74, 0x6f, 0x12, 0x06, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x22, 0xdf, 0x13, 0x0a, 0x06, 0x54,
 	0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x40, 0x0a, 0x0d, 0x74, 0x61, 0x72,
 	0x67, 0x65, 0x74, 0x5f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
@@ -283,7 +1068,101 @@ var file_github_com_googleinterns_step224_2020_config_proto_targets_proto_rawDes
 	0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
 	0x09, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x55, 0x72, 0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x75,
 	0x63, 0x6b, 0x65, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0a, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x47, 0x0a, 0x0c, 0x54,
+	0x0a, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x4f, 0x0a, 0x12, 0x63,
+	0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x5f, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68,
+	0x6d, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x20, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73,
+	0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d,
+	0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x52, 0x11, 0x63, 0x68, 0x65, 0x63, 0x6b,
+	0x73, 0x75, 0x6d, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x12, 0x34, 0x0a, 0x09,
+	0x72, 0x65, 0x61, 0x64, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x17, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x2e,
+	0x52, 0x65, 0x61, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x08, 0x72, 0x65, 0x61, 0x64, 0x4d, 0x6f,
+	0x64, 0x65, 0x12, 0x2f, 0x0a, 0x14, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x64,
+	0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x11, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x61, 0x64, 0x73, 0x50, 0x65, 0x72, 0x46,
+	0x69, 0x6c, 0x65, 0x12, 0x3a, 0x0a, 0x0b, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x6d, 0x6f,
+	0x64, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65,
+	0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d,
+	0x6f, 0x64, 0x65, 0x52, 0x0a, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x12,
+	0x35, 0x0a, 0x17, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f,
+	0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x14, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x53, 0x69, 0x7a,
+	0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x42, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x73, 0x69, 0x73,
+	0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x68, 0x65,
+	0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6e, 0x73,
+	0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0b, 0x63,
+	0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x4f, 0x0a, 0x12, 0x72, 0x65,
+	0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x18, 0x0f, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x20, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e,
+	0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x2e, 0x52, 0x65, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x11, 0x72, 0x65, 0x6d, 0x65, 0x64, 0x69,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x3f, 0x0a, 0x1c, 0x72,
+	0x65, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x67, 0x72, 0x61, 0x63, 0x65,
+	0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x10, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x19, 0x72, 0x65, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x47, 0x72,
+	0x61, 0x63, 0x65, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x53, 0x65, 0x63, 0x12, 0x23, 0x0a, 0x0d,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x18, 0x11, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0c, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x75, 0x63, 0x6b, 0x65,
+	0x74, 0x12, 0x35, 0x0a, 0x17, 0x62, 0x6f, 0x6f, 0x74, 0x73, 0x74, 0x72, 0x61, 0x70, 0x5f, 0x66,
+	0x69, 0x6c, 0x65, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x12, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x14, 0x62, 0x6f, 0x6f, 0x74, 0x73, 0x74, 0x72, 0x61, 0x70, 0x46, 0x69, 0x6c,
+	0x65, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x12, 0x26, 0x0a, 0x0f, 0x66, 0x69, 0x6c, 0x65,
+	0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x13, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0d, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x12, 0x3a, 0x0a, 0x0b, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x6c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x18,
+	0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74,
+	0x52, 0x0a, 0x66, 0x69, 0x6c, 0x65, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x12, 0x41, 0x0a, 0x1d,
+	0x64, 0x75, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b,
+	0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x15, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x1a, 0x64, 0x75, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x43,
+	0x68, 0x65, 0x63, 0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x53, 0x65, 0x63, 0x12,
+	0x35, 0x0a, 0x17, 0x64, 0x75, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x5f, 0x6d, 0x69,
+	0x6e, 0x5f, 0x61, 0x67, 0x65, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x18, 0x16, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x14, 0x64, 0x75, 0x72, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x4d, 0x69, 0x6e, 0x41,
+	0x67, 0x65, 0x44, 0x61, 0x79, 0x73, 0x12, 0x46, 0x0a, 0x0f, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x5f, 0x73, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x79, 0x18, 0x17, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x1d, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x2e,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x79, 0x52, 0x0e,
+	0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x79, 0x12, 0x1f,
+	0x0a, 0x0b, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x5f, 0x73, 0x65, 0x65, 0x64, 0x18, 0x18, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0a, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x65, 0x65, 0x64, 0x12,
+	0x22, 0x0a, 0x0d, 0x61, 0x70, 0x69, 0x5f, 0x71, 0x70, 0x73, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x18, 0x19, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x61, 0x70, 0x69, 0x51, 0x70, 0x73, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f,
+	0x73, 0x65, 0x63, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x76, 0x61, 0x6c, 0x53, 0x65, 0x63, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x5f, 0x6a, 0x69, 0x74, 0x74, 0x65, 0x72, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4a, 0x69, 0x74, 0x74, 0x65, 0x72, 0x12,
+	0x4a, 0x0a, 0x13, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x77,
+	0x69, 0x6e, 0x64, 0x6f, 0x77, 0x73, 0x18, 0x1c, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x68,
+	0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63,
+	0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x52, 0x12, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e,
+	0x61, 0x6e, 0x63, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x73, 0x12, 0x49, 0x0a, 0x10, 0x6d,
+	0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18,
+	0x1d, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x73, 0x2e, 0x54,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x2e, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63,
+	0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x0f, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e,
+	0x63, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x1a, 0xd7, 0x01, 0x0a, 0x11, 0x43, 0x6f, 0x6e, 0x73, 0x69,
+	0x73, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x2c, 0x0a, 0x12,
+	0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x5f,
+	0x6d, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61,
+	0x6c, 0x42, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x4d, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x6d, 0x61,
+	0x78, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x5f, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x42, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x4d, 0x73,
+	0x12, 0x2d, 0x0a, 0x12, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x5f, 0x6d, 0x75, 0x6c, 0x74,
+	0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x62, 0x61,
+	0x63, 0x6b, 0x6f, 0x66, 0x66, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x12,
+	0x1f, 0x0a, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63,
+	0x12, 0x1e, 0x0a, 0x0b, 0x6c, 0x61, 0x67, 0x5f, 0x73, 0x6c, 0x6f, 0x5f, 0x73, 0x65, 0x63, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x6c, 0x61, 0x67, 0x53, 0x6c, 0x6f, 0x53, 0x65, 0x63,
+	0x1a, 0x59, 0x0a, 0x0a, 0x46, 0x69, 0x6c, 0x65, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x12, 0x1b,
+	0x0a, 0x09, 0x6e, 0x75, 0x6d, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x6e, 0x75, 0x6d, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x6e,
+	0x75, 0x6d, 0x5f, 0x70, 0x65, 0x72, 0x6d, 0x61, 0x6e, 0x65, 0x6e, 0x74, 0x5f, 0x66, 0x69, 0x6c,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x11, 0x6e, 0x75, 0x6d, 0x50, 0x65, 0x72,
+	0x6d, 0x61, 0x6e, 0x65, 0x6e, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x22, 0x47, 0x0a, 0x0c, 0x54,
 	0x61, 0x72, 0x67, 0x65, 0x74, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x1d, 0x0a, 0x19, 0x54,
 	0x41, 0x52, 0x47, 0x45, 0x54, 0x5f, 0x53, 0x59, 0x53, 0x54, 0x45, 0x4d, 0x5f, 0x55, 0x4e, 0x53,
 	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x47, 0x4f,
@@ -292,11 +1171,57 @@ var file_github_com_googleinterns_step224_2020_config_proto_targets_proto_rawDes
 	0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x1b, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43,
 	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
 	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x54, 0x54, 0x50, 0x10,
-	0x01, 0x12, 0x09, 0x0a, 0x05, 0x48, 0x54, 0x54, 0x50, 0x53, 0x10, 0x02, 0x42, 0x34, 0x5a, 0x32,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x73, 0x2f, 0x73, 0x74, 0x65, 0x70, 0x32, 0x32, 0x34,
-	0x2d, 0x32, 0x30, 0x32, 0x30, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x01, 0x12, 0x09, 0x0a, 0x05, 0x48, 0x54, 0x54, 0x50, 0x53, 0x10, 0x02, 0x22, 0x62, 0x0a, 0x11,
+	0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68,
+	0x6d, 0x12, 0x22, 0x0a, 0x1e, 0x43, 0x48, 0x45, 0x43, 0x4b, 0x53, 0x55, 0x4d, 0x5f, 0x41, 0x4c,
+	0x47, 0x4f, 0x52, 0x49, 0x54, 0x48, 0x4d, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46,
+	0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x48, 0x41, 0x31, 0x10, 0x01, 0x12,
+	0x0a, 0x0a, 0x06, 0x53, 0x48, 0x41, 0x32, 0x35, 0x36, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x43,
+	0x52, 0x43, 0x33, 0x32, 0x43, 0x10, 0x03, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x44, 0x35, 0x10, 0x04,
+	0x22, 0x3a, 0x0a, 0x08, 0x52, 0x65, 0x61, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x19, 0x0a, 0x15,
+	0x52, 0x45, 0x41, 0x44, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x46, 0x55, 0x4c, 0x4c, 0x10,
+	0x01, 0x12, 0x09, 0x0a, 0x05, 0x52, 0x41, 0x4e, 0x47, 0x45, 0x10, 0x02, 0x22, 0x44, 0x0a, 0x0a,
+	0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x1b, 0x0a, 0x17, 0x55, 0x50,
+	0x4c, 0x4f, 0x41, 0x44, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x49, 0x4e, 0x47, 0x4c,
+	0x45, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x52, 0x45, 0x53, 0x55, 0x4d, 0x41, 0x42, 0x4c, 0x45,
+	0x10, 0x02, 0x22, 0x5f, 0x0a, 0x11, 0x52, 0x65, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x22, 0x0a, 0x1e, 0x52, 0x45, 0x4d, 0x45, 0x44,
+	0x49, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x55, 0x4e,
+	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x52,
+	0x45, 0x50, 0x4f, 0x52, 0x54, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x51, 0x55, 0x41, 0x52, 0x41,
+	0x4e, 0x54, 0x49, 0x4e, 0x45, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x44, 0x45, 0x4c, 0x45, 0x54,
+	0x45, 0x10, 0x03, 0x22, 0x89, 0x01, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74,
+	0x72, 0x61, 0x74, 0x65, 0x67, 0x79, 0x12, 0x1f, 0x0a, 0x1b, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45,
+	0x5f, 0x53, 0x54, 0x52, 0x41, 0x54, 0x45, 0x47, 0x59, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x41, 0x4e, 0x44, 0x4f,
+	0x4d, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x52, 0x4f, 0x55, 0x4e, 0x44, 0x5f, 0x52, 0x4f, 0x42,
+	0x49, 0x4e, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x4f, 0x4c, 0x44, 0x45, 0x53, 0x54, 0x5f, 0x46,
+	0x49, 0x52, 0x53, 0x54, 0x10, 0x03, 0x12, 0x1b, 0x0a, 0x17, 0x4c, 0x45, 0x41, 0x53, 0x54, 0x5f,
+	0x52, 0x45, 0x43, 0x45, 0x4e, 0x54, 0x4c, 0x59, 0x5f, 0x56, 0x45, 0x52, 0x49, 0x46, 0x49, 0x45,
+	0x44, 0x10, 0x04, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x45, 0x45, 0x44, 0x45, 0x44, 0x10, 0x05, 0x22,
+	0x4b, 0x0a, 0x0f, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x4d, 0x6f,
+	0x64, 0x65, 0x12, 0x20, 0x0a, 0x1c, 0x4d, 0x41, 0x49, 0x4e, 0x54, 0x45, 0x4e, 0x41, 0x4e, 0x43,
+	0x45, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49,
+	0x45, 0x44, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x55, 0x50, 0x50, 0x52, 0x45, 0x53, 0x53,
+	0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x4d, 0x41, 0x52, 0x4b, 0x10, 0x02, 0x22, 0xb7, 0x01, 0x0a,
+	0x11, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x57, 0x69, 0x6e, 0x64,
+	0x6f, 0x77, 0x12, 0x24, 0x0a, 0x0e, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x75, 0x6e, 0x69, 0x78,
+	0x5f, 0x73, 0x65, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x55, 0x6e, 0x69, 0x78, 0x53, 0x65, 0x63, 0x12, 0x20, 0x0a, 0x0c, 0x65, 0x6e, 0x64, 0x5f,
+	0x75, 0x6e, 0x69, 0x78, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a,
+	0x65, 0x6e, 0x64, 0x55, 0x6e, 0x69, 0x78, 0x53, 0x65, 0x63, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x63,
+	0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x63,
+	0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x63, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x5f, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69,
+	0x6d, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x73, 0x2f, 0x73, 0x74, 0x65, 0x70, 0x32, 0x32, 0x34, 0x2d, 0x32, 0x30, 0x32, 0x30, 0x2f,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -311,21 +1236,39 @@ func file_github_com_googleinterns_step224_2020_config_proto_targets_proto_rawDe
 	return file_github_com_googleinterns_step224_2020_config_proto_targets_proto_rawDescData
 }
 
-var file_github_com_googleinterns_step224_2020_config_proto_targets_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_github_com_googleinterns_step224_2020_config_proto_targets_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_googleinterns_step224_2020_config_proto_targets_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
+var file_github_com_googleinterns_step224_2020_config_proto_targets_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_github_com_googleinterns_step224_2020_config_proto_targets_proto_goTypes = []interface{}{
-	(Target_TargetSystem)(0),   // 0: hermes.Target.TargetSystem
-	(Target_ConnectionType)(0), // 1: hermes.Target.ConnectionType
-	(*Target)(nil),             // 2: hermes.Target
+	(Target_TargetSystem)(0),         // 0: hermes.Target.TargetSystem
+	(Target_ConnectionType)(0),       // 1: hermes.Target.ConnectionType
+	(Target_ChecksumAlgorithm)(0),    // 2: hermes.Target.ChecksumAlgorithm
+	(Target_ReadMode)(0),             // 3: hermes.Target.ReadMode
+	(Target_UploadMode)(0),           // 4: hermes.Target.UploadMode
+	(Target_RemediationPolicy)(0),    // 5: hermes.Target.RemediationPolicy
+	(Target_DeleteStrategy)(0),       // 6: hermes.Target.DeleteStrategy
+	(Target_MaintenanceMode)(0),      // 7: hermes.Target.MaintenanceMode
+	(*Target)(nil),                   // 8: hermes.Target
+	(*MaintenanceWindow)(nil),        // 9: hermes.MaintenanceWindow
+	(*Target_ConsistencyConfig)(nil), // 10: hermes.Target.ConsistencyConfig
+	(*Target_FileLayout)(nil),        // 11: hermes.Target.FileLayout
 }
 var file_github_com_googleinterns_step224_2020_config_proto_targets_proto_depIdxs = []int32{
-	0, // 0: hermes.Target.target_system:type_name -> hermes.Target.TargetSystem
-	1, // 1: hermes.Target.connection_type:type_name -> hermes.Target.ConnectionType
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0,  // 0: hermes.Target.target_system:type_name -> hermes.Target.TargetSystem
+	1,  // 1: hermes.Target.connection_type:type_name -> hermes.Target.ConnectionType
+	2,  // 2: hermes.Target.checksum_algorithm:type_name -> hermes.Target.ChecksumAlgorithm
+	3,  // 3: hermes.Target.read_mode:type_name -> hermes.Target.ReadMode
+	4,  // 4: hermes.Target.upload_mode:type_name -> hermes.Target.UploadMode
+	10, // 5: hermes.Target.consistency:type_name -> hermes.Target.ConsistencyConfig
+	5,  // 6: hermes.Target.remediation_policy:type_name -> hermes.Target.RemediationPolicy
+	11, // 7: hermes.Target.file_layout:type_name -> hermes.Target.FileLayout
+	6,  // 8: hermes.Target.delete_strategy:type_name -> hermes.Target.DeleteStrategy
+	9,  // 9: hermes.Target.maintenance_windows:type_name -> hermes.MaintenanceWindow
+	7,  // 10: hermes.Target.maintenance_mode:type_name -> hermes.Target.MaintenanceMode
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_github_com_googleinterns_step224_2020_config_proto_targets_proto_init() }
@@ -346,14 +1289,50 @@ func file_github_com_googleinterns_step224_2020_config_proto_targets_proto_init(
 				return nil
 			}
 		}
+		file_github_com_googleinterns_step224_2020_config_proto_targets_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MaintenanceWindow); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_targets_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Target_ConsistencyConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_googleinterns_step224_2020_config_proto_targets_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Target_FileLayout); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_github_com_googleinterns_step224_2020_config_proto_targets_proto_rawDesc,
-			NumEnums:      2,
-			NumMessages:   1,
+			NumEnums:      8,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
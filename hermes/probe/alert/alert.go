@@ -0,0 +1,412 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package alert implements the alert rules evaluated after every probe run of
+// a target, and the notifications of the alerts that fire and resolve, which
+// are grouped per target and sent to a webhook.
+package alert
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/slo"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+const (
+	defaultTimeout        = 10 * time.Second
+	defaultRepeatInterval = 4 * time.Hour
+
+	// Firing and Resolved are the states of an alert.
+	Firing   = "firing"
+	Resolved = "resolved"
+)
+
+// Observation holds the result of a probe run of a target, which the rules are evaluated against.
+type Observation struct {
+	// Status is the exit status of the run.
+	Status metrics.ExitStatus
+	// Err is the error the run failed with, nil if it succeeded.
+	Err error
+	// Indicators holds the service level indicators of the target after the run, nil if no SLO is configured.
+	Indicators []*probepb.SLI
+}
+
+// Alert is an alert of a target, as sent in notifications.
+type Alert struct {
+	Rule        string `json:"rule"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+	// StartsAtUnixSec is the time the alert fired.
+	StartsAtUnixSec int64 `json:"starts_at_unix_sec"`
+	// EndsAtUnixSec is the time the alert resolved, it is omitted while the alert is firing.
+	EndsAtUnixSec int64 `json:"ends_at_unix_sec,omitempty"`
+}
+
+// Notification groups the alerts of a target that are firing and those that resolved since the
+// previous notification of the target.
+type Notification struct {
+	Probe         string `json:"probe"`
+	Target        string `json:"target"`
+	StorageSystem string `json:"storage_system"`
+	// State is firing if any alert of the target is firing, resolved otherwise.
+	State  string   `json:"state"`
+	Alerts []*Alert `json:"alerts"`
+}
+
+// rule is an alert rule, exactly one of its conditions is set.
+type rule struct {
+	name                string
+	consecutiveFailures int
+	status              string
+	burnRate            float64
+	window              int64
+}
+
+// newRule creates a rule from the config given and checks that it has exactly one valid condition.
+func newRule(conf *probepb.AlertRule, windows []int64) (*rule, error) {
+	r := &rule{
+		name:                conf.GetName(),
+		consecutiveFailures: int(conf.GetConsecutiveFailures()),
+		status:              conf.GetStatus(),
+		burnRate:            conf.GetBurnRate(),
+		window:              conf.GetBurnRateWindowSec(),
+	}
+	if r.name == "" {
+		return nil, fmt.Errorf("invalid alert rule: no name given")
+	}
+	conditions := 0
+	if conf.ConsecutiveFailures != nil {
+		conditions++
+		if r.consecutiveFailures <= 0 {
+			return nil, fmt.Errorf("invalid alert rule %q: consecutive_failures = %d; want a positive value", r.name, r.consecutiveFailures)
+		}
+	}
+	if conf.Status != nil {
+		conditions++
+		known := false
+		for _, name := range metrics.ExitStatusName {
+			known = known || name == r.status
+		}
+		if !known {
+			return nil, fmt.Errorf("invalid alert rule %q: unknown status %q", r.name, r.status)
+		}
+	}
+	if conf.BurnRate != nil {
+		conditions++
+		if r.burnRate <= 0 {
+			return nil, fmt.Errorf("invalid alert rule %q: burn_rate = %v; want a positive rate", r.name, r.burnRate)
+		}
+		found := false
+		for _, w := range windows {
+			found = found || w == r.window
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid alert rule %q: burn_rate_window_sec = %d; want one of the SLO windows %v", r.name, r.window, windows)
+		}
+	}
+	if conditions != 1 {
+		return nil, fmt.Errorf("invalid alert rule %q: %d conditions set; want exactly one of consecutive_failures, status and burn_rate", r.name, conditions)
+	}
+	return r, nil
+}
+
+// evaluate returns whether the rule fires for a target after a run, and why.
+// Arguments:
+//	- failures: the number of consecutive failed runs of the target, including this run.
+//	- obs: the result of the run.
+// Returns:
+//	- fires: returns whether the rule fires.
+//	- description: returns why the rule fires, empty if it does not.
+func (r *rule) evaluate(failures int, obs *Observation) (bool, string) {
+	status := metrics.ExitStatusName[obs.Status]
+	switch {
+	case r.consecutiveFailures > 0:
+		if failures < r.consecutiveFailures {
+			return false, ""
+		}
+		return true, fmt.Sprintf("%d consecutive probe runs failed, the last with status %s%s", failures, status, errSuffix(obs.Err))
+	case r.status != "":
+		if status != r.status {
+			return false, ""
+		}
+		return true, fmt.Sprintf("probe run exited with status %s%s", status, errSuffix(obs.Err))
+	}
+	for _, sli := range obs.Indicators {
+		if sli.GetWindowSec() != r.window {
+			continue
+		}
+		// The fraction of the error budget consumed over the window is the burn rate over the window.
+		rate := 1 - sli.GetErrorBudgetRemaining()
+		if rate < r.burnRate {
+			return false, ""
+		}
+		return true, fmt.Sprintf("error budget burning at %.2fx over the %s window, %d runs with a success ratio of %.4f", rate, slo.WindowName(r.window), sli.GetRuns(), sli.GetSuccessRatio())
+	}
+	return false, ""
+}
+
+// errSuffix returns the error of a run to append to a description, empty if the run did not fail with an error.
+func errSuffix(err error) string {
+	if err == nil {
+		return ""
+	}
+	return ": " + err.Error()
+}
+
+// group holds the alerts of a target.
+type group struct {
+	mu     sync.Mutex
+	target *probepb.Target
+	// failures counts the consecutive failed runs of the target.
+	failures int
+	// firing holds the alerts of the target that are firing by rule name.
+	firing map[string]*Alert
+	// resolved holds the alerts of the target that resolved since the last notification.
+	resolved []*Alert
+	// pending is true if alerts fired or resolved since the last notification.
+	pending bool
+	// notified is the time of the last notification of the target.
+	notified time.Time
+	// evaluated is the time of the last evaluation of the target.
+	evaluated time.Time
+	// sending is true while a notification of the target is being sent, and sendingAt is the
+	// time of the evaluation it was built at.
+	sending   bool
+	sendingAt time.Time
+}
+
+// Engine evaluates the alert rules of every target and notifies the alerts that fire and resolve.
+// It is safe for concurrent use.
+type Engine struct {
+	probe   string
+	rules   []*rule
+	webhook *Webhook
+	repeat  time.Duration
+
+	mu     sync.Mutex
+	groups map[string]*group
+}
+
+// New creates the alerting engine of a probe from the config given.
+// Arguments:
+//	- probe: the name of the probe, which is sent in notifications.
+//	- conf: the alerting config of the probe.
+//	- windows: the lengths in seconds of the SLO windows, nil if no SLO is configured.
+// Returns:
+//	- engine: returns the engine, or nil if conf is nil.
+//	- err: returns an error if the webhook URL or a rule is invalid, or two rules have the same name.
+func New(probe string, conf *probepb.AlertingConfig, windows []int64) (*Engine, error) {
+	if conf == nil {
+		return nil, nil
+	}
+	u, err := url.Parse(conf.GetWebhookUrl())
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid alerting config: webhook_url = %q; want an HTTP or HTTPS URL", conf.GetWebhookUrl())
+	}
+	timeout := defaultTimeout
+	if sec := conf.GetTimeoutSec(); sec > 0 {
+		timeout = time.Duration(sec) * time.Second
+	}
+	e := &Engine{
+		probe:   probe,
+		webhook: NewWebhook(u.String(), timeout),
+		repeat:  defaultRepeatInterval,
+		groups:  make(map[string]*group),
+	}
+	if sec := conf.GetRepeatIntervalSec(); sec > 0 {
+		e.repeat = time.Duration(sec) * time.Second
+	}
+	names := make(map[string]bool)
+	for _, rc := range conf.GetRules() {
+		r, err := newRule(rc, windows)
+		if err != nil {
+			return nil, err
+		}
+		if names[r.name] {
+			return nil, fmt.Errorf("invalid alert rule %q: more than one rule has this name", r.name)
+		}
+		names[r.name] = true
+		e.rules = append(e.rules, r)
+	}
+	return e, nil
+}
+
+// groupFor returns the group of the alerts of a target, creating it if it does not exist yet.
+func (e *Engine) groupFor(target *probepb.Target) *group {
+	key := groupKey(target)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	g, ok := e.groups[key]
+	if !ok {
+		g = &group{target: target, firing: make(map[string]*Alert)}
+		e.groups[key] = g
+	}
+	return g
+}
+
+// groupKey returns the key of the group of a target, which is the target sent in its notifications.
+func groupKey(target *probepb.Target) string {
+	return fmt.Sprintf("%s:%s", target.GetName(), target.GetBucketName())
+}
+
+// Evaluate evaluates the rules for a target after a probe run, and returns the notification of the target
+// to send if an alert fired or resolved, or if alerts are still firing and the repeat interval elapsed since
+// the last notification. Alerts that are still firing are not notified again until then.
+// Evaluate does not send the notification, so that the run of the target does not wait on the webhook,
+// the caller sends it with Send. While a notification of the target is being sent, no other notification
+// of the target is returned, the changes are sent by that Send once it completes.
+// Arguments:
+//	- target: the target that was probed.
+//	- obs: the result of the run.
+//	- now: the time of the run.
+// Returns:
+//	- n: returns the notification to send, nil if there is none.
+func (e *Engine) Evaluate(target *probepb.Target, obs Observation, now time.Time) *Notification {
+	g := e.groupFor(target)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.evaluated = now
+	if obs.Status == metrics.Success {
+		g.failures = 0
+	} else {
+		g.failures++
+	}
+	for _, r := range e.rules {
+		fires, description := r.evaluate(g.failures, &obs)
+		alert, firing := g.firing[r.name]
+		switch {
+		case fires && !firing:
+			g.firing[r.name] = &Alert{Rule: r.name, State: Firing, Description: description, StartsAtUnixSec: now.Unix()}
+			g.pending = true
+		case fires:
+			alert.Description = description
+		case firing:
+			g.resolve(r.name, now)
+		}
+	}
+	if !g.pending && (len(g.firing) == 0 || now.Sub(g.notified) < e.repeat) {
+		return nil
+	}
+	return e.take(g)
+}
+
+// Resolve resolves every alert of a target that is firing, when this instance gives up the target,
+// e.g. because it moved to another shard or another instance holds its lease. The instance that
+// takes over the target does not know about these alerts, it fires them again if they still hold.
+// Arguments:
+//	- target: the target given up.
+//	- now: the time the target was given up.
+// Returns:
+//	- n: returns the notification of the resolved alerts to send with Send, nil if there is none.
+func (e *Engine) Resolve(target *probepb.Target, now time.Time) *Notification {
+	g := e.groupFor(target)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.evaluated = now
+	g.failures = 0
+	for _, r := range e.rules {
+		if _, ok := g.firing[r.name]; ok {
+			g.resolve(r.name, now)
+		}
+	}
+	if !g.pending {
+		return nil
+	}
+	return e.take(g)
+}
+
+// resolve moves the firing alert of a rule to the resolved alerts of the group.
+func (g *group) resolve(rule string, now time.Time) {
+	alert := g.firing[rule]
+	delete(g.firing, rule)
+	alert.State = Resolved
+	alert.EndsAtUnixSec = now.Unix()
+	g.resolved = append(g.resolved, alert)
+	g.pending = true
+}
+
+// take builds the notification of the alerts of a group and marks the group as sending it,
+// unless a notification of the group is already being sent. The group must be locked.
+func (e *Engine) take(g *group) *Notification {
+	if g.sending {
+		return nil
+	}
+	n := &Notification{
+		Probe:         e.probe,
+		Target:        groupKey(g.target),
+		StorageSystem: g.target.GetTargetSystem().String(),
+		State:         Resolved,
+	}
+	// The alerts are copied, as the alerts of the group change while the notification is sent.
+	for _, r := range e.rules {
+		if alert, ok := g.firing[r.name]; ok {
+			a := *alert
+			n.State = Firing
+			n.Alerts = append(n.Alerts, &a)
+		}
+	}
+	n.Alerts = append(n.Alerts, g.resolved...)
+	g.resolved = nil
+	g.pending = false
+	g.sending = true
+	g.sendingAt = g.evaluated
+	return n
+}
+
+// Send sends a notification returned by Evaluate or Resolve to the webhook, without holding the
+// alerts of the target, then sends the changes to the alerts of the target made while it was sent.
+// If a notification cannot be sent, its alerts are sent again with the next notification of the target.
+// Arguments:
+//	- ctx: the context of the webhook requests.
+//	- n: the notification to send, nothing is sent if it is nil.
+// Returns:
+//	- err: returns an error if a notification could not be sent.
+func (e *Engine) Send(ctx context.Context, n *Notification) error {
+	for n != nil {
+		err := e.webhook.Send(ctx, n)
+		e.mu.Lock()
+		g := e.groups[n.Target]
+		e.mu.Unlock()
+		g.mu.Lock()
+		g.sending = false
+		if err != nil {
+			var resolved []*Alert
+			for _, a := range n.Alerts {
+				if a.State == Resolved {
+					resolved = append(resolved, a)
+				}
+			}
+			g.resolved = append(resolved, g.resolved...)
+			g.pending = true
+			g.mu.Unlock()
+			return fmt.Errorf("target %q: failed to send the notification of %d alerts: %w", n.Target, len(n.Alerts), err)
+		}
+		g.notified = g.sendingAt
+		n = nil
+		if g.pending {
+			n = e.take(g)
+		}
+		g.mu.Unlock()
+	}
+	return nil
+}
@@ -0,0 +1,340 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+// receiver is a webhook receiving notifications, which fails the requests while failing is set.
+type receiver struct {
+	mu            sync.Mutex
+	notifications []*Notification
+	failing       bool
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failing {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	n := &Notification{}
+	if err := json.NewDecoder(req.Body).Decode(n); err != nil || req.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	r.notifications = append(r.notifications, n)
+}
+
+// take returns the notifications received since the last call.
+func (r *receiver) take() []*Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := r.notifications
+	r.notifications = nil
+	return n
+}
+
+// summary returns the state of a notification and the rule and state of each of its alerts.
+func summary(n *Notification) string {
+	s := n.State
+	for _, a := range n.Alerts {
+		s += fmt.Sprintf(" %s:%s", a.Rule, a.State)
+	}
+	return s
+}
+
+func newTestEngine(t *testing.T, url string, rules ...*probepb.AlertRule) *Engine {
+	t.Helper()
+	e, err := New("alert_test", &probepb.AlertingConfig{WebhookUrl: proto.String(url), Rules: rules}, []int64{3600})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	return e
+}
+
+// evaluate evaluates the rules for a target after a run and sends the notification returned, if any.
+func evaluate(ctx context.Context, e *Engine, target *probepb.Target, obs Observation, now time.Time) error {
+	return e.Send(ctx, e.Evaluate(target, obs, now))
+}
+
+var testTarget = &probepb.Target{
+	Name:         "hermes",
+	TargetSystem: probepb.Target_GOOGLE_CLOUD_STORAGE,
+	BucketName:   "test_bucket_alert",
+}
+
+func TestEvaluate(t *testing.T) {
+	r := &receiver{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+	e := newTestEngine(t, server.URL,
+		&probepb.AlertRule{Name: proto.String("failing"), ConsecutiveFailures: proto.Int32(2)},
+		&probepb.AlertRule{Name: proto.String("corrupted"), Status: proto.String("file_corrupted")},
+	)
+	ctx := context.Background()
+	now := time.Now()
+	failed := errors.New("probe failed")
+
+	tests := []struct {
+		desc   string
+		status metrics.ExitStatus
+		want   []string
+	}{
+		{desc: "first failure", status: metrics.ProbeFailed},
+		{desc: "second failure", status: metrics.ProbeFailed, want: []string{"firing failing:firing"}},
+		{desc: "alert still firing", status: metrics.ProbeFailed},
+		{desc: "corrupted file", status: metrics.FileCorrupted, want: []string{"firing failing:firing corrupted:firing"}},
+		{desc: "success", status: metrics.Success, want: []string{"resolved failing:resolved corrupted:resolved"}},
+		{desc: "still succeeding", status: metrics.Success},
+	}
+	for i, tc := range tests {
+		obs := Observation{Status: tc.status}
+		if tc.status != metrics.Success {
+			obs.Err = failed
+		}
+		if err := evaluate(ctx, e, testTarget, obs, now.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("%s: Evaluate() failed: %v", tc.desc, err)
+		}
+		var got []string
+		for _, n := range r.take() {
+			got = append(got, summary(n))
+			if n.Target != "hermes:test_bucket_alert" || n.Probe != "alert_test" {
+				t.Errorf("%s: Evaluate() sent a notification of probe %q and target %q, want probe %q and target %q", tc.desc, n.Probe, n.Target, "alert_test", "hermes:test_bucket_alert")
+			}
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("%s: Evaluate() sent %q, want %q", tc.desc, got, tc.want)
+		}
+	}
+}
+
+func TestEvaluateGroupsPerTarget(t *testing.T) {
+	r := &receiver{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+	e := newTestEngine(t, server.URL, &probepb.AlertRule{Name: proto.String("failing"), ConsecutiveFailures: proto.Int32(1)})
+	ctx := context.Background()
+	other := proto.Clone(testTarget).(*probepb.Target)
+	other.BucketName = "test_bucket_alert_2"
+
+	if err := evaluate(ctx, e, testTarget, Observation{Status: metrics.ProbeFailed}, time.Now()); err != nil {
+		t.Fatalf("Evaluate() failed: %v", err)
+	}
+	if err := evaluate(ctx, e, other, Observation{Status: metrics.ProbeFailed}, time.Now()); err != nil {
+		t.Fatalf("Evaluate() failed: %v", err)
+	}
+	got := r.take()
+	if len(got) != 2 || got[0].Target == got[1].Target {
+		t.Fatalf("Evaluate() of two failing targets sent %d notifications, want one per target", len(got))
+	}
+	// The first target recovers, which does not resolve the alert of the other target.
+	if err := evaluate(ctx, e, testTarget, Observation{Status: metrics.Success}, time.Now()); err != nil {
+		t.Fatalf("Evaluate() failed: %v", err)
+	}
+	if err := evaluate(ctx, e, other, Observation{Status: metrics.ProbeFailed}, time.Now()); err != nil {
+		t.Fatalf("Evaluate() failed: %v", err)
+	}
+	got = r.take()
+	if len(got) != 1 || got[0].Target != "hermes:test_bucket_alert" || summary(got[0]) != "resolved failing:resolved" {
+		t.Errorf("Evaluate() after one target recovered sent %v, want only the resolution of that target", got)
+	}
+}
+
+func TestEvaluateRepeatAndRetry(t *testing.T) {
+	r := &receiver{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+	e := newTestEngine(t, server.URL, &probepb.AlertRule{Name: proto.String("failing"), ConsecutiveFailures: proto.Int32(1)})
+	ctx := context.Background()
+	now := time.Now()
+	obs := Observation{Status: metrics.ProbeFailed}
+
+	r.failing = true
+	if err := evaluate(ctx, e, testTarget, obs, now); err == nil {
+		t.Errorf("Evaluate() with a failing webhook succeeded, want an error")
+	}
+	r.failing = false
+	if err := evaluate(ctx, e, testTarget, obs, now.Add(time.Minute)); err != nil {
+		t.Fatalf("Evaluate() failed: %v", err)
+	}
+	if got := r.take(); len(got) != 1 || summary(got[0]) != "firing failing:firing" || got[0].Alerts[0].StartsAtUnixSec != now.Unix() {
+		t.Errorf("Evaluate() after the webhook recovered sent %v, want the alert that failed to be sent", got)
+	}
+
+	if err := evaluate(ctx, e, testTarget, obs, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Evaluate() failed: %v", err)
+	}
+	if got := r.take(); len(got) != 0 {
+		t.Errorf("Evaluate() within the repeat interval sent %d notifications, want 0", len(got))
+	}
+	if err := evaluate(ctx, e, testTarget, obs, now.Add(defaultRepeatInterval+time.Minute)); err != nil {
+		t.Fatalf("Evaluate() failed: %v", err)
+	}
+	if got := r.take(); len(got) != 1 || summary(got[0]) != "firing failing:firing" {
+		t.Errorf("Evaluate() after the repeat interval sent %v, want the firing alert again", got)
+	}
+}
+
+func TestSendWithoutHoldingAlerts(t *testing.T) {
+	r := &receiver{}
+	release := make(chan struct{})
+	received := make(chan struct{}, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		received <- struct{}{}
+		<-release
+		r.ServeHTTP(w, req)
+	}))
+	defer server.Close()
+	e := newTestEngine(t, server.URL, &probepb.AlertRule{Name: proto.String("failing"), ConsecutiveFailures: proto.Int32(1)})
+	ctx := context.Background()
+	now := time.Now()
+
+	n := e.Evaluate(testTarget, Observation{Status: metrics.ProbeFailed}, now)
+	if n == nil {
+		t.Fatalf("Evaluate() of a failed run returned no notification, want the firing alert")
+	}
+	done := make(chan error, 1)
+	go func() { done <- e.Send(ctx, n) }()
+	<-received
+
+	// The target is evaluated while the notification is sent, its resolution is sent once the notification completed.
+	if n := e.Evaluate(testTarget, Observation{Status: metrics.Success}, now.Add(time.Minute)); n != nil {
+		t.Errorf("Evaluate() while a notification is sent = %v, want nil", n)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("Send() failed: %v", err)
+	}
+	var got []string
+	for _, n := range r.take() {
+		got = append(got, summary(n))
+	}
+	if want := []string{"firing failing:firing", "resolved failing:resolved"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Send() sent %q, want %q", got, want)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	r := &receiver{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+	e := newTestEngine(t, server.URL, &probepb.AlertRule{Name: proto.String("failing"), ConsecutiveFailures: proto.Int32(2)})
+	ctx := context.Background()
+	now := time.Now()
+
+	if n := e.Resolve(testTarget, now); n != nil {
+		t.Errorf("Resolve() without firing alerts = %v, want nil", n)
+	}
+	for i := 0; i < 2; i++ {
+		if err := evaluate(ctx, e, testTarget, Observation{Status: metrics.ProbeFailed}, now); err != nil {
+			t.Fatalf("Evaluate() failed: %v", err)
+		}
+	}
+	r.take()
+	if err := e.Send(ctx, e.Resolve(testTarget, now.Add(time.Minute))); err != nil {
+		t.Fatalf("Send() failed: %v", err)
+	}
+	if got := r.take(); len(got) != 1 || summary(got[0]) != "resolved failing:resolved" || got[0].Alerts[0].EndsAtUnixSec != now.Add(time.Minute).Unix() {
+		t.Errorf("Resolve() sent %v, want the resolution of the firing alert", got)
+	}
+	// The consecutive failures are reset, so that the alert fires again only after two more failures.
+	if err := evaluate(ctx, e, testTarget, Observation{Status: metrics.ProbeFailed}, now.Add(2*time.Minute)); err != nil {
+		t.Fatalf("Evaluate() failed: %v", err)
+	}
+	if got := r.take(); len(got) != 0 {
+		t.Errorf("Evaluate() of the first failure after Resolve() sent %v, want nothing", got)
+	}
+}
+
+func TestEvaluateBurnRate(t *testing.T) {
+	r := &receiver{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+	e := newTestEngine(t, server.URL, &probepb.AlertRule{Name: proto.String("burn"), BurnRate: proto.Float64(2), BurnRateWindowSec: proto.Int64(3600)})
+	ctx := context.Background()
+
+	tests := []struct {
+		desc      string
+		remaining float64
+		want      string
+	}{
+		{desc: "budget consumed at its rate", remaining: 0},
+		{desc: "budget consumed at three times its rate", remaining: -2, want: "firing burn:firing"},
+		{desc: "budget consumed at half its rate", remaining: 0.5, want: "resolved burn:resolved"},
+	}
+	for _, tc := range tests {
+		obs := Observation{
+			Status:     metrics.Success,
+			Indicators: []*probepb.SLI{{WindowSec: 3600, Runs: 10, ErrorBudgetRemaining: tc.remaining}},
+		}
+		if err := evaluate(ctx, e, testTarget, obs, time.Now()); err != nil {
+			t.Fatalf("%s: Evaluate() failed: %v", tc.desc, err)
+		}
+		var got string
+		if n := r.take(); len(n) > 0 {
+			got = summary(n[0])
+		}
+		if got != tc.want {
+			t.Errorf("%s: Evaluate() sent %q, want %q", tc.desc, got, tc.want)
+		}
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	if e, err := New("alert_test", nil, nil); e != nil || err != nil {
+		t.Errorf("New(nil) = %v, %v, want nil, nil", e, err)
+	}
+	tests := []struct {
+		desc string
+		url  string
+		rule *probepb.AlertRule
+	}{
+		{desc: "no webhook", rule: &probepb.AlertRule{Name: proto.String("r"), ConsecutiveFailures: proto.Int32(1)}},
+		{desc: "webhook that is not HTTP", url: "ftp://example.com/hook", rule: &probepb.AlertRule{Name: proto.String("r"), ConsecutiveFailures: proto.Int32(1)}},
+		{desc: "no name", url: "http://example.com/hook", rule: &probepb.AlertRule{ConsecutiveFailures: proto.Int32(1)}},
+		{desc: "no condition", url: "http://example.com/hook", rule: &probepb.AlertRule{Name: proto.String("r")}},
+		{desc: "two conditions", url: "http://example.com/hook", rule: &probepb.AlertRule{Name: proto.String("r"), ConsecutiveFailures: proto.Int32(1), Status: proto.String("file_corrupted")}},
+		{desc: "zero failures", url: "http://example.com/hook", rule: &probepb.AlertRule{Name: proto.String("r"), ConsecutiveFailures: proto.Int32(0)}},
+		{desc: "unknown status", url: "http://example.com/hook", rule: &probepb.AlertRule{Name: proto.String("r"), Status: proto.String("FileCorrupted")}},
+		{desc: "burn rate over a window that is not an SLO window", url: "http://example.com/hook", rule: &probepb.AlertRule{Name: proto.String("r"), BurnRate: proto.Float64(2), BurnRateWindowSec: proto.Int64(60)}},
+	}
+	for _, tc := range tests {
+		conf := &probepb.AlertingConfig{WebhookUrl: proto.String(tc.url), Rules: []*probepb.AlertRule{tc.rule}}
+		if _, err := New("alert_test", conf, []int64{3600}); err == nil {
+			t.Errorf("%s: New() succeeded, want an error", tc.desc)
+		}
+	}
+	rule := &probepb.AlertRule{Name: proto.String("r"), ConsecutiveFailures: proto.Int32(1)}
+	conf := &probepb.AlertingConfig{WebhookUrl: proto.String("http://example.com/hook"), Rules: []*probepb.AlertRule{rule, rule}}
+	if _, err := New("alert_test", conf, nil); err == nil {
+		t.Errorf("New() with two rules of the same name succeeded, want an error")
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bootstrap implements the preparation of a target for probing:
+// creating its bucket, creating every file ID and writing its NIL file.
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/google/cloudprober/logger"
+	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+	"github.com/googleinterns/step224-2020/hermes/probe/checknil"
+	"github.com/googleinterns/step224-2020/hermes/probe/create"
+	"github.com/googleinterns/step224-2020/hermes/probe/journal"
+	"github.com/googleinterns/step224-2020/hermes/probe/layout"
+	"github.com/googleinterns/step224-2020/hermes/probe/lease"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	apiLatency           = "hermes_api_latency_seconds"
+	defaultFilesPerSec   = 1
+	defaultFileSizeBytes = 1000
+)
+
+// FileSize returns the size in bytes of the files created on the target.
+func FileSize(target *target.Target) int {
+	if size := target.Target.GetFileSizeBytes(); size > 0 {
+		return int(size)
+	}
+	return defaultFileSizeBytes
+}
+
+// createInterval returns the minimum time between the creation of two files when bootstrapping the target.
+func createInterval(target *target.Target) time.Duration {
+	rate := target.Target.GetBootstrapFilesPerSec()
+	if rate <= 0 {
+		rate = defaultFilesPerSec
+	}
+	return time.Duration(float64(time.Second) / rate)
+}
+
+// ensureBucket creates the target bucket if it does not exist.
+func ensureBucket(ctx context.Context, target *target.Target, client stiface.Client, logger *logger.Logger) error {
+	bucketName := target.Target.GetBucketName()
+	bucket := client.Bucket(bucketName)
+	_, err := bucket.Attrs(ctx)
+	if err == nil {
+		return nil
+	}
+	if err != storage.ErrBucketNotExist {
+		return metrics.NewProbeError(metrics.APICallFailed, fmt.Errorf("could not get attributes of bucket %q: %w", bucketName, err))
+	}
+	// TODO(#77): Refactor timing into using function from metrics.go
+	start := time.Now()
+	if err := bucket.Create(ctx, target.Target.GetName(), nil); err != nil {
+		target.LatencyMetrics.APICallLatency[metrics.APICreateBucket][metrics.APICallFailed].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+		return metrics.NewProbeError(metrics.APICallFailed, fmt.Errorf("could not create bucket %q in project %q: %w", bucketName, target.Target.GetName(), err))
+	}
+	target.LatencyMetrics.APICallLatency[metrics.APICreateBucket][metrics.Success].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+	logger.Infof("Bucket %q created in project %q.", bucketName, target.Target.GetName())
+	return nil
+}
+
+// hasHermesFiles reports whether the target bucket holds any Hermes files other than the NIL file and the lease.
+func hasHermesFiles(ctx context.Context, target *target.Target, client stiface.Client) (bool, error) {
+	objects := client.Bucket(target.Target.GetBucketName()).Objects(ctx, &storage.Query{Prefix: checknil.HermesFilePrefix})
+	for {
+		obj, err := objects.Next()
+		if err == iterator.Done {
+			return false, nil
+		}
+		if err != nil {
+			status := metrics.APICallFailed
+			if err == storage.ErrBucketNotExist {
+				status = metrics.BucketMissing
+			}
+			return false, metrics.NewProbeError(status, fmt.Errorf("could not list bucket %q: %w", target.Target.GetBucketName(), err))
+		}
+		if obj.Name != journal.NilFileName && obj.Name != lease.FileName {
+			return true, nil
+		}
+	}
+}
+
+// loadJournal replaces the journal of the target with the latest journal in the stores, see journal.Reconcile.
+// A target without a journal in any store is new and keeps its empty journal, as long as its bucket holds no Hermes files.
+func loadJournal(ctx context.Context, target *target.Target, client stiface.Client, stores []journal.Store, logger *logger.Logger) error {
+	j, err := journal.Reconcile(ctx, target, stores, logger)
+	switch metrics.StatusOf(err) {
+	case metrics.Success:
+		target.Journal = j
+		return nil
+	case metrics.FileMissing:
+		found, err := hasHermesFiles(ctx, target, client)
+		if err != nil {
+			return err
+		}
+		if found {
+			return metrics.NewProbeError(metrics.UnknownFileFound, fmt.Errorf("bucket %q holds Hermes files but no NIL file %q", target.Target.GetBucketName(), journal.NilFileName))
+		}
+		return nil
+	default:
+		return err
+	}
+}
+
+// Bootstrap prepares a target for probing. It creates the target bucket if the target is configured to,
+// loads and reconciles the journal from the journal stores, creates every file ID in the file layout
+// of the target missing from the journal at a limited rate and writes the journal to the stores.
+// Bootstrapping a target whose files all exist only reads its journal.
+// The journal is written after every file is created, so an interrupted bootstrap resumes on the next call.
+// Arguments:
+//	- ctx: the context of the probe run.
+//	- target: the target to be bootstrapped.
+//	- client: initialised storage client for this target system.
+//	- stores: the stores holding copies of the journal of the target.
+//	- logger: logger associated with the probe calling this function.
+// Returns:
+//	- err: returns an error if the target could not be bootstrapped, including:
+//		- UnknownFileFound: the bucket holds Hermes files but no NIL file.
+//		- BucketMissing: the bucket does not exist and the target is not configured to create it.
+//		- InvalidArgument: the file layout of the target is invalid.
+func Bootstrap(ctx context.Context, target *target.Target, client stiface.Client, stores []journal.Store, logger *logger.Logger) error {
+	bucketName := target.Target.GetBucketName()
+	l, err := layout.New(target.Target.GetFileLayout())
+	if err != nil {
+		return metrics.NewProbeError(metrics.InvalidArgument, fmt.Errorf("Bootstrap(%q) failed: %w", bucketName, err))
+	}
+	if target.Target.GetCreateBucket() {
+		if err := ensureBucket(ctx, target, client, logger); err != nil {
+			return fmt.Errorf("Bootstrap(%q) failed: %w", bucketName, err)
+		}
+	}
+	if err := loadJournal(ctx, target, client, stores, logger); err != nil {
+		return fmt.Errorf("Bootstrap(%q) failed: %w", bucketName, err)
+	}
+
+	var missing []int32
+	for _, id := range l.FileIDs() {
+		if _, ok := target.Journal.Filenames[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	logger.Infof("Bootstrap(%q): creating %d files.", bucketName, len(missing))
+
+	// The journal is written after every file is created so that an interrupted bootstrap can resume.
+	ticker := time.NewTicker(createInterval(target))
+	defer ticker.Stop()
+	for i, id := range missing {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("Bootstrap(%q) interrupted after creating %d of %d files: %w", bucketName, i, len(missing), ctx.Err())
+			case <-ticker.C:
+			}
+		}
+		if err := create.CreateFile(ctx, target, id, FileSize(target), client, logger); err != nil {
+			return fmt.Errorf("Bootstrap(%q) failed to create file %d: %w", bucketName, id, err)
+		}
+		if err := journal.WriteAll(ctx, target, stores); err != nil {
+			return fmt.Errorf("Bootstrap(%q) failed to record file %d: %w", bucketName, id, err)
+		}
+	}
+	logger.Infof("Bootstrap(%q): created %d files.", bucketName, len(missing))
+	return nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/googleinterns/step224-2020/hermes/probe/fakegcs"
+	"github.com/googleinterns/step224-2020/hermes/probe/journal"
+	"github.com/googleinterns/step224-2020/hermes/probe/layout"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/probetest"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+)
+
+const bucketName = "test_bucket_bootstrap"
+
+// genTestTarget generates a target of 100 byte files with an empty journal.
+func genTestTarget(t *testing.T, createBucket bool, filesPerSec float64) *target.Target {
+	t.Helper()
+	target := probetest.NewTarget(t, "bootstrap_test", probetest.TargetConfig(bucketName))
+	target.Target.CreateBucket = createBucket
+	target.Target.BootstrapFilesPerSec = filesPerSec
+	target.Target.FileSizeBytes = 100
+	return target
+}
+
+func TestBootstrapNewTarget(t *testing.T) {
+	ctx := context.Background()
+	client := fakegcs.NewClient()
+	target := genTestTarget(t, true, 1000)
+	logger := fakegcs.NewLogger(ctx).Logger
+
+	if err := Bootstrap(ctx, target, client, []journal.Store{journal.NewBucketStore(client)}, logger); err != nil {
+		t.Fatalf("Bootstrap() failed: %v", err)
+	}
+	want := int(layout.Default().NumFiles)
+	if got := len(target.Journal.Filenames); got != want {
+		t.Errorf("Bootstrap() created %d files, want %d", got, want)
+	}
+	j, err := journal.Read(ctx, target, client)
+	if err != nil {
+		t.Fatalf("journal.Read() after Bootstrap() failed: %v", err)
+	}
+	if !proto.Equal(j, target.Journal) {
+		t.Errorf("journal.Read() = %v, want the journal of the target %v", j, target.Journal)
+	}
+
+	// A second bootstrap of the same target only loads the journal.
+	again := genTestTarget(t, true, 1000)
+	if err := Bootstrap(ctx, again, client, []journal.Store{journal.NewBucketStore(client)}, logger); err != nil {
+		t.Fatalf("Bootstrap() of a bootstrapped target failed: %v", err)
+	}
+	if !proto.Equal(again.Journal, target.Journal) {
+		t.Errorf("Bootstrap() of a bootstrapped target changed the journal to %v, want %v", again.Journal, target.Journal)
+	}
+}
+
+func TestBootstrapResumesAtLimitedRate(t *testing.T) {
+	ctx := context.Background()
+	client := fakegcs.NewClient()
+	target := genTestTarget(t, true, 1000)
+	logger := fakegcs.NewLogger(ctx).Logger
+	if err := Bootstrap(ctx, target, client, []journal.Store{journal.NewBucketStore(client)}, logger); err != nil {
+		t.Fatalf("Bootstrap() failed: %v", err)
+	}
+
+	// Remove the last few files, as if the bootstrap had been interrupted.
+	missing := int32(3)
+	for id := layout.Default().MaxFileID() - missing + 1; id <= layout.Default().MaxFileID(); id++ {
+		if err := client.Bucket(bucketName).Object(target.Journal.Filenames[id]).Delete(ctx); err != nil {
+			t.Fatalf("failed to delete file %d: %v", id, err)
+		}
+		journal.RemoveFile(target.Journal, id)
+	}
+	if err := journal.Write(ctx, target, client); err != nil {
+		t.Fatalf("journal.Write() failed: %v", err)
+	}
+
+	resumed := genTestTarget(t, false, 20)
+	start := time.Now()
+	if err := Bootstrap(ctx, resumed, client, []journal.Store{journal.NewBucketStore(client)}, logger); err != nil {
+		t.Fatalf("Bootstrap() of an interrupted target failed: %v", err)
+	}
+	if got, want := time.Since(start), time.Duration(missing-1)*50*time.Millisecond; got < want {
+		t.Errorf("Bootstrap() created %d files in %v, want at least %v at 20 files per second", missing, got, want)
+	}
+	if got, want := len(resumed.Journal.Filenames), int(layout.Default().NumFiles); got != want {
+		t.Errorf("Bootstrap() resumed with %d files, want %d", got, want)
+	}
+}
+
+func TestBootstrapErrors(t *testing.T) {
+	ctx := context.Background()
+	logger := fakegcs.NewLogger(ctx).Logger
+
+	client := fakegcs.NewClient()
+	if err := Bootstrap(ctx, genTestTarget(t, false, 1000), client, []journal.Store{journal.NewBucketStore(client)}, logger); metrics.StatusOf(err) != metrics.BucketMissing {
+		t.Errorf("Bootstrap() without a bucket = %v, want status %q", err, metrics.ExitStatusName[metrics.BucketMissing])
+	}
+
+	if err := client.Bucket(bucketName).Create(ctx, bucketName, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	w := client.Bucket(bucketName).Object("Hermes_07_leftover").NewWriter(ctx)
+	if _, err := w.Write([]byte("abc123")); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close file: %v", err)
+	}
+	if err := Bootstrap(ctx, genTestTarget(t, false, 1000), client, []journal.Store{journal.NewBucketStore(client)}, logger); metrics.StatusOf(err) != metrics.UnknownFileFound {
+		t.Errorf("Bootstrap() of a bucket with Hermes files but no NIL file = %v, want status %q", err, metrics.ExitStatusName[metrics.UnknownFileFound])
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package checknil implements the check nil probe operation, which sweeps the
+// target bucket and checks that its contents are consistent with the StateJournal.
+package checknil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/google/cloudprober/logger"
+	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+	"github.com/googleinterns/step224-2020/hermes/probe/create"
+	"github.com/googleinterns/step224-2020/hermes/probe/journal"
+	"github.com/googleinterns/step224-2020/hermes/probe/lease"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	// HermesFilePrefix is the prefix of the names of all files created by Hermes.
+	HermesFilePrefix = "Hermes_"
+	apiLatency       = "hermes_api_latency_seconds"
+	probeLatency     = "hermes_probe_latency_seconds"
+)
+
+// Result holds the differences between the contents of the target bucket and the StateJournal.
+type Result struct {
+	// Missing maps the IDs of files in the journal that were not found in the bucket to their filenames.
+	Missing map[int32]string
+	// Unknown holds the names of Hermes files found in the bucket that are not in the journal.
+	Unknown []string
+	// Aborted holds the names of incomplete uploads left in the bucket by AbortUpload, see create.AbortedFilePrefix.
+	Aborted []string
+	// Foreign holds the names of files found in the bucket that were not created by Hermes.
+	// They are reported, but do not affect the status of the result.
+	Foreign []string
+	// JournalSize is the number of files in the journal when the sweep was run.
+	JournalSize int
+}
+
+// Status returns the exit status summarising the result.
+// Missing files take precedence over unknown files as they indicate data loss.
+// Files not created by Hermes and aborted uploads do not affect the status.
+// Returns:
+//	- status:
+//		- AllFilesMissing: none of the files in the journal were found.
+//		- FileMissing: some of the files in the journal were not found.
+//		- UnknownFileFound: a Hermes file not in the journal was found.
+//		- Success: the Hermes files in the bucket are consistent with the journal.
+func (r *Result) Status() metrics.ExitStatus {
+	switch {
+	case r.JournalSize > 0 && len(r.Missing) == r.JournalSize:
+		return metrics.AllFilesMissing
+	case len(r.Missing) > 0:
+		return metrics.FileMissing
+	case len(r.Unknown) > 0:
+		return metrics.UnknownFileFound
+	default:
+		return metrics.Success
+	}
+}
+
+// String returns a summary of the differences in the result.
+func (r *Result) String() string {
+	missing := make([]string, 0, len(r.Missing))
+	for _, name := range r.Missing {
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+	return fmt.Sprintf("missing: %v, unknown: %v, aborted: %v, foreign: %v", missing, r.Unknown, r.Aborted, r.Foreign)
+}
+
+// listBucket lists the names of every file in the target bucket.
+func listBucket(ctx context.Context, target *target.Target, client stiface.Client) ([]string, error) {
+	// TODO(#77): Refactor timing into using function from metrics.go
+	start := time.Now()
+	objects := client.Bucket(target.Target.GetBucketName()).Objects(ctx, nil)
+	var names []string
+	for {
+		obj, err := objects.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			status := metrics.APICallFailed
+			if err == storage.ErrBucketNotExist {
+				status = metrics.BucketMissing
+			}
+			target.LatencyMetrics.APICallLatency[metrics.APIListFiles][status].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+			return nil, metrics.NewProbeError(status, fmt.Errorf("could not list bucket %q: %w", target.Target.GetBucketName(), err))
+		}
+		names = append(names, obj.Name)
+	}
+	target.LatencyMetrics.APICallLatency[metrics.APIListFiles][metrics.Success].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+	return names, nil
+}
+
+// Compare compares the names of the files in a bucket with the filenames in the journal of the target.
+// Arguments:
+//	- target: the target whose journal the names are compared against.
+//	- names: the names of every file in the bucket.
+// Returns:
+//	- result: returns the differences between the bucket and the journal.
+func Compare(target *target.Target, names []string) *Result {
+	filenames := target.Journal.GetFilenames()
+	inJournal := make(map[string]bool, len(filenames))
+	for _, name := range filenames {
+		inJournal[name] = true
+	}
+	listed := make(map[string]bool, len(names))
+	result := &Result{Missing: make(map[int32]string), JournalSize: len(filenames)}
+	for _, name := range names {
+		listed[name] = true
+		switch {
+		case inJournal[name], name == journal.NilFileName, name == lease.FileName:
+		case strings.HasPrefix(name, QuarantinePrefix):
+			// Quarantined files are awaiting review and are not reported again.
+		case strings.HasPrefix(name, create.AbortedFilePrefix):
+			result.Aborted = append(result.Aborted, name)
+		case strings.HasPrefix(name, HermesFilePrefix):
+			result.Unknown = append(result.Unknown, name)
+		default:
+			result.Foreign = append(result.Foreign, name)
+		}
+	}
+	for id, name := range filenames {
+		if !listed[name] {
+			result.Missing[id] = name
+		}
+	}
+	sort.Strings(result.Unknown)
+	sort.Strings(result.Aborted)
+	sort.Strings(result.Foreign)
+	return result
+}
+
+// CheckNil lists every file in the target bucket and checks that the Hermes files in it are exactly
+// the files in the StateJournal of the target. Files not created by Hermes are logged and counted in the
+// ForeignFiles gauge of the target, except for files under the QuarantinePrefix, but do not fail the check.
+// Unknown Hermes files can be passed to Remediate and aborted uploads to DeleteAborted.
+// The FileMissing status of every file missing from the bucket is recorded in its journal entry.
+// Arguments:
+//	- ctx: the context of the probe run.
+//	- target: the target to be checked.
+//	- client: initialised storage client for this target system.
+//	- logger: logger associated with the probe calling this function.
+// Returns:
+//	- result: returns the differences found, or nil if the bucket could not be listed.
+//	- err: returns a *metrics.ProbeError with the status of the result if any differences were found,
+//	  or with the status of the failed API call if the bucket could not be listed.
+func CheckNil(ctx context.Context, target *target.Target, client stiface.Client, logger *logger.Logger) (*Result, error) {
+	// TODO(#77): Refactor timing into using function from metrics.go
+	start := time.Now()
+	names, err := listBucket(ctx, target, client)
+	if err != nil {
+		target.LatencyMetrics.ProbeOpLatency[metrics.CheckNil][metrics.StatusOf(err)].Metric(probeLatency).AddFloat64(time.Now().Sub(start).Seconds())
+		return nil, fmt.Errorf("CheckNil(%q) failed: %w", target.Target.GetBucketName(), err)
+	}
+	result := Compare(target, names)
+	status := result.Status()
+	target.LatencyMetrics.ProbeOpLatency[metrics.CheckNil][status].Metric(probeLatency).AddFloat64(time.Now().Sub(start).Seconds())
+
+	for id, name := range result.Missing {
+		logger.Warningf("CheckNil(%q): file %d %q is in the journal but not in the bucket.", target.Target.GetBucketName(), id, name)
+		journal.RecordStatus(target.Journal, id, metrics.FileMissing, start)
+	}
+	for _, name := range result.Unknown {
+		logger.Warningf("CheckNil(%q): Hermes file %q is in the bucket but not in the journal.", target.Target.GetBucketName(), name)
+	}
+	for _, name := range result.Aborted {
+		logger.Warningf("CheckNil(%q): aborted upload %q was left in the bucket.", target.Target.GetBucketName(), name)
+	}
+	for _, name := range result.Foreign {
+		logger.Warningf("CheckNil(%q): file %q was not created by Hermes.", target.Target.GetBucketName(), name)
+	}
+	target.LatencyMetrics.ForeignFiles = metrics.NewForeignFilesGauge(target.Target, len(result.Foreign))
+	if status != metrics.Success {
+		return result, metrics.NewProbeError(status, fmt.Errorf("CheckNil(%q): bucket is inconsistent with the journal: %v", target.Target.GetBucketName(), result))
+	}
+	return result, nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checknil
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+	"github.com/googleinterns/step224-2020/hermes/probe/fakegcs"
+	"github.com/googleinterns/step224-2020/hermes/probe/journal"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/probetest"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+
+	journalpb "github.com/googleinterns/step224-2020/hermes/proto"
+)
+
+const bucketName = "test_bucket_checknil"
+
+// genTestTarget generates a target with the file names given in its journal.
+func genTestTarget(t *testing.T, filenames map[int32]string) *target.Target {
+	t.Helper()
+	target := probetest.NewTarget(t, "checknil_test", probetest.TargetConfig(bucketName))
+	target.Journal.Filenames = filenames
+	return target
+}
+
+// createTestFiles creates the test bucket and a file for each of the names given.
+func createTestFiles(ctx context.Context, t *testing.T, client stiface.Client, names ...string) {
+	t.Helper()
+	if err := client.Bucket(bucketName).Create(ctx, bucketName, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	for _, name := range names {
+		w := client.Bucket(bucketName).Object(name).NewWriter(ctx)
+		if _, err := w.Write([]byte("abc123")); err != nil {
+			t.Fatalf("failed to write file %q: %v", name, err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("failed to close file %q: %v", name, err)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	journal := map[int32]string{
+		1: "Hermes_01_a",
+		2: "Hermes_02_b",
+	}
+	tests := []struct {
+		desc       string
+		names      []string
+		want       *Result
+		wantStatus metrics.ExitStatus
+	}{
+		{
+			desc:       "consistent",
+			names:      []string{"Hermes_01_a", "Hermes_02_b"},
+			want:       &Result{Missing: map[int32]string{}, JournalSize: 2},
+			wantStatus: metrics.Success,
+		},
+		{
+			desc:       "one missing",
+			names:      []string{"Hermes_02_b"},
+			want:       &Result{Missing: map[int32]string{1: "Hermes_01_a"}, JournalSize: 2},
+			wantStatus: metrics.FileMissing,
+		},
+		{
+			desc:       "all missing",
+			names:      nil,
+			want:       &Result{Missing: map[int32]string{1: "Hermes_01_a", 2: "Hermes_02_b"}, JournalSize: 2},
+			wantStatus: metrics.AllFilesMissing,
+		},
+		{
+			desc:       "unknown Hermes file",
+			names:      []string{"Hermes_01_a", "Hermes_02_b", "Hermes_02_old"},
+			want:       &Result{Missing: map[int32]string{}, Unknown: []string{"Hermes_02_old"}, JournalSize: 2},
+			wantStatus: metrics.UnknownFileFound,
+		},
+		{
+			desc:       "foreign file",
+			names:      []string{"Hermes_01_a", "Hermes_02_b", "notes.txt"},
+			want:       &Result{Missing: map[int32]string{}, Foreign: []string{"notes.txt"}, JournalSize: 2},
+			wantStatus: metrics.Success,
+		},
+		{
+			desc:       "aborted upload",
+			names:      []string{"Hermes_01_a", "Hermes_02_b", "Hermes_aborted_02_00000000000000ff"},
+			want:       &Result{Missing: map[int32]string{}, Aborted: []string{"Hermes_aborted_02_00000000000000ff"}, JournalSize: 2},
+			wantStatus: metrics.Success,
+		},
+		{
+			desc:       "missing takes precedence over unknown",
+			names:      []string{"Hermes_02_b", "Hermes_01_other"},
+			want:       &Result{Missing: map[int32]string{1: "Hermes_01_a"}, Unknown: []string{"Hermes_01_other"}, JournalSize: 2},
+			wantStatus: metrics.FileMissing,
+		},
+	}
+	for _, tc := range tests {
+		got := Compare(genTestTarget(t, journal), tc.names)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: Compare() = %+v, want %+v", tc.desc, got, tc.want)
+		}
+		if status := got.Status(); status != tc.wantStatus {
+			t.Errorf("%s: Compare().Status() = %q, want %q", tc.desc, metrics.ExitStatusName[status], metrics.ExitStatusName[tc.wantStatus])
+		}
+	}
+}
+
+func TestCompareEmptyJournal(t *testing.T) {
+	if got := Compare(genTestTarget(t, map[int32]string{}), nil).Status(); got != metrics.Success {
+		t.Errorf("Compare() of an empty bucket and journal status = %q, want %q", metrics.ExitStatusName[got], metrics.ExitStatusName[metrics.Success])
+	}
+}
+
+func TestCheckNil(t *testing.T) {
+	ctx := context.Background()
+	client := fakegcs.NewClient()
+	createTestFiles(ctx, t, client, "Hermes_01_a", "Hermes_02_b", "Hermes_03_c", "other")
+	target := genTestTarget(t, map[int32]string{1: "Hermes_01_a", 2: "Hermes_02_b"})
+	logger := fakegcs.NewLogger(ctx).Logger
+
+	result, err := CheckNil(ctx, target, client, logger)
+	if got := metrics.StatusOf(err); got != metrics.UnknownFileFound {
+		t.Errorf("CheckNil() status = %q, want %q", metrics.ExitStatusName[got], metrics.ExitStatusName[metrics.UnknownFileFound])
+	}
+	if result == nil {
+		t.Fatalf("CheckNil() returned a nil result")
+	}
+	if want := []string{"Hermes_03_c"}; !reflect.DeepEqual(result.Unknown, want) {
+		t.Errorf("CheckNil().Unknown = %v, want %v", result.Unknown, want)
+	}
+	if want := []string{"other"}; !reflect.DeepEqual(result.Foreign, want) {
+		t.Errorf("CheckNil().Foreign = %v, want %v", result.Foreign, want)
+	}
+}
+
+func TestCheckNilForeignFiles(t *testing.T) {
+	ctx := context.Background()
+	client := fakegcs.NewClient()
+	createTestFiles(ctx, t, client, "Hermes_01_a", "Hermes_02_b", "notes.txt", "logs/today")
+	target := genTestTarget(t, map[int32]string{1: "Hermes_01_a", 2: "Hermes_02_b"})
+	logger := fakegcs.NewLogger(ctx).Logger
+
+	result, err := CheckNil(ctx, target, client, logger)
+	if err != nil {
+		t.Errorf("CheckNil() of a bucket with foreign files = %v, want nil", err)
+	}
+	if want := []string{"logs/today", "notes.txt"}; result == nil || !reflect.DeepEqual(result.Foreign, want) {
+		t.Errorf("CheckNil() = %+v, want foreign files %v", result, want)
+	}
+	if got, want := target.LatencyMetrics.ForeignFiles.Metric("hermes_foreign_files").String(), "2"; got != want {
+		t.Errorf("CheckNil() set the foreign files gauge to %s, want %s", got, want)
+	}
+}
+
+func TestCheckNilRecordsMissingFiles(t *testing.T) {
+	ctx := context.Background()
+	client := fakegcs.NewClient()
+	createTestFiles(ctx, t, client, "Hermes_01_a")
+	target := genTestTarget(t, map[int32]string{})
+	for id, name := range map[int32]string{1: "Hermes_01_a", 2: "Hermes_02_b"} {
+		journal.AddFile(target.Journal, id, &journalpb.FileEntry{Filename: name})
+	}
+	logger := fakegcs.NewLogger(ctx).Logger
+
+	if _, err := CheckNil(ctx, target, client, logger); metrics.StatusOf(err) != metrics.FileMissing {
+		t.Errorf("CheckNil() = %v, want status %q", err, metrics.ExitStatusName[metrics.FileMissing])
+	}
+	if entry := target.Journal.Files[2]; entry.GetLastStatus() != metrics.ExitStatusName[metrics.FileMissing] || entry.GetConsecutiveFailures() != 1 {
+		t.Errorf("CheckNil() recorded entry %v of the missing file 2, want one %q failure", entry, metrics.ExitStatusName[metrics.FileMissing])
+	}
+	if entry := target.Journal.Files[1]; entry.GetConsecutiveFailures() != 0 {
+		t.Errorf("CheckNil() recorded a failure of file 1 %v, which is in the bucket", entry)
+	}
+}
+
+func TestCheckNilBucketMissing(t *testing.T) {
+	ctx := context.Background()
+	target := genTestTarget(t, map[int32]string{})
+	logger := fakegcs.NewLogger(ctx).Logger
+
+	if _, err := CheckNil(ctx, target, fakegcs.NewClient(), logger); metrics.StatusOf(err) != metrics.BucketMissing {
+		t.Errorf("CheckNil() = %v, want status %q", err, metrics.ExitStatusName[metrics.BucketMissing])
+	}
+}
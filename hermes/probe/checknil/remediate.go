@@ -0,0 +1,258 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Remediate implements the remediation of unknown Hermes files found by CheckNil.
+
+package checknil
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/google/cloudprober/logger"
+	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+const (
+	// QuarantinePrefix is the prefix unknown Hermes files are moved under by the QUARANTINE remediation policy.
+	QuarantinePrefix = "quarantine/"
+	// MetadataOriginalNameKey is the custom metadata key for the name of a quarantined file before it was moved.
+	MetadataOriginalNameKey = "hermes_original_name"
+	// MetadataQuarantinedAtKey is the custom metadata key for the time at which a file was quarantined.
+	MetadataQuarantinedAtKey = "hermes_quarantined_at"
+	defaultGracePeriod       = 24 * time.Hour
+)
+
+// Action represents an action taken on an unknown Hermes file.
+type Action int
+
+const (
+	// Reported indicates that the file was reported and left in place.
+	Reported Action = iota
+	// Deferred indicates that the file is younger than the grace period and was left in place.
+	Deferred
+	// Quarantined indicates that the file was moved under the QuarantinePrefix.
+	Quarantined
+	// Deleted indicates that the file was deleted.
+	Deleted
+)
+
+// ActionName maps Action constants to their audit log string equivalent.
+var ActionName = map[Action]string{
+	Reported:    "reported",
+	Deferred:    "deferred",
+	Quarantined: "quarantined",
+	Deleted:     "deleted",
+}
+
+// AuditRecord records an action taken on an unknown Hermes file.
+type AuditRecord struct {
+	// Time at which the action was taken.
+	Time time.Time
+	// Action taken on the file.
+	Action Action
+	// Bucket the file was found in.
+	Bucket string
+	// Name of the file.
+	Name string
+	// Generation of the file the action was taken on.
+	Generation int64
+	// Destination is the name the file was moved to, if it was quarantined.
+	Destination string
+}
+
+// String returns the audit log entry for the record.
+func (r *AuditRecord) String() string {
+	return fmt.Sprintf("AUDIT time=%s action=%s bucket=%q file=%q generation=%d destination=%q",
+		r.Time.UTC().Format(time.RFC3339), ActionName[r.Action], r.Bucket, r.Name, r.Generation, r.Destination)
+}
+
+// gracePeriod returns the minimum age of an unknown file before it is deleted.
+func gracePeriod(target *target.Target) time.Duration {
+	if sec := target.Target.GetRemediationGracePeriodSec(); sec > 0 {
+		return time.Duration(sec) * time.Second
+	}
+	return defaultGracePeriod
+}
+
+// statusOf returns the exit status of a failed API call on a file.
+func statusOf(err error) metrics.ExitStatus {
+	switch err {
+	case storage.ErrObjectNotExist:
+		return metrics.FileMissing
+	case storage.ErrBucketNotExist:
+		return metrics.BucketMissing
+	default:
+		return metrics.APICallFailed
+	}
+}
+
+// getAttrs gets the attributes of a file and records the latency of the API call.
+func getAttrs(ctx context.Context, target *target.Target, file stiface.ObjectHandle) (*storage.ObjectAttrs, error) {
+	// TODO(#77): Refactor timing into using function from metrics.go
+	start := time.Now()
+	attrs, err := file.Attrs(ctx)
+	status := metrics.Success
+	if err != nil {
+		status = statusOf(err)
+	}
+	target.LatencyMetrics.APICallLatency[metrics.APIGetFileAttrs][status].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+	if err != nil {
+		return nil, metrics.NewProbeError(status, err)
+	}
+	return attrs, nil
+}
+
+// deleteGeneration deletes the generation of a file given and records the latency of the API call.
+// The generation precondition ensures that a file rewritten since it was listed is not deleted.
+func deleteGeneration(ctx context.Context, target *target.Target, file stiface.ObjectHandle, generation int64) error {
+	// TODO(#77): Refactor timing into using function from metrics.go
+	start := time.Now()
+	err := file.If(storage.Conditions{GenerationMatch: generation}).Delete(ctx)
+	status := metrics.Success
+	if err != nil {
+		status = statusOf(err)
+	}
+	target.LatencyMetrics.APICallLatency[metrics.APIDeleteFile][status].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+	if err != nil {
+		return metrics.NewProbeError(status, err)
+	}
+	return nil
+}
+
+// quarantine copies the file under the QuarantinePrefix and then deletes the original.
+func quarantine(ctx context.Context, target *target.Target, client stiface.Client, attrs *storage.ObjectAttrs) (string, error) {
+	bucket := client.Bucket(target.Target.GetBucketName())
+	destination := QuarantinePrefix + attrs.Name
+	copier := bucket.Object(destination).CopierFrom(bucket.Object(attrs.Name).If(storage.Conditions{GenerationMatch: attrs.Generation}))
+	copier.ObjectAttrs().ContentType = attrs.ContentType
+	copier.ObjectAttrs().Metadata = make(map[string]string, len(attrs.Metadata)+2)
+	for k, v := range attrs.Metadata {
+		copier.ObjectAttrs().Metadata[k] = v
+	}
+	copier.ObjectAttrs().Metadata[MetadataOriginalNameKey] = attrs.Name
+	copier.ObjectAttrs().Metadata[MetadataQuarantinedAtKey] = time.Now().UTC().Format(time.RFC3339)
+
+	// TODO(#77): Refactor timing into using function from metrics.go
+	start := time.Now()
+	_, err := copier.Run(ctx)
+	status := metrics.Success
+	if err != nil {
+		status = statusOf(err)
+	}
+	target.LatencyMetrics.APICallLatency[metrics.APICopyFile][status].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+	if err != nil {
+		return "", metrics.NewProbeError(status, fmt.Errorf("could not copy %q to %q: %w", attrs.Name, destination, err))
+	}
+	if err := deleteGeneration(ctx, target, bucket.Object(attrs.Name), attrs.Generation); err != nil {
+		return "", fmt.Errorf("copied %q to %q but could not delete it: %w", attrs.Name, destination, err)
+	}
+	return destination, nil
+}
+
+// Remediate applies the remediation policy of the target to the unknown Hermes files given.
+// Every action taken, including leaving a file in place, is written to the audit log.
+// Arguments:
+//	- ctx: the context of the probe run.
+//	- target: the target the files were found on.
+//	- client: initialised storage client for this target system.
+//	- logger: logger the audit log is written to.
+//	- unknown: the names of the unknown Hermes files, as found by CheckNil.
+// Returns:
+//	- records: returns the audit records of the actions taken.
+//	- err: returns an error if an action could not be taken on one of the files.
+//	  Remediation continues with the remaining files after an error.
+func Remediate(ctx context.Context, target *target.Target, client stiface.Client, logger *logger.Logger, unknown []string) ([]*AuditRecord, error) {
+	policy := target.Target.GetRemediationPolicy()
+	bucketName := target.Target.GetBucketName()
+	var records []*AuditRecord
+	var errs []error
+	for _, name := range unknown {
+		record := &AuditRecord{Action: Reported, Bucket: bucketName, Name: name}
+		if policy == probepb.Target_QUARANTINE || policy == probepb.Target_DELETE {
+			attrs, err := getAttrs(ctx, target, client.Bucket(bucketName).Object(name))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%q: %w", name, err))
+				continue
+			}
+			record.Generation = attrs.Generation
+			switch {
+			case policy == probepb.Target_QUARANTINE:
+				if record.Destination, err = quarantine(ctx, target, client, attrs); err != nil {
+					errs = append(errs, fmt.Errorf("%q: %w", name, err))
+					continue
+				}
+				record.Action = Quarantined
+			case time.Since(attrs.Created) < gracePeriod(target):
+				record.Action = Deferred
+			default:
+				if err := deleteGeneration(ctx, target, client.Bucket(bucketName).Object(name), attrs.Generation); err != nil {
+					errs = append(errs, fmt.Errorf("%q: %w", name, err))
+					continue
+				}
+				record.Action = Deleted
+			}
+		}
+		record.Time = time.Now()
+		logger.Warning(record.String())
+		records = append(records, record)
+	}
+	if len(errs) > 0 {
+		return records, fmt.Errorf("Remediate(%q) failed for %d of %d files: %v", bucketName, len(errs), len(unknown), errs)
+	}
+	return records, nil
+}
+
+// DeleteAborted deletes the incomplete uploads left in the bucket by AbortUpload, e.g. when the probe
+// stopped before it could delete them. They are Hermes files, so they are deleted whatever the
+// remediation policy of the target. Every deletion is written to the audit log.
+// Arguments:
+//	- ctx: the context of the probe run.
+//	- target: the target the uploads were found on.
+//	- client: initialised storage client for this target system.
+//	- logger: logger the audit log is written to.
+//	- aborted: the names of the aborted uploads, as found by CheckNil.
+// Returns:
+//	- records: returns the audit records of the uploads deleted.
+//	- err: returns an error if one of the uploads could not be deleted.
+//	  The remaining uploads are still deleted after an error.
+func DeleteAborted(ctx context.Context, target *target.Target, client stiface.Client, logger *logger.Logger, aborted []string) ([]*AuditRecord, error) {
+	bucketName := target.Target.GetBucketName()
+	var records []*AuditRecord
+	var errs []error
+	for _, name := range aborted {
+		file := client.Bucket(bucketName).Object(name)
+		attrs, err := getAttrs(ctx, target, file)
+		if err == nil {
+			err = deleteGeneration(ctx, target, file, attrs.Generation)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%q: %w", name, err))
+			continue
+		}
+		record := &AuditRecord{Time: time.Now(), Action: Deleted, Bucket: bucketName, Name: name, Generation: attrs.Generation}
+		logger.Warning(record.String())
+		records = append(records, record)
+	}
+	if len(errs) > 0 {
+		return records, fmt.Errorf("DeleteAborted(%q) failed for %d of %d files: %v", bucketName, len(errs), len(aborted), errs)
+	}
+	return records, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checknil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/googleinterns/step224-2020/hermes/probe/fakegcs"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+func TestRemediate(t *testing.T) {
+	tests := []struct {
+		policy          probepb.Target_RemediationPolicy
+		graceSec        int64
+		wantAction      Action
+		wantOriginal    bool
+		wantQuarantined bool
+	}{
+		{probepb.Target_REMEDIATION_POLICY_UNSPECIFIED, 0, Reported, true, false},
+		{probepb.Target_REPORT, 0, Reported, true, false},
+		{probepb.Target_QUARANTINE, 0, Quarantined, false, true},
+		{probepb.Target_DELETE, 0, Deferred, true, false},
+		{probepb.Target_DELETE, 1, Deleted, false, false},
+	}
+	for _, tc := range tests {
+		ctx := context.Background()
+		client := fakegcs.NewClient()
+		name := "Hermes_03_leftover"
+		createTestFiles(ctx, t, client, name)
+		target := genTestTarget(t, map[int32]string{})
+		target.Target.RemediationPolicy = tc.policy
+		target.Target.RemediationGracePeriodSec = tc.graceSec
+		logger := fakegcs.NewLogger(ctx).Logger
+		if tc.graceSec > 0 {
+			// Wait for the file to become older than the grace period.
+			time.Sleep(time.Duration(tc.graceSec)*time.Second + 100*time.Millisecond)
+		}
+
+		records, err := Remediate(ctx, target, client, logger, []string{name})
+		if err != nil {
+			t.Errorf("Remediate(%v) failed: %v", tc.policy, err)
+			continue
+		}
+		if len(records) != 1 || records[0].Action != tc.wantAction || records[0].Name != name {
+			t.Errorf("Remediate(%v) = %v, want a single %q record for %q", tc.policy, records, ActionName[tc.wantAction], name)
+		}
+		bucket := client.Bucket(bucketName)
+		if _, err := bucket.Object(name).Attrs(ctx); (err == nil) != tc.wantOriginal {
+			t.Errorf("Remediate(%v): Attrs(%q) = %v, want file to exist: %v", tc.policy, name, err, tc.wantOriginal)
+		}
+		attrs, err := bucket.Object(QuarantinePrefix + name).Attrs(ctx)
+		if (err == nil) != tc.wantQuarantined {
+			t.Errorf("Remediate(%v): Attrs(%q) = %v, want file to exist: %v", tc.policy, QuarantinePrefix+name, err, tc.wantQuarantined)
+		}
+		if err == nil && attrs.Metadata[MetadataOriginalNameKey] != name {
+			t.Errorf("Remediate(%v): quarantined file metadata[%q] = %q, want %q", tc.policy, MetadataOriginalNameKey, attrs.Metadata[MetadataOriginalNameKey], name)
+		}
+	}
+}
+
+func TestCompareIgnoresQuarantine(t *testing.T) {
+	result := Compare(genTestTarget(t, map[int32]string{}), []string{QuarantinePrefix + "Hermes_03_leftover"})
+	if len(result.Unknown) != 0 || len(result.Foreign) != 0 {
+		t.Errorf("Compare() = %+v, want quarantined files to be ignored", result)
+	}
+}
+
+func TestDeleteAborted(t *testing.T) {
+	ctx := context.Background()
+	client := fakegcs.NewClient()
+	name := "Hermes_aborted_03_00000000000000ff"
+	createTestFiles(ctx, t, client, name)
+	// Aborted uploads are deleted whatever the remediation policy.
+	target := genTestTarget(t, map[int32]string{})
+	target.Target.RemediationPolicy = probepb.Target_REPORT
+	logger := fakegcs.NewLogger(ctx).Logger
+
+	records, err := DeleteAborted(ctx, target, client, logger, []string{name})
+	if err != nil {
+		t.Fatalf("DeleteAborted() failed: %v", err)
+	}
+	if len(records) != 1 || records[0].Action != Deleted || records[0].Name != name {
+		t.Errorf("DeleteAborted() = %v, want a single %q record for %q", records, ActionName[Deleted], name)
+	}
+	if _, err := client.Bucket(bucketName).Object(name).Attrs(ctx); err != storage.ErrObjectNotExist {
+		t.Errorf("DeleteAborted(): Attrs(%q) = %v, want %v", name, err, storage.ErrObjectNotExist)
+	}
+}
+
+func TestRemediateMissingFile(t *testing.T) {
+	ctx := context.Background()
+	client := fakegcs.NewClient()
+	createTestFiles(ctx, t, client)
+	target := genTestTarget(t, map[int32]string{})
+	target.Target.RemediationPolicy = probepb.Target_DELETE
+	logger := fakegcs.NewLogger(ctx).Logger
+
+	if _, err := Remediate(ctx, target, client, logger, []string{"Hermes_04_gone"}); err == nil {
+		t.Errorf("Remediate() of a missing file = nil, want error wrapping %v", storage.ErrObjectNotExist)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package checksum implements the checksum algorithms that Hermes uses to
+// verify the contents of the files it stores in a target storage system.
+package checksum
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"strings"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+// Default is the algorithm used when a target does not specify one.
+const Default = probepb.Target_SHA1
+
+var (
+	// castagnoliTable is the CRC32 table used by GCS for CRC32C checksums.
+	castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+	// algorithmName maps each algorithm to the name used for it in filenames.
+	algorithmName = map[probepb.Target_ChecksumAlgorithm]string{
+		probepb.Target_SHA1:   "sha1",
+		probepb.Target_SHA256: "sha256",
+		probepb.Target_CRC32C: "crc32c",
+		probepb.Target_MD5:    "md5",
+	}
+)
+
+// resolve replaces an unspecified algorithm with the default algorithm.
+func resolve(alg probepb.Target_ChecksumAlgorithm) probepb.Target_ChecksumAlgorithm {
+	if alg == probepb.Target_CHECKSUM_ALGORITHM_UNSPECIFIED {
+		return Default
+	}
+	return alg
+}
+
+// New returns a new hash.Hash computing the checksum for the algorithm given.
+// Arguments:
+//	- alg: the checksum algorithm, CHECKSUM_ALGORITHM_UNSPECIFIED selects Default.
+// Returns:
+//	- hash: returns a new hash.Hash for the algorithm.
+//	- err: returns an error if the algorithm is not supported.
+func New(alg probepb.Target_ChecksumAlgorithm) (hash.Hash, error) {
+	switch resolve(alg) {
+	case probepb.Target_SHA1:
+		return sha1.New(), nil
+	case probepb.Target_SHA256:
+		return sha256.New(), nil
+	case probepb.Target_CRC32C:
+		return NewCRC32C(), nil
+	case probepb.Target_MD5:
+		return md5.New(), nil
+	default:
+		return nil, fmt.Errorf("invalid argument: unsupported checksum algorithm %v", alg)
+	}
+}
+
+// NewCRC32C returns a new hash.Hash32 computing the CRC32C (Castagnoli) checksum,
+// which is the checksum reported by GCS in ObjectAttrs.CRC32C.
+func NewCRC32C() hash.Hash32 {
+	return crc32.New(castagnoliTable)
+}
+
+// Name returns the name used to identify the algorithm in filenames.
+func Name(alg probepb.Target_ChecksumAlgorithm) string {
+	return algorithmName[resolve(alg)]
+}
+
+// Parse returns the algorithm identified by the name given.
+// Arguments:
+//	- name: the name of the algorithm as encoded in a filename, e.g. "sha256".
+// Returns:
+//	- alg: returns the checksum algorithm matching the name.
+//	- err: returns an error if no algorithm matches the name.
+func Parse(name string) (probepb.Target_ChecksumAlgorithm, error) {
+	for alg, n := range algorithmName {
+		if strings.EqualFold(n, name) {
+			return alg, nil
+		}
+	}
+	return probepb.Target_CHECKSUM_ALGORITHM_UNSPECIFIED, fmt.Errorf("invalid argument: unknown checksum algorithm %q", name)
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checksum
+
+import (
+	"fmt"
+	"testing"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		alg  probepb.Target_ChecksumAlgorithm
+		want string
+	}{
+		{probepb.Target_CHECKSUM_ALGORITHM_UNSPECIFIED, "a9993e364706816aba3e25717850c26c9cd0d89d"},
+		{probepb.Target_SHA1, "a9993e364706816aba3e25717850c26c9cd0d89d"},
+		{probepb.Target_SHA256, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{probepb.Target_CRC32C, "364b3fb7"},
+		{probepb.Target_MD5, "900150983cd24fb0d6963f7d28e17f72"},
+	}
+
+	for _, tc := range tests {
+		h, err := New(tc.alg)
+		if err != nil {
+			t.Fatalf("New(%v) failed: %v", tc.alg, err)
+		}
+		h.Write([]byte("abc"))
+		if got := fmt.Sprintf("%x", h.Sum(nil)); got != tc.want {
+			t.Errorf("New(%v) checksum of \"abc\" = %q, want %q", tc.alg, got, tc.want)
+		}
+	}
+
+	if _, err := New(probepb.Target_ChecksumAlgorithm(100)); err == nil {
+		t.Errorf("New(100) = nil error, want error for unsupported algorithm")
+	}
+}
+
+func TestNameAndParse(t *testing.T) {
+	for alg := range algorithmName {
+		got, err := Parse(Name(alg))
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", Name(alg), err)
+		}
+		if got != alg {
+			t.Errorf("Parse(Name(%v)) = %v, want %v", alg, got, alg)
+		}
+	}
+
+	if got := Name(probepb.Target_CHECKSUM_ALGORITHM_UNSPECIFIED); got != "sha1" {
+		t.Errorf("Name(CHECKSUM_ALGORITHM_UNSPECIFIED) = %q, want %q", got, "sha1")
+	}
+
+	if _, err := Parse("sha3"); err == nil {
+		t.Errorf("Parse(\"sha3\") = nil error, want error for unknown algorithm")
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package consistency implements the measurement of the time taken for a
+// storage system to reflect the creation or deletion of a file.
+package consistency
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+const (
+	consistencyLagSeconds    = "hermes_consistency_lag_seconds"
+	defaultInitialBackoff    = 100 * time.Millisecond
+	defaultMaxBackoff        = 5 * time.Second
+	defaultBackoffMultiplier = 2
+	defaultTimeout           = 60 * time.Second
+	defaultLagSLO            = 5 * time.Second
+)
+
+// Config holds the backoff and SLO used to measure consistency lag on a target.
+type Config struct {
+	// InitialBackoff is the delay before the second poll.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay between polls.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the delay is multiplied by after every poll.
+	Multiplier float64
+	// Timeout is the time after which a change is considered lost.
+	Timeout time.Duration
+	// LagSLO is the lag above which a consistency check fails.
+	LagSLO time.Duration
+}
+
+// NewConfig creates a new *Config from the target config given, applying the default of every unset field.
+func NewConfig(conf *probepb.Target_ConsistencyConfig) *Config {
+	c := &Config{
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		Multiplier:     defaultBackoffMultiplier,
+		Timeout:        defaultTimeout,
+		LagSLO:         defaultLagSLO,
+	}
+	if ms := conf.GetInitialBackoffMs(); ms > 0 {
+		c.InitialBackoff = time.Duration(ms) * time.Millisecond
+	}
+	if ms := conf.GetMaxBackoffMs(); ms > 0 {
+		c.MaxBackoff = time.Duration(ms) * time.Millisecond
+	}
+	if m := conf.GetBackoffMultiplier(); m >= 1 {
+		c.Multiplier = m
+	}
+	if sec := conf.GetTimeoutSec(); sec > 0 {
+		c.Timeout = time.Duration(sec) * time.Second
+	}
+	if sec := conf.GetLagSloSec(); sec > 0 {
+		c.LagSLO = time.Duration(sec * float64(time.Second))
+	}
+	return c
+}
+
+// Condition reports whether a change is visible in the target storage system.
+// An error returned by a Condition stops the polling.
+type Condition func(ctx context.Context) (bool, error)
+
+// WaitFor polls the condition with an exponential backoff until it holds, it returns an error or
+// the timeout in the config expires.
+// Arguments:
+//	- ctx: the context of the probe run, the polling stops if it is cancelled.
+//	- conf: the backoff and timeout used for polling.
+//	- cond: the condition polled.
+// Returns:
+//	- lag: returns the time from the call until the condition held, or until polling stopped.
+//	- err: returns an error if the condition returned one, the timeout expired or ctx was cancelled.
+func WaitFor(ctx context.Context, conf *Config, cond Condition) (time.Duration, error) {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, conf.Timeout)
+	defer cancel()
+	backoff := conf.InitialBackoff
+	for {
+		ok, err := cond(ctx)
+		if err != nil && ctx.Err() != nil {
+			// The condition failed because polling timed out or was cancelled.
+			return time.Since(start), ctx.Err()
+		}
+		if err != nil {
+			return time.Since(start), err
+		}
+		if ok {
+			return time.Since(start), nil
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return time.Since(start), ctx.Err()
+		case <-timer.C:
+		}
+		if backoff = time.Duration(float64(backoff) * conf.Multiplier); backoff > conf.MaxBackoff {
+			backoff = conf.MaxBackoff
+		}
+	}
+}
+
+// Measure waits for the condition to hold on the target and records the lag in the consistency lag metrics of the target.
+// Arguments:
+//	- ctx: the context of the probe run.
+//	- target: the target the change was made on.
+//	- check: the consistency check being measured.
+//	- cond: the condition that holds once the change is visible.
+// Returns:
+//	- err: returns a *metrics.ProbeError with status:
+//		- OpTimeout: the change did not become visible before the timeout expired.
+//		- ConsistencyLagExceeded: the change became visible after the lag SLO.
+//		- the status of the error returned by cond, if it returned one.
+func Measure(ctx context.Context, target *target.Target, check metrics.ConsistencyCheck, cond Condition) error {
+	conf := NewConfig(target.Target.GetConsistency())
+	lag, err := WaitFor(ctx, conf, cond)
+	var status metrics.ExitStatus
+	switch {
+	case err == context.DeadlineExceeded:
+		status = metrics.OpTimeout
+		err = fmt.Errorf("change not visible after %v: %w", lag, err)
+	case err != nil:
+		status = metrics.StatusOf(err)
+	case lag > conf.LagSLO:
+		status = metrics.ConsistencyLagExceeded
+		err = fmt.Errorf("change took %v to become visible; want <= %v", lag, conf.LagSLO)
+	default:
+		status = metrics.Success
+	}
+	target.LatencyMetrics.ConsistencyLag[check][status].Metric(consistencyLagSeconds).AddFloat64(lag.Seconds())
+	if err != nil {
+		return metrics.NewProbeError(status, fmt.Errorf("%s: %w", metrics.ConsistencyCheckName[check], err))
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consistency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/probetest"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+// visibleAfter returns a condition that holds from the nth poll onwards and a pointer to the number of polls.
+func visibleAfter(n int) (Condition, *int) {
+	polls := 0
+	return func(context.Context) (bool, error) {
+		polls++
+		return polls >= n, nil
+	}, &polls
+}
+
+func TestNewConfig(t *testing.T) {
+	got := NewConfig(nil)
+	want := &Config{
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		Multiplier:     defaultBackoffMultiplier,
+		Timeout:        defaultTimeout,
+		LagSLO:         defaultLagSLO,
+	}
+	if *got != *want {
+		t.Errorf("NewConfig(nil) = %+v, want %+v", got, want)
+	}
+
+	got = NewConfig(&probepb.Target_ConsistencyConfig{
+		InitialBackoffMs:  10,
+		MaxBackoffMs:      20,
+		BackoffMultiplier: 1.5,
+		TimeoutSec:        3,
+		LagSloSec:         0.5,
+	})
+	want = &Config{
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     20 * time.Millisecond,
+		Multiplier:     1.5,
+		Timeout:        3 * time.Second,
+		LagSLO:         500 * time.Millisecond,
+	}
+	if *got != *want {
+		t.Errorf("NewConfig() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	conf := &Config{InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond, Multiplier: 2, Timeout: time.Second}
+	cond, polls := visibleAfter(4)
+	if _, err := WaitFor(context.Background(), conf, cond); err != nil {
+		t.Errorf("WaitFor() failed: %v", err)
+	}
+	if *polls != 4 {
+		t.Errorf("WaitFor() polled %d times, want 4", *polls)
+	}
+}
+
+func TestWaitForTimeout(t *testing.T) {
+	conf := &Config{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 1, Timeout: 20 * time.Millisecond}
+	cond, _ := visibleAfter(1 << 30)
+	if _, err := WaitFor(context.Background(), conf, cond); err != context.DeadlineExceeded {
+		t.Errorf("WaitFor() = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestWaitForConditionError(t *testing.T) {
+	conf := &Config{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 1, Timeout: time.Second}
+	wantErr := errors.New("list failed")
+	if _, err := WaitFor(context.Background(), conf, func(context.Context) (bool, error) {
+		return false, wantErr
+	}); err != wantErr {
+		t.Errorf("WaitFor() = %v, want %v", err, wantErr)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		desc       string
+		conf       *probepb.Target_ConsistencyConfig
+		visibleAt  int
+		wantStatus metrics.ExitStatus
+	}{
+		{
+			desc:       "visible immediately",
+			conf:       &probepb.Target_ConsistencyConfig{InitialBackoffMs: 1, TimeoutSec: 1},
+			visibleAt:  1,
+			wantStatus: metrics.Success,
+		},
+		{
+			desc:       "visible within SLO",
+			conf:       &probepb.Target_ConsistencyConfig{InitialBackoffMs: 1, MaxBackoffMs: 1, TimeoutSec: 1},
+			visibleAt:  3,
+			wantStatus: metrics.Success,
+		},
+		{
+			desc:       "lag above SLO",
+			conf:       &probepb.Target_ConsistencyConfig{InitialBackoffMs: 20, MaxBackoffMs: 20, TimeoutSec: 1, LagSloSec: 0.01},
+			visibleAt:  2,
+			wantStatus: metrics.ConsistencyLagExceeded,
+		},
+		{
+			desc:       "never visible",
+			conf:       &probepb.Target_ConsistencyConfig{InitialBackoffMs: 1, MaxBackoffMs: 1, TimeoutSec: 1},
+			visibleAt:  1 << 30,
+			wantStatus: metrics.OpTimeout,
+		},
+	}
+	for _, tc := range tests {
+		target := probetest.NewTarget(t, "consistency_test", probetest.TargetConfig("test_bucket_probe0"))
+		target.Target.Consistency = tc.conf
+		cond, _ := visibleAfter(tc.visibleAt)
+		if got := metrics.StatusOf(Measure(context.Background(), target, metrics.ListAfterWrite, cond)); got != tc.wantStatus {
+			t.Errorf("%s: Measure() status = %q, want %q", tc.desc, metrics.ExitStatusName[got], metrics.ExitStatusName[tc.wantStatus])
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package content implements the deterministic generator of the contents of the files Hermes creates.
+package content
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// blockSize is the number of bytes generated from a single counter value.
+const blockSize = sha256.Size
+
+// Reader generates the deterministic pseudo-random contents of a file from a seed.
+// The generator is counter based: block i of the contents is SHA-256(seed || i), so any
+// byte range of the contents can be generated without generating the bytes before it.
+// Reader implements io.Reader, io.Seeker and io.ReaderAt.
+type Reader struct {
+	seed int64
+	size int64
+	// offset of the next byte to be read
+	off int64
+}
+
+// NewReader returns a new Reader for contents of the size given generated from the seed.
+func NewReader(seed int64, sizeBytes int64) *Reader {
+	return &Reader{seed: seed, size: sizeBytes}
+}
+
+// Size returns the size of the contents in bytes.
+func (r *Reader) Size() int64 {
+	return r.size
+}
+
+// block generates the block of contents for the counter value given.
+func (r *Reader) block(counter int64) [blockSize]byte {
+	var in [16]byte
+	binary.BigEndian.PutUint64(in[:8], uint64(r.seed))
+	binary.BigEndian.PutUint64(in[8:], uint64(counter))
+	return sha256.Sum256(in[:])
+}
+
+// ReadAt implements the io.ReaderAt interface.
+// It generates len(buf) bytes of the contents starting at offset off.
+// Arguments:
+//	- buf: a byte slice that serves as an output buffer.
+//	- off: the offset in the contents to start reading at.
+// Returns:
+//	- n: the number of bytes read.
+//	- err: io.EOF if fewer than len(buf) bytes were read because the end of the contents was reached.
+func (r *Reader) ReadAt(buf []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, fmt.Errorf("invalid argument: offset = %d; want offset >= 0", off)
+	}
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	want := len(buf)
+	if int64(want) > r.size-off {
+		buf = buf[:r.size-off]
+	}
+	for n < len(buf) {
+		pos := off + int64(n)
+		block := r.block(pos / blockSize)
+		n += copy(buf[n:], block[pos%blockSize:])
+	}
+	if n < want {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Read implements the io.Reader interface.
+func (r *Reader) Read(buf []byte) (n int, err error) {
+	if r.off >= r.size {
+		return 0, io.EOF
+	}
+	n, err = r.ReadAt(buf, r.off)
+	r.off += int64(n)
+	if err == io.EOF && n > 0 {
+		// The end of the contents will be reported by the next call to Read.
+		err = nil
+	}
+	return n, err
+}
+
+// Seek implements the io.Seeker interface.
+func (r *Reader) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = r.off + offset
+	case io.SeekEnd:
+		abs = r.size + offset
+	default:
+		return 0, errors.New("content.Reader.Seek: invalid whence")
+	}
+	if abs < 0 {
+		return 0, errors.New("content.Reader.Seek: negative position")
+	}
+	r.off = abs
+	return abs, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+var (
+	_ io.ReadSeeker = (*Reader)(nil)
+	_ io.ReaderAt   = (*Reader)(nil)
+)
+
+func TestRead(t *testing.T) {
+	for _, size := range []int64{0, 1, 31, 32, 33, 1000} {
+		got, err := ioutil.ReadAll(NewReader(7, size))
+		if err != nil {
+			t.Fatalf("ReadAll(NewReader(7, %d)) failed: %v", size, err)
+		}
+		if int64(len(got)) != size {
+			t.Errorf("ReadAll(NewReader(7, %d)) read %d bytes, want %d", size, len(got), size)
+		}
+		again, err := ioutil.ReadAll(NewReader(7, size))
+		if err != nil {
+			t.Fatalf("ReadAll(NewReader(7, %d)) failed: %v", size, err)
+		}
+		if !bytes.Equal(got, again) {
+			t.Errorf("NewReader(7, %d) generated different contents on two reads, want identical contents", size)
+		}
+	}
+
+	a, _ := ioutil.ReadAll(NewReader(7, 100))
+	b, _ := ioutil.ReadAll(NewReader(8, 100))
+	if bytes.Equal(a, b) {
+		t.Errorf("NewReader(7, 100) and NewReader(8, 100) generated identical contents, want different contents")
+	}
+}
+
+func TestReadAt(t *testing.T) {
+	const size = 1000
+	all, err := ioutil.ReadAll(NewReader(3, size))
+	if err != nil {
+		t.Fatalf("ReadAll(NewReader(3, %d)) failed: %v", size, err)
+	}
+	tests := []struct {
+		off     int64
+		length  int
+		wantN   int
+		wantEOF bool
+	}{
+		{0, 10, 10, false},
+		{31, 2, 2, false},
+		{500, 300, 300, false},
+		{990, 10, 10, false},
+		{990, 20, 10, true},
+		{size, 1, 0, true},
+	}
+	r := NewReader(3, size)
+	for _, tc := range tests {
+		buf := make([]byte, tc.length)
+		n, err := r.ReadAt(buf, tc.off)
+		if n != tc.wantN || (err == io.EOF) != tc.wantEOF {
+			t.Errorf("ReadAt(len: %d, off: %d) = (%d, %v), want (%d, EOF: %v)", tc.length, tc.off, n, err, tc.wantN, tc.wantEOF)
+		}
+		if !bytes.Equal(buf[:n], all[tc.off:tc.off+int64(n)]) {
+			t.Errorf("ReadAt(len: %d, off: %d) returned bytes that differ from a sequential read at the same offset", tc.length, tc.off)
+		}
+	}
+}
+
+func TestSeek(t *testing.T) {
+	const size = 100
+	all, _ := ioutil.ReadAll(NewReader(5, size))
+	r := NewReader(5, size)
+	tests := []struct {
+		offset  int64
+		whence  int
+		wantPos int64
+	}{
+		{40, io.SeekStart, 40},
+		{10, io.SeekCurrent, 50},
+		{-20, io.SeekEnd, 80},
+	}
+	for _, tc := range tests {
+		pos, err := r.Seek(tc.offset, tc.whence)
+		if err != nil || pos != tc.wantPos {
+			t.Fatalf("Seek(%d, %d) = (%d, %v), want (%d, nil)", tc.offset, tc.whence, pos, err, tc.wantPos)
+		}
+		got, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatalf("ReadAll() after Seek(%d, %d) failed: %v", tc.offset, tc.whence, err)
+		}
+		if !bytes.Equal(got, all[pos:]) {
+			t.Errorf("ReadAll() after Seek(%d, %d) returned bytes that differ from the contents at offset %d", tc.offset, tc.whence, pos)
+		}
+		if _, err := r.Seek(pos, io.SeekStart); err != nil {
+			t.Fatalf("Seek(%d, io.SeekStart) failed: %v", pos, err)
+		}
+	}
+	if _, err := r.Seek(-1, io.SeekStart); err == nil {
+		t.Errorf("Seek(-1, io.SeekStart) = nil error, want error for negative position")
+	}
+}
@@ -21,121 +21,327 @@ package create
 
 import (
 	"context"
-	"crypto/sha1"
+	crand "crypto/rand"
+	"encoding/binary"
 	"fmt"
 	"io"
-	"math/rand"
+	"strconv"
 	"time"
 
 	"cloud.google.com/go/storage"
 	"github.com/google/cloudprober/logger"
 	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+	"github.com/googleinterns/step224-2020/hermes/probe/checksum"
+	"github.com/googleinterns/step224-2020/hermes/probe/consistency"
+	"github.com/googleinterns/step224-2020/hermes/probe/content"
+	"github.com/googleinterns/step224-2020/hermes/probe/journal"
+	"github.com/googleinterns/step224-2020/hermes/probe/layout"
 	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
 	"github.com/googleinterns/step224-2020/hermes/probe/target"
 	"google.golang.org/api/iterator"
 
+	probepb "github.com/googleinterns/step224-2020/config/proto"
 	pb "github.com/googleinterns/step224-2020/hermes/proto"
 )
 
 const (
-	FileNameFormat          = "Hermes_%02d_%x"
-	minFileID               = 1
-	maxFileID               = 50
+	// FileNameFormat is the format of the names of files in the storage system: Hermes_ID_seed_algorithm_checksum
+	FileNameFormat = "Hermes_%02d_%016x_%s_%x"
+	// FileNamePrefixFormat is the format of the filename prefix shared by all files with the same ID: Hermes_ID_
+	FileNamePrefixFormat = "Hermes_%02d_"
+	// AbortedFilePrefix is the prefix of the names of files whose uploads are aborted.
+	AbortedFilePrefix = "Hermes_aborted_"
+	// AbortedFileNameFormat is the format of the names of files whose uploads are aborted: Hermes_aborted_ID_seed
+	AbortedFileNameFormat   = AbortedFilePrefix + "%02d_%016x"
 	maxFileSizeBytes        = 1000
-	hermesAPILatencySeconds = "hermes_api_latency_s"
+	hermesAPILatencySeconds = "hermes_api_latency_seconds"
+	// defaultUploadChunkSizeBytes is the minimum chunk size of a GCS resumable upload.
+	defaultUploadChunkSizeBytes = 256 * 1024
+	// maxResumableFileSizeBytes is the maximum size of a file in RESUMABLE upload mode.
+	// Files no larger than the chunk size are uploaded in a single request by the client library.
+	maxResumableFileSizeBytes = 64 * defaultUploadChunkSizeBytes
+)
+
+const (
+	// HermesVersion is the version of Hermes recorded in the metadata of every file it creates.
+	HermesVersion = "0.1.0"
+	// ContentType is the content type of every file created by Hermes.
+	ContentType = "application/octet-stream"
+	// MetadataVersionKey is the custom metadata key for the version of Hermes that created a file.
+	MetadataVersionKey = "hermes_version"
+	// MetadataSeedKey is the custom metadata key for the seed used to generate the contents of a file.
+	MetadataSeedKey = "hermes_seed"
+	// MetadataSizeKey is the custom metadata key for the intended size of a file in bytes.
+	MetadataSizeKey = "hermes_size_bytes"
 )
 
 type randomFile struct {
 	id        int32
 	sizeBytes int
+	// seed used to generate the contents of the file, a new seed is used every time a file is created.
+	seed int64
 }
 
-type randomFileReader struct {
-	sizeBytes int
-	// currently reading this byte
-	i    int
-	rand *rand.Rand
+// newSeed returns a new random, non-negative seed for the contents of a file.
+func newSeed() (int64, error) {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return 0, fmt.Errorf("could not generate seed: %w", err)
+	}
+	return int64(binary.BigEndian.Uint64(b[:]) >> 1), nil
 }
 
-// Read implements the standard Read interface, it returns a random stream of bytes generated by a pseudo-random generator.
-// Argument:
-//      buf: a byte slice serves as an output buffer
-// Returns:
-//		n:  the number of bytes read
-//		err: error it should be nil when not done reading and io.EOF once the whole file contents have been read
-func (r *randomFileReader) Read(buf []byte) (n int, err error) {
-	// check whether Read is done
-	if r.i >= r.sizeBytes {
-		return 0, io.EOF
-	}
-	b := buf
-	if len(buf) > r.sizeBytes-r.i {
-		// if the length of buffer is greater than the number of bytes left to read make the sizeBytes of the buffer match the number of bytes left to read
-		b = buf[:r.sizeBytes-r.i]
-	}
-	// n is now the length  of the buffer
-	n, err = r.rand.Read(b)
-	if err != nil {
-		// in this case n = 0
-		return n, err
-	}
-	r.i += n
-	return n, err
+// newReader returns a reader for the contents of the file, which are generated from its seed.
+func (f *randomFile) newReader() *content.Reader {
+	return content.NewReader(f.seed, int64(f.sizeBytes))
 }
 
-func (f *randomFile) newReader() *randomFileReader {
-	//  id will serve as a Seed and i - index of the currently read byte  will be set to 0 automatically in the returned reader
-	return &randomFileReader{
-		sizeBytes: f.sizeBytes,
-		rand:      rand.New(rand.NewSource(int64(f.id))),
+// metadata returns the custom metadata stored with the file so that it can be verified when the file is read.
+func (f *randomFile) metadata() map[string]string {
+	return map[string]string{
+		MetadataVersionKey: HermesVersion,
+		MetadataSeedKey:    strconv.FormatInt(f.seed, 10),
+		MetadataSizeKey:    strconv.Itoa(f.sizeBytes),
 	}
 }
 
-func newRandomFile(id int32, sizeBytes int) (*randomFile, error) {
-	if id < minFileID || id > maxFileID {
-		return nil, fmt.Errorf("invalid argument: id = %d; want %d <= id <= %d", id, minFileID, maxFileID)
+func newRandomFile(l *layout.Layout, id int32, sizeBytes int, seed int64) (*randomFile, error) {
+	return newRandomFileWithMaxSize(l, id, sizeBytes, seed, maxFileSizeBytes)
+}
+
+// newResumableFile returns a file to be uploaded in RESUMABLE upload mode.
+// The file may be larger than the upload chunk size so that it is uploaded in more than one chunk.
+func newResumableFile(l *layout.Layout, id int32, sizeBytes int, seed int64) (*randomFile, error) {
+	return newRandomFileWithMaxSize(l, id, sizeBytes, seed, maxResumableFileSizeBytes)
+}
+
+func newRandomFileWithMaxSize(l *layout.Layout, id int32, sizeBytes int, seed int64, maxSizeBytes int) (*randomFile, error) {
+	if !l.Contains(id) {
+		return nil, fmt.Errorf("invalid argument: id = %d; want %d <= id <= %d", id, layout.MinFileID, l.MaxFileID())
 	}
-	if sizeBytes > maxFileSizeBytes || sizeBytes <= 0 {
-		return nil, fmt.Errorf("invalid argument: sizeBytes = %d; want 0 < sizeBytes <= %d", sizeBytes, maxFileSizeBytes)
+	if sizeBytes > maxSizeBytes || sizeBytes <= 0 {
+		return nil, fmt.Errorf("invalid argument: sizeBytes = %d; want 0 < sizeBytes <= %d", sizeBytes, maxSizeBytes)
 	}
-	return &randomFile{id: id, sizeBytes: sizeBytes}, nil
+	return &randomFile{id: id, sizeBytes: sizeBytes, seed: seed}, nil
 }
 
-func (f *randomFile) checksum() ([]byte, error) {
+func (f *randomFile) checksum(alg probepb.Target_ChecksumAlgorithm) ([]byte, error) {
 	r := f.newReader()
-	h := sha1.New()
+	h, err := checksum.New(alg)
+	if err != nil {
+		return nil, err
+	}
 	if _, err := io.Copy(h, r); err != nil {
 		return nil, fmt.Errorf("io.Copy: %w", err)
 	}
 	return h.Sum(nil), nil
 }
 
-func (f *randomFile) fileName() (string, error) {
-	checksum, err := f.checksum()
+// fileName returns the name of the file, which embeds the file ID, the seed of its contents and the
+// checksum of its contents computed using the algorithm given.
+func (f *randomFile) fileName(alg probepb.Target_ChecksumAlgorithm) (string, error) {
+	sum, err := f.checksum(alg)
+	if err != nil {
+		return "", fmt.Errorf("{%d, %d}.checksum(%v) = nil,  %w", f.id, f.sizeBytes, alg, err)
+	}
+	return fmt.Sprintf(FileNameFormat, f.id, f.seed, checksum.Name(alg), sum), nil
+}
+
+// listFiles lists the names of the files with the prefix given in the target bucket and records the latency of the list files API call.
+func listFiles(ctx context.Context, target *target.Target, client stiface.Client, prefix string) ([]string, error) {
+	start := time.Now()
+	objIter := client.Bucket(target.Target.GetBucketName()).Objects(ctx, &storage.Query{Prefix: prefix})
+	var names []string
+	for {
+		obj, err := objIter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			status := metrics.APICallFailed
+			if err == storage.ErrBucketNotExist {
+				status = metrics.BucketMissing
+			}
+			target.LatencyMetrics.APICallLatency[metrics.APIListFiles][status].Metric(hermesAPILatencySeconds).AddFloat64(time.Now().Sub(start).Seconds())
+			return nil, metrics.NewProbeError(status, fmt.Errorf("could not list files with prefix %q: %w", prefix, err))
+		}
+		names = append(names, obj.Name)
+	}
+	target.LatencyMetrics.APICallLatency[metrics.APIListFiles][metrics.Success].Metric(hermesAPILatencySeconds).AddFloat64(time.Now().Sub(start).Seconds())
+	return names, nil
+}
+
+// uploadChunkSize returns the chunk size used for resumable uploads to the target.
+func uploadChunkSize(target *target.Target) int {
+	if size := target.Target.GetUploadChunkSizeBytes(); size > 0 {
+		return int(size)
+	}
+	return defaultUploadChunkSizeBytes
+}
+
+// chunkRecorder records the latency of each chunk of a resumable upload.
+type chunkRecorder struct {
+	target *target.Target
+	// last is the time at which the previous chunk finished uploading.
+	last time.Time
+}
+
+// newChunkRecorder returns a new *chunkRecorder timing the first chunk from now.
+func newChunkRecorder(target *target.Target) *chunkRecorder {
+	return &chunkRecorder{target: target, last: time.Now()}
+}
+
+// record records the latency of the chunk that has just finished with the status given.
+func (c *chunkRecorder) record(status metrics.ExitStatus) {
+	now := time.Now()
+	c.target.LatencyMetrics.APICallLatency[metrics.APIUploadChunk][status].Metric(hermesAPILatencySeconds).AddFloat64(now.Sub(c.last).Seconds())
+	c.last = now
+}
+
+// progress is passed to Writer.SetProgressFunc and is called every time a chunk is uploaded.
+func (c *chunkRecorder) progress(int64) {
+	c.record(metrics.Success)
+}
+
+// newWriter returns a writer for the file configured for the upload mode of the target.
+// Arguments:
+//	- ctx: the context of the upload, cancelling it aborts the upload.
+//	- target: the target the file is uploaded to.
+//	- client: the storage client used to upload the file.
+//	- f: the file being uploaded.
+//	- fileName: the name of the object the file is uploaded to.
+// Returns:
+//	- wc: returns a writer for the object.
+//	- chunks: returns the recorder of chunk latencies in RESUMABLE upload mode and nil otherwise.
+func newWriter(ctx context.Context, target *target.Target, client stiface.Client, f *randomFile, fileName string) (stiface.Writer, *chunkRecorder) {
+	wc := client.Bucket(target.Target.GetBucketName()).Object(fileName).NewWriter(ctx)
+	wc.ObjectAttrs().ContentType = ContentType
+	wc.ObjectAttrs().Metadata = f.metadata()
+	if target.Target.GetUploadMode() != probepb.Target_RESUMABLE {
+		return wc, nil
+	}
+	chunks := newChunkRecorder(target)
+	wc.SetChunkSize(uploadChunkSize(target))
+	wc.SetProgressFunc(chunks.progress)
+	return wc, chunks
+}
+
+// AbortUpload starts a resumable upload of a file, aborts it after more than one chunk has been written
+// and verifies that the incomplete upload is not visible in the target bucket.
+// An incomplete upload that is found is deleted.
+// Arguments:
+//	- ctx: the context of the probe run.
+//	- target: the target the upload is aborted on. Its upload chunk size is used.
+//	- fileID: the ID of the file whose upload is aborted. It needs to be in the file layout of the target.
+//	- client: the storage client used to upload the file.
+//	- logger: a cloudprober logger.
+// Returns:
+//	- err: returns a *metrics.ProbeError with status:
+//		- IncompleteUploadFound: the aborted upload left a file in the target bucket.
+//		- BucketMissing: the target bucket does not exist.
+//		- APICallFailed: the upload could not be started or the bucket could not be checked.
+func AbortUpload(ctx context.Context, target *target.Target, fileID int32, client stiface.Client, logger *logger.Logger) error {
+	l, err := layout.New(target.Target.GetFileLayout())
 	if err != nil {
-		return "", fmt.Errorf("{%d, %d}.checksum = nil,  %w", f.id, f.sizeBytes, err)
+		return metrics.NewProbeError(metrics.InvalidArgument, err)
+	}
+	seed, err := newSeed()
+	if err != nil {
+		return err
+	}
+	// The first chunk is uploaded and the upload is aborted while the second chunk is buffered.
+	chunkSize := uploadChunkSize(target)
+	f, err := newResumableFile(l, fileID, chunkSize+chunkSize/2, seed)
+	if err != nil {
+		return metrics.NewProbeError(metrics.InvalidArgument, err)
+	}
+	return abortUpload(ctx, target, client, f, logger)
+}
+
+// abortUpload writes the contents of the file to a resumable upload, aborts it before it is completed
+// and verifies that the incomplete upload is not visible in the target bucket, see AbortUpload.
+// Arguments:
+//	- ctx: the context of the probe run.
+//	- target: the target the upload is aborted on.
+//	- client: the storage client used to upload the file.
+//	- f: the file whose upload is aborted.
+//	- logger: a cloudprober logger.
+// Returns:
+//	- err: returns a *metrics.ProbeError if the upload could not be started or left a file behind.
+func abortUpload(ctx context.Context, target *target.Target, client stiface.Client, f *randomFile, logger *logger.Logger) error {
+	fileName := fmt.Sprintf(AbortedFileNameFormat, f.id, f.seed)
+	bucketName := target.Target.GetBucketName()
+	uploadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	start := time.Now()
+	record := func(status metrics.ExitStatus) {
+		target.LatencyMetrics.APICallLatency[metrics.APIAbortUpload][status].Metric(hermesAPILatencySeconds).AddFloat64(time.Now().Sub(start).Seconds())
+	}
+	wc := client.Bucket(bucketName).Object(fileName).NewWriter(uploadCtx)
+	wc.ObjectAttrs().ContentType = ContentType
+	wc.SetChunkSize(uploadChunkSize(target))
+	if _, err := io.Copy(wc, f.newReader()); err != nil {
+		record(metrics.APICallFailed)
+		return metrics.NewProbeError(metrics.APICallFailed, fmt.Errorf("abortUpload(id: %d): could not start upload of %q: %w", f.id, fileName, err))
+	}
+	cancel()
+	// Closing the writer after its context has been cancelled aborts the upload, so an error is expected.
+	if err := wc.Close(); err == nil {
+		logger.Warningf("Aborted upload of %q completed.", fileName)
+	}
+
+	_, err := client.Bucket(bucketName).Object(fileName).Attrs(ctx)
+	switch err {
+	case storage.ErrObjectNotExist:
+		record(metrics.Success)
+		return nil
+	case nil:
+		record(metrics.IncompleteUploadFound)
+		if err := client.Bucket(bucketName).Object(fileName).Delete(ctx); err != nil {
+			logger.Errorf("Could not delete the incomplete upload %q: %v", fileName, err)
+		}
+		return metrics.NewProbeError(metrics.IncompleteUploadFound, fmt.Errorf("abortUpload(id: %d): aborted upload left file %q in bucket %q", f.id, fileName, bucketName))
+	case storage.ErrBucketNotExist:
+		record(metrics.BucketMissing)
+		return metrics.NewProbeError(metrics.BucketMissing, fmt.Errorf("abortUpload(id: %d): %w", f.id, err))
+	default:
+		record(metrics.APICallFailed)
+		return metrics.NewProbeError(metrics.APICallFailed, fmt.Errorf("abortUpload(id: %d): could not check for incomplete upload %q: %w", f.id, fileName, err))
 	}
-	return fmt.Sprintf(FileNameFormat, f.id, checksum), nil
 }
 
 // CreateFile creates and stores a file with randomized contents in the target storage system.
 // Before it creates and stores a file it logs an intent to do so in the target storage system's journal.
-// It verifies that the creation and storage process was successful.
-// Finally, it updates the filenames map in the target's journal and record the exit status in the logger.
+// Once the file is stored, it updates the filenames map in the target's journal and record the exit status in the logger.
+// It then verifies that the creation and storage process was successful by polling the target until the file is listed,
+// recording the time taken as the list-after-write consistency lag. The journal is updated even if this check fails.
+// In RESUMABLE upload mode the file is uploaded in chunks.
 // Arguments:
 //          ctx: it carries deadlines and cancellation signals that might orinate from the main probe located in probe.go.
 //          target: contains information about target storage system, carries an intent log in the form of a StateJournal and it used to export metrics.
-//          fileID: the unique identifer of every randomFile, it cannot be repeated. It needs to be in the range [minFileID, maxFileID]. FileID 0 is reserved for a special file called the NIL file.
+//          fileID: the unique identifer of every randomFile, it cannot be repeated. It needs to be in the file layout of the target. FileID 0 is reserved for a special file called the NIL file.
 //          client: is a storage client. It is used as an interface to interact with the target storage system.
 //          logger: a cloudprober logger used to record the exit status of the CreateFile operation in a target bucket. The logger passed MUST be a valid logger.
 // Returns:
 //          error: an error string with detailed information about the status and fileID. Nil is returned when the operation is successful.
 func CreateFile(ctx context.Context, target *target.Target, fileID int32, fileSize int, client stiface.Client, logger *logger.Logger) error {
-	f, err := newRandomFile(fileID, fileSize)
+	l, err := layout.New(target.Target.GetFileLayout())
+	if err != nil {
+		return metrics.NewProbeError(metrics.InvalidArgument, err)
+	}
+	seed, err := newSeed()
 	if err != nil {
 		return err
 	}
-	fileName, err := f.fileName()
+	newFile := newRandomFile
+	if target.Target.GetUploadMode() == probepb.Target_RESUMABLE {
+		newFile = newResumableFile
+	}
+	f, err := newFile(l, fileID, fileSize, seed)
+	if err != nil {
+		return err
+	}
+	fileName, err := f.fileName(target.Target.GetChecksumAlgorithm())
 	if err != nil {
 		return err
 	}
@@ -148,7 +354,7 @@ func CreateFile(ctx context.Context, target *target.Target, fileID int32, fileSi
 	r := f.newReader()
 	start := time.Now()
 	bucketName := target.Target.GetBucketName()
-	wc := client.Bucket(bucketName).Object(fileName).NewWriter(ctx)
+	wc, chunks := newWriter(ctx, target, client, f, fileName)
 	if _, err = io.Copy(wc, r); err != nil {
 		switch err {
 		case storage.ErrBucketNotExist:
@@ -156,47 +362,68 @@ func CreateFile(ctx context.Context, target *target.Target, fileID int32, fileSi
 		default:
 			status = metrics.ProbeFailed
 		}
+		if chunks != nil {
+			chunks.record(status)
+		}
 		target.LatencyMetrics.APICallLatency[metrics.APICreateFile][status].Metric(hermesAPILatencySeconds).AddFloat64(time.Now().Sub(start).Seconds())
 		return fmt.Errorf("CreateFile(id: %d).%q: could not create file %q: %w", fileID, status, fileName, err)
 	}
 	if err := wc.Close(); err != nil {
 		status = metrics.WriterCloseFailed
+		if chunks != nil {
+			chunks.record(status)
+		}
 		target.LatencyMetrics.APICallLatency[metrics.APICreateFile][status].Metric(hermesAPILatencySeconds).AddFloat64(time.Now().Sub(start).Seconds())
 		return fmt.Errorf("Writer.Close: %w with status %q", err, status)
 	}
 	status = metrics.Success
 	target.LatencyMetrics.APICallLatency[metrics.APICreateFile][status].Metric(hermesAPILatencySeconds).AddFloat64(time.Now().Sub(start).Seconds())
 
-	// Verify that the file that has just been created is in fact present in the target system
-	fileNamePrefix := fmt.Sprintf(FileNameFormat, fileID, "")
-	query := &storage.Query{Prefix: fileNamePrefix}
-	start = time.Now()
-	objIter := client.Bucket(bucketName).Objects(ctx, query)
-	var namesFound []string
-	for {
-		obj, err := objIter.Next()
-		if err == iterator.Done {
-			break
+	created := time.Now()
+	entry := &pb.FileEntry{
+		Filename:          fileName,
+		Seed:              seed,
+		SizeBytes:         int64(f.sizeBytes),
+		ChecksumAlgorithm: checksum.Name(target.Target.GetChecksumAlgorithm()),
+	}
+	if attrs := wc.Attrs(); attrs != nil {
+		entry.Generation = attrs.Generation
+		if !attrs.Created.IsZero() {
+			created = attrs.Created
 		}
+	}
+	entry.CreatedUnixSec = created.Unix()
+	if l.IsPermanent(fileID) {
+		// The checksum of a permanent file is recorded to verify its durability, see durability.Verify.
+		sum, err := f.checksum(target.Target.GetChecksumAlgorithm())
 		if err != nil {
-			return fmt.Errorf("CreateFile check failed due to: %w", err)
+			return err
 		}
-		namesFound = append(namesFound, obj.Name)
-	}
-	finish := time.Now()
-	if len(namesFound) == 0 {
-		target.LatencyMetrics.APICallLatency[metrics.APIListFiles][metrics.FileMissing].Metric(hermesAPILatencySeconds).AddFloat64(finish.Sub(start).Seconds())
-		return fmt.Errorf("CreateFile check failed no files with prefix %q found", fileNamePrefix)
+		entry.Checksum = fmt.Sprintf("%x", sum)
+		entry.LastVerifiedUnixSec = created.Unix()
 	}
-	if len(namesFound) != 1 {
-		return fmt.Errorf("expected exactly one file in bucket %q with prefix %q; found %d: %v", bucketName, fileNamePrefix, len(namesFound), namesFound)
+	// The file is added to the journal as soon as it is written, so that the journal still matches
+	// the bucket if the consistency check below fails.
+	journal.AddFile(target.Journal, fileID, entry)
+	logger.Infof("Object %q added in bucket %q.", fileName, bucketName)
+
+	// Verify that the file that has just been created is in fact present in the target system
+	// and measure the time taken for it to be listed.
+	fileNamePrefix := fmt.Sprintf(FileNamePrefixFormat, fileID)
+	listed := func(ctx context.Context) (bool, error) {
+		namesFound, err := listFiles(ctx, target, client, fileNamePrefix)
+		if err != nil {
+			return false, err
+		}
+		for _, name := range namesFound {
+			if name != fileName {
+				return false, metrics.NewProbeError(metrics.UnknownFileFound, fmt.Errorf("expected exactly one file in bucket %q with prefix %q; found %d: %v", bucketName, fileNamePrefix, len(namesFound), namesFound))
+			}
+		}
+		return len(namesFound) == 1, nil
 	}
-	if namesFound[0] != fileName {
-		fmt.Errorf("CreateFile check failed: filename matching %q prefix: %q; want %q", fileNamePrefix, namesFound[0], fileName)
+	if err := consistency.Measure(ctx, target, metrics.ListAfterWrite, listed); err != nil {
+		return fmt.Errorf("CreateFile check failed: %w", err)
 	}
-	target.LatencyMetrics.APICallLatency[metrics.APIListFiles][metrics.Success].Metric(hermesAPILatencySeconds).AddFloat64(finish.Sub(start).Seconds())
-
-	target.Journal.Filenames[fileID] = fileName
-	logger.Infof("Object %q added in bucket %q.", fileName, bucketName)
 	return nil
 }
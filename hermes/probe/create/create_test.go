@@ -23,18 +23,20 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
-	"github.com/golang/protobuf/proto"
+	"cloud.google.com/go/storage"
+	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
 	"github.com/googleinterns/step224-2020/hermes/probe/fakegcs"
+	"github.com/googleinterns/step224-2020/hermes/probe/layout"
 	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/probetest"
 	"github.com/googleinterns/step224-2020/hermes/probe/target"
 
-	metricpb "github.com/google/cloudprober/metrics/proto"
 	probepb "github.com/googleinterns/step224-2020/config/proto"
-	journalpb "github.com/googleinterns/step224-2020/hermes/proto"
 )
 
-var fileNamePrefixLength = len(fmt.Sprintf(FileNameFormat, 0, ""))
+var fileNamePrefixLength = len(fmt.Sprintf(FileNamePrefixFormat, 0))
 
 func TestNewRandomFile(t *testing.T) {
 	tests := []struct {
@@ -45,13 +47,13 @@ func TestNewRandomFile(t *testing.T) {
 	}{
 		{51, 12, nil, true},
 		{0, 50, nil, true},
-		{3, 100, &randomFile{3, 100}, false},
-		{12, 100, &randomFile{12, 100}, false},
+		{3, 100, &randomFile{3, 100, 3}, false},
+		{12, 100, &randomFile{12, 100, 12}, false},
 		{3, 0, nil, true},
 		{3, 1001, nil, true},
 	}
 	for _, tc := range tests {
-		got, err := newRandomFile(tc.fileID, tc.fileSize)
+		got, err := newRandomFile(layout.Default(), tc.fileID, tc.fileSize, int64(tc.fileID))
 		if tc.want == nil && got != nil {
 			t.Errorf("{%d, %d}.newRandomFile = {%d, %d} expected nil", tc.fileID, tc.fileSize, got.id, got.sizeBytes)
 		}
@@ -59,7 +61,7 @@ func TestNewRandomFile(t *testing.T) {
 			t.Errorf("{%d, %d}.newRandomFile = nil expected {%d, %d}", tc.fileID, tc.fileSize, tc.want.id, tc.want.sizeBytes)
 		}
 		if err != nil && !tc.wantErr {
-			t.Errorf("{%d, %d}.newRandomFile() failed and returned an unexpected error %w", tc.fileID, tc.fileSize, err)
+			t.Errorf("{%d, %d}.newRandomFile() failed and returned an unexpected error %v", tc.fileID, tc.fileSize, err)
 		}
 		if err == nil && tc.wantErr {
 			t.Errorf("{%d, %d}.newRandomFile() failed expected an error got nil", tc.fileID, tc.fileSize)
@@ -72,35 +74,56 @@ func TestNewRandomFile(t *testing.T) {
 
 }
 
+func TestNewResumableFile(t *testing.T) {
+	tests := []struct {
+		fileSize int
+		wantErr  bool
+	}{
+		{100, false},
+		{maxFileSizeBytes + 1, false},
+		{maxResumableFileSizeBytes, false},
+		{maxResumableFileSizeBytes + 1, true},
+		{0, true},
+	}
+	for _, tc := range tests {
+		if _, err := newResumableFile(layout.Default(), 3, tc.fileSize, 3); (err != nil) != tc.wantErr {
+			t.Errorf("newResumableFile(3, %d) = %v, want error: %v", tc.fileSize, err, tc.wantErr)
+		}
+	}
+}
+
 func TestFileName(t *testing.T) {
 	tests := []struct {
 		file *randomFile
+		alg  probepb.Target_ChecksumAlgorithm
 		want string
 	}{
-		{&randomFile{3, 100}, "Hermes_03_"},
-		{&randomFile{12, 100}, "Hermes_12_"},
-		{&randomFile{8, 20}, "Hermes_08_"},
+		{&randomFile{3, 100, 3}, probepb.Target_CHECKSUM_ALGORITHM_UNSPECIFIED, "Hermes_03_0000000000000003_sha1_"},
+		{&randomFile{12, 100, 12}, probepb.Target_SHA1, "Hermes_12_000000000000000c_sha1_"},
+		{&randomFile{8, 20, 8}, probepb.Target_SHA256, "Hermes_08_0000000000000008_sha256_"},
+		{&randomFile{8, 20, 8}, probepb.Target_CRC32C, "Hermes_08_0000000000000008_crc32c_"},
+		{&randomFile{8, 20, 8}, probepb.Target_MD5, "Hermes_08_0000000000000008_md5_"},
 	}
 
 	for _, tc := range tests {
-		got, err := tc.file.fileName()
+		got, err := tc.file.fileName(tc.alg)
 		if err != nil {
-			t.Errorf("{%d, %d}.fileName() failed and returned an unexpected error %w", tc.file.id, tc.file.sizeBytes, err)
+			t.Errorf("{%d, %d}.fileName(%v) failed and returned an unexpected error %v", tc.file.id, tc.file.sizeBytes, tc.alg, err)
 		}
 		if !strings.HasPrefix(got, tc.want) {
-			t.Errorf("{%d, %d}.fileName() =  %qchecksum expected %qchecksum", tc.file.id, tc.file.sizeBytes, got[:fileNamePrefixLength], tc.want)
+			t.Errorf("{%d, %d}.fileName(%v) =  %q expected %qchecksum", tc.file.id, tc.file.sizeBytes, tc.alg, got, tc.want)
 		}
 	}
 }
 
 func TestChecksum(t *testing.T) {
-	file := randomFile{11, 100}
-	checksum, err := file.checksum()
+	file := randomFile{11, 100, 11}
+	checksum, err := file.checksum(probepb.Target_SHA1)
 	if err != nil {
 		t.Error(err)
 	}
-	otherFile := randomFile{13, 100}
-	otherChecksum, err := otherFile.checksum()
+	otherFile := randomFile{13, 100, 13}
+	otherChecksum, err := otherFile.checksum(probepb.Target_SHA1)
 	if err != nil {
 		t.Error(err)
 	}
@@ -108,8 +131,16 @@ func TestChecksum(t *testing.T) {
 		t.Errorf("{%d, %d}.checksum = {%d,%d}.checksum expected {%d, %d}.checksum != {%d, %d}.checksum ", file.id, file.sizeBytes, otherFile.id, otherFile.sizeBytes, file.id, file.sizeBytes, otherFile.id, otherFile.sizeBytes)
 
 	}
-	file = randomFile{11, 100}
-	checksumAgain, err := file.checksum()
+	seededFile := randomFile{11, 100, 12}
+	seededChecksum, err := seededFile.checksum(probepb.Target_SHA1)
+	if err != nil {
+		t.Error(err)
+	}
+	if fmt.Sprintf("%x", checksum) == fmt.Sprintf("%x", seededChecksum) {
+		t.Errorf("checksums of file %d created with seeds %d and %d are equal, expected them to differ", file.id, file.seed, seededFile.seed)
+	}
+	file = randomFile{11, 100, 11}
+	checksumAgain, err := file.checksum(probepb.Target_SHA1)
 	if err != nil {
 		t.Error(err)
 	}
@@ -128,55 +159,146 @@ func TestCreateFile(t *testing.T) {
 	}
 	fileID := int32(6)
 	fileSize := 50
-	target := &target.Target{
-		&probepb.Target{
-			Name:                   "hermes",
-			TargetSystem:           probepb.Target_GOOGLE_CLOUD_STORAGE,
-			TotalSpaceAllocatedMib: int64(1000),
-			BucketName:             "test_bucket_probe0",
-		},
-		&journalpb.StateJournal{
-			Filenames: make(map[int32]string),
-		},
-		&metrics.Metrics{},
-	}
-	hp := &probepb.HermesProbeDef{
-		ProbeName: proto.String("createfile_test"),
-		Targets: []*probepb.Target{
-			&probepb.Target{
-				Name:                   "hermes",
-				TargetSystem:           probepb.Target_GOOGLE_CLOUD_STORAGE,
-				TotalSpaceAllocatedMib: int64(100),
-				BucketName:             "test_bucket_probe0",
-			},
-		},
-		TargetSystem: probepb.HermesProbeDef_GCS.Enum(),
-		IntervalSec:  proto.Int32(3600),
-		TimeoutSec:   proto.Int32(60),
-		ProbeLatencyDistribution: &metricpb.Dist{
-			Buckets: &metricpb.Dist_ExplicitBuckets{
-				ExplicitBuckets: "0.1,0.2,0.4,0.6,0.8,1.6,3.2,6.4,12.8,1",
-			},
-		},
-		ApiCallLatencyDistribution: &metricpb.Dist{
-			Buckets: &metricpb.Dist_ExplicitBuckets{
-				ExplicitBuckets: "0.1,0.2,0.4,0.6,0.8,1.6,3.2,6.4,12.8,1",
-			},
-		},
-	}
-	probeTarget := &probepb.Target{
-		Name:                   "hermes",
-		TargetSystem:           probepb.Target_GOOGLE_CLOUD_STORAGE,
-		TotalSpaceAllocatedMib: int64(100),
-		BucketName:             "test_bucket_probe0",
-	}
-
-	var err error
-	if target.LatencyMetrics, err = metrics.NewMetrics(hp, probeTarget); err != nil {
-		t.Fatalf("metrics.NewMetrics(): %v", err)
-	}
+	target := probetest.NewTarget(t, "createfile_test", probetest.TargetConfig(bucketName))
 	logger := fakegcs.NewLogger(ctx).Logger
 	if err := CreateFile(ctx, target, fileID, fileSize, client, logger); err != nil {
 		t.Error(err)
 	}
+	entry, ok := target.Journal.Files[fileID]
+	if !ok {
+		t.Fatalf("CreateFile() did not record file %d in the journal", fileID)
+	}
+	if entry.GetFilename() != target.Journal.Filenames[fileID] || entry.GetSizeBytes() != int64(fileSize) || entry.GetChecksumAlgorithm() != "sha1" || entry.GetLastStatus() != "success" {
+		t.Errorf("CreateFile() recorded journal entry %v, want filename %q, size %d, algorithm sha1 and status success", entry, target.Journal.Filenames[fileID], fileSize)
+	}
+	if entry.GetGeneration() == 0 || entry.GetCreatedUnixSec() == 0 {
+		t.Errorf("CreateFile() recorded journal entry %v, want the generation and creation time of the object", entry)
+	}
+	if entry.GetChecksum() == "" || !strings.HasSuffix(entry.GetFilename(), entry.GetChecksum()) {
+		t.Errorf("CreateFile() recorded checksum %q of permanent file %d, want the checksum in the file name %q", entry.GetChecksum(), fileID, entry.GetFilename())
+	}
+}
+
+func TestCreateFileUsesNewSeed(t *testing.T) {
+	ctx := context.Background()
+	bucketName := "test_bucket_probe0"
+	target := probetest.NewTarget(t, "createfile_seed_test", probetest.TargetConfig(bucketName))
+	logger := fakegcs.NewLogger(ctx).Logger
+
+	fileID := int32(7)
+	names := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		// A new bucket and journal entry are used for each file as CreateFile will not overwrite an existing file with the same ID.
+		client := fakegcs.NewClient()
+		if err := client.Bucket(bucketName).Create(ctx, bucketName, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := CreateFile(ctx, target, fileID, 100, client, logger); err != nil {
+			t.Fatalf("CreateFile(ID: %d) failed: %v", fileID, err)
+		}
+		names[target.Journal.Filenames[fileID]] = true
+		delete(target.Journal.Filenames, fileID)
+	}
+	if len(names) != 2 {
+		t.Errorf("CreateFile(ID: %d) created files with the same name twice: %v, expected a new seed for each file", fileID, names)
+	}
+}
+
+// newResumableTestTarget returns a target in RESUMABLE upload mode with an empty journal and a bucket in the client given.
+func TestCreateFileConsistencyLagExceeded(t *testing.T) {
+	ctx := context.Background()
+	bucketName := "test_bucket_probe0"
+	target := probetest.NewTarget(t, "createfile_lag_test", probetest.TargetConfig(bucketName))
+	target.Target.Consistency = &probepb.Target_ConsistencyConfig{InitialBackoffMs: 1, MaxBackoffMs: 1, TimeoutSec: 5, LagSloSec: 0.001}
+	client := fakegcs.NewClient()
+	if err := client.Bucket(bucketName).Create(ctx, bucketName, nil); err != nil {
+		t.Fatal(err)
+	}
+	logger := fakegcs.NewLogger(ctx).Logger
+
+	fileID := int32(7)
+	err := CreateFile(ctx, target, fileID, 100, probetest.NewSlowListClient(client, 20*time.Millisecond), logger)
+	if got := metrics.StatusOf(err); got != metrics.ConsistencyLagExceeded {
+		t.Fatalf("CreateFile(ID: %d) with slow listings = %v, want status %q", fileID, err, metrics.ExitStatusName[metrics.ConsistencyLagExceeded])
+	}
+	// The file was written, so it is in the journal although it was listed too late.
+	if _, err := client.Bucket(bucketName).Object(target.Journal.Filenames[fileID]).Attrs(ctx); err != nil {
+		t.Errorf("CreateFile(ID: %d) recorded file %q in the journal, which is not in the bucket: %v", fileID, target.Journal.Filenames[fileID], err)
+	}
+}
+
+func newResumableTestTarget(ctx context.Context, t *testing.T, client stiface.Client) *target.Target {
+	t.Helper()
+	bucketName := "test_bucket_probe0"
+	if err := client.Bucket(bucketName).Create(ctx, bucketName, nil); err != nil {
+		t.Fatal(err)
+	}
+	target := probetest.NewTarget(t, "createfile_resumable_test", probetest.TargetConfig(bucketName))
+	target.Target.UploadMode = probepb.Target_RESUMABLE
+	target.Target.UploadChunkSizeBytes = 100
+	return target
+}
+
+func TestCreateFileResumable(t *testing.T) {
+	ctx := context.Background()
+	client := fakegcs.NewClient()
+	target := newResumableTestTarget(ctx, t, client)
+	logger := fakegcs.NewLogger(ctx).Logger
+
+	fileID := int32(9)
+	fileSize := 2 * maxFileSizeBytes
+	if err := CreateFile(ctx, target, fileID, fileSize, client, logger); err != nil {
+		t.Fatalf("CreateFile(ID: %d) in RESUMABLE upload mode failed: %v", fileID, err)
+	}
+	attrs, err := client.Bucket(target.Target.GetBucketName()).Object(target.Journal.Filenames[fileID]).Attrs(ctx)
+	if err != nil {
+		t.Fatalf("CreateFile(ID: %d) did not create the file: %v", fileID, err)
+	}
+	if attrs.Size != int64(fileSize) {
+		t.Errorf("CreateFile(ID: %d) created a file of %d bytes, want %d bytes", fileID, attrs.Size, fileSize)
+	}
+}
+
+func TestAbortUpload(t *testing.T) {
+	ctx := context.Background()
+	client := fakegcs.NewClient()
+	target := newResumableTestTarget(ctx, t, client)
+	logger := fakegcs.NewLogger(ctx).Logger
+
+	fileID := int32(9)
+	if err := AbortUpload(ctx, target, fileID, client, logger); err != nil {
+		t.Fatalf("AbortUpload(ID: %d) failed: %v", fileID, err)
+	}
+	names, err := listFiles(ctx, target, client, "Hermes_aborted_")
+	if err != nil {
+		t.Fatalf("listFiles() failed: %v", err)
+	}
+	if len(names) != 0 {
+		t.Errorf("AbortUpload(ID: %d) left files %v, want the aborted upload not to create a file", fileID, names)
+	}
+}
+
+func TestAbortUploadFindsIncompleteUpload(t *testing.T) {
+	ctx := context.Background()
+	client := fakegcs.NewClient()
+	target := newResumableTestTarget(ctx, t, client)
+	logger := fakegcs.NewLogger(ctx).Logger
+
+	f := &randomFile{id: 4, sizeBytes: 500, seed: 42}
+	abortedName := fmt.Sprintf(AbortedFileNameFormat, f.id, f.seed)
+	obj := client.Bucket(target.Target.GetBucketName()).Object(abortedName)
+	wc := obj.NewWriter(ctx)
+	if _, err := wc.Write([]byte("incomplete")); err != nil {
+		t.Fatalf("Write(%q) failed: %v", abortedName, err)
+	}
+	if err := wc.Close(); err != nil {
+		t.Fatalf("Close(%q) failed: %v", abortedName, err)
+	}
+
+	if got := metrics.StatusOf(abortUpload(ctx, target, client, f, logger)); got != metrics.IncompleteUploadFound {
+		t.Errorf("abortUpload() status = %q, want %q", metrics.ExitStatusName[got], metrics.ExitStatusName[metrics.IncompleteUploadFound])
+	}
+	if _, err := obj.Attrs(ctx); err != storage.ErrObjectNotExist {
+		t.Errorf("Attrs(%q) = %v, want %v: abortUpload() should delete the incomplete upload", abortedName, err, storage.ErrObjectNotExist)
+	}
 }
@@ -23,12 +23,14 @@ package delete
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"time"
 
 	"cloud.google.com/go/storage"
 	"github.com/google/cloudprober/logger"
 	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+	"github.com/googleinterns/step224-2020/hermes/probe/consistency"
+	"github.com/googleinterns/step224-2020/hermes/probe/journal"
+	"github.com/googleinterns/step224-2020/hermes/probe/layout"
 	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
 	"github.com/googleinterns/step224-2020/hermes/probe/target"
 	"google.golang.org/api/iterator"
@@ -37,16 +39,16 @@ import (
 )
 
 const (
-	apiLatency        = "hermes_api_latency_seconds" // TODO(evanSpendlove) add this constant to metrics.go
-	minFileIDToDelete = 11                           // we can delete files starting from the file Hermes_11
-	maxFileIDToDelete = 50                           // there are 40 files to delete from [Hermes_11,Hermes_50]
+	apiLatency = "hermes_api_latency_seconds" // TODO(evanSpendlove) add this constant to metrics.go
 )
 
 // DeleteFile deletes the file, corresponding to the ID passed, in the target storage system bucket.
-// It then checks that the file has been deleted by trying to get the object.
+// It then polls the target until the file is no longer listed and can no longer be read,
+// recording the time taken as the list-after-delete and read-after-delete consistency lag.
+// The file is removed from the journal once it is deleted, even if the consistency checks fail.
 // Arguments:
 //	- ctx: context allows this probe can be cancelled if needed.
-// - fileID: ID of the file to be deleted. Must be within inclusive range: 11-50.
+//	- fileID: ID of the file to be deleted. Must be a rotating file in the file layout of the target.
 //	- config: HermesProbeDef config for the probe calling this function.
 //	- target: target run information stored in struct from probe/probe.go
 //	- client: initialised storage client for this target system.
@@ -59,11 +61,17 @@ const (
 //		- FileMissing: the file to be deleted could not be found in the target bucket.
 //		- BucketMissing: the target bucket on this target system was not found.
 //		- ProbeFailed: there was an error during one of the API calls and the probe failed.
+//		- OpTimeout: the deleted file was still listed or readable when the consistency timeout expired.
+//		- ConsistencyLagExceeded: the deleted file took longer than the lag SLO to disappear.
 func DeleteFile(ctx context.Context, fileID int32, target *target.Target, client stiface.Client, logger *logger.Logger) (int32, error) {
 	bucket := target.Target.GetBucketName()
 
-	if fileID < minFileIDToDelete || fileID > maxFileIDToDelete {
-		return fileID, fmt.Errorf("DeleteFile(%q, %q) failed; status %v: expected fileID %d to be within valid inclusive range: 11-50", bucket, fileID, metrics.InvalidArgument, fileID)
+	l, err := layout.New(target.Target.GetFileLayout())
+	if err != nil {
+		return fileID, metrics.NewProbeError(metrics.InvalidArgument, fmt.Errorf("DeleteFile(%q, %d) failed: %w", bucket, fileID, err))
+	}
+	if !l.IsRotating(fileID) {
+		return fileID, metrics.NewProbeError(metrics.InvalidArgument, fmt.Errorf("DeleteFile(%q, %d) failed: expected fileID %d to be within valid inclusive range: %d-%d", bucket, fileID, fileID, l.MinRotatingFileID(), l.MaxFileID()))
 	}
 
 	// TODO(evanSpendlove): Add custom error object to return value and modify all returns.
@@ -73,7 +81,7 @@ func DeleteFile(ctx context.Context, fileID int32, target *target.Target, client
 	}
 
 	target.Journal.Intent = &pb.Intent{
-		FileOperation: pb.Intent_CREATE,
+		FileOperation: pb.Intent_DELETE,
 		Filename:      filename,
 	}
 
@@ -97,39 +105,82 @@ func DeleteFile(ctx context.Context, fileID int32, target *target.Target, client
 	}
 	target.LatencyMetrics.APICallLatency[metrics.APIDeleteFile][metrics.Success].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
 
-	query := &storage.Query{Prefix: filename}
+	// Update in-memory NIL file as soon as the file is deleted, so that the journal still matches
+	// the bucket if the consistency checks below fail.
+	journal.RemoveFile(target.Journal, fileID)
+	target.Journal.LastDeletedFileId = fileID
+	target.Journal.Deletions++
+	logger.Infof("Object %v deleted in bucket %s.", file, bucket)
+
+	// Measure the time taken for the deleted file to no longer be listed and to no longer be readable.
+	unlisted := func(ctx context.Context) (bool, error) {
+		return isUnlisted(ctx, target, client, filename)
+	}
+	if err := consistency.Measure(ctx, target, metrics.ListAfterDelete, unlisted); err != nil {
+		return fileID, fmt.Errorf("DeleteFile(%q, %q) failed; status %v: %w", bucket, filename, metrics.StatusOf(err), err)
+	}
+	unreadable := func(ctx context.Context) (bool, error) {
+		return isUnreadable(ctx, target, file)
+	}
+	if err := consistency.Measure(ctx, target, metrics.ReadAfterDelete, unreadable); err != nil {
+		return fileID, fmt.Errorf("DeleteFile(%q, %q) failed; status %v: %w", bucket, filename, metrics.StatusOf(err), err)
+	}
+
+	return fileID, nil
+}
+
+// isUnlisted reports whether the file is no longer listed in the target bucket.
+// It records the latency of the list files API call.
+func isUnlisted(ctx context.Context, target *target.Target, client stiface.Client, filename string) (bool, error) {
 	// TODO(#77): Refactor timing into using function from metrics.go
-	start = time.Now()
-	objects := client.Bucket(bucket).Objects(ctx, query)
+	start := time.Now()
+	objects := client.Bucket(target.Target.GetBucketName()).Objects(ctx, &storage.Query{Prefix: filename})
 	for {
 		obj, err := objects.Next()
 		if err == iterator.Done {
 			break
 		}
-		if obj.Name == filename {
-			status := metrics.ProbeFailed
-			target.LatencyMetrics.APICallLatency[metrics.APIListFiles][status].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
-			return fileID, fmt.Errorf("DeleteFile(%q, %q) failed; status %v: object %v still listed after delete", bucket, filename, status, obj.Name)
-		}
 		if err != nil {
-			status := metrics.BucketMissing
+			status := metrics.APICallFailed
+			if err == storage.ErrBucketNotExist {
+				status = metrics.BucketMissing
+			}
 			target.LatencyMetrics.APICallLatency[metrics.APIListFiles][status].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
-			return fileID, fmt.Errorf("DeleteFile(%q, %q) failed; status %v: %w", bucket, filename, status, err)
+			return false, metrics.NewProbeError(status, err)
+		}
+		if obj.Name == filename {
+			target.LatencyMetrics.APICallLatency[metrics.APIListFiles][metrics.Success].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+			return false, nil
 		}
 	}
 	target.LatencyMetrics.APICallLatency[metrics.APIListFiles][metrics.Success].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
-
-	// Update in-memory NIL file after delete operation.
-	delete(target.Journal.Filenames, fileID)
-
-	logger.Infof("Object %v deleted in bucket %s.", file, bucket)
-	return fileID, nil
+	return true, nil
 }
 
-// PickFileToDelete picks which file to delete and returns the integer ID of this file.
-// Returns:
-//	- ID: returns the ID of the file to be deleted.
-func PickFileToDelete() int32 {
-	rand.Seed(time.Now().UnixNano())
-	return int32(rand.Intn(maxFileIDToDelete-minFileIDToDelete+1) + minFileIDToDelete)
+// isUnreadable reports whether the file can no longer be read from the target bucket.
+// It records the latency of the get file attributes API call.
+func isUnreadable(ctx context.Context, target *target.Target, file stiface.ObjectHandle) (bool, error) {
+	// TODO(#77): Refactor timing into using function from metrics.go
+	start := time.Now()
+	_, err := file.Attrs(ctx)
+	var status metrics.ExitStatus
+	switch err {
+	case nil:
+		status = metrics.Success
+	case storage.ErrObjectNotExist:
+		status = metrics.FileMissing
+	case storage.ErrBucketNotExist:
+		status = metrics.BucketMissing
+	default:
+		status = metrics.APICallFailed
+	}
+	target.LatencyMetrics.APICallLatency[metrics.APIGetFileAttrs][status].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+	switch status {
+	case metrics.Success:
+		return false, nil
+	case metrics.FileMissing:
+		return true, nil
+	default:
+		return false, metrics.NewProbeError(status, err)
+	}
 }
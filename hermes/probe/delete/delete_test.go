@@ -24,60 +24,18 @@ import (
 	"testing"
 
 	"cloud.google.com/go/storage"
-	"github.com/golang/protobuf/proto"
 	"github.com/google/cloudprober/logger"
 	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
-	"github.com/googleinterns/step224-2020/hermes/probe"
 	"github.com/googleinterns/step224-2020/hermes/probe/fakegcs"
+	"github.com/googleinterns/step224-2020/hermes/probe/probetest"
 	"github.com/googleinterns/step224-2020/hermes/probe/target"
 	"google.golang.org/api/iterator"
 
-	metricpb "github.com/google/cloudprober/metrics/proto"
 	monitorpb "github.com/googleinterns/step224-2020/config/proto"
 	m "github.com/googleinterns/step224-2020/hermes/probe/metrics"
 	journalpb "github.com/googleinterns/step224-2020/hermes/proto"
 )
 
-// genTestConfig generates a test HermesProbeDef proto config.
-// Arguments:
-//	- name: pass the name for this probe instance.
-// Returns:
-//	- hermesExtension: returns the HermesProbeDef extension.
-func genTestConfig(name string) *monitorpb.HermesProbeDef {
-	hermesExtension := &monitorpb.HermesProbeDef{
-		ProbeName: proto.String(name),
-		Targets: []*monitorpb.Target{
-			genTargetPb(),
-		},
-		TargetSystem: monitorpb.HermesProbeDef_GCS.Enum(),
-		IntervalSec:  proto.Int32(3600),
-		TimeoutSec:   proto.Int32(60),
-		ProbeLatencyDistribution: &metricpb.Dist{
-			Buckets: &metricpb.Dist_ExplicitBuckets{
-				ExplicitBuckets: "0.1,0.2,0.4,0.6,0.8,1.6,3.2,6.4,12.8,1000",
-			},
-		},
-		ApiCallLatencyDistribution: &metricpb.Dist{
-			Buckets: &metricpb.Dist_ExplicitBuckets{
-				ExplicitBuckets: "0.000000002,0.000000004,0.000000008,0.000000016,0.00000032,0.000000064,0.000000128,100",
-			},
-		},
-	}
-	return hermesExtension
-}
-
-// getTargetPb generates a target proto config.
-// Returns:
-//	- target: target proto config
-func genTargetPb() *monitorpb.Target {
-	return &monitorpb.Target{
-		Name:                   "hermes",
-		TargetSystem:           monitorpb.Target_GOOGLE_CLOUD_STORAGE,
-		TotalSpaceAllocatedMib: int64(100),
-		BucketName:             bucketName,
-	}
-}
-
 const (
 	// TODO(#76): Change these to int from int32.
 	firstID    = int32(1)
@@ -87,33 +45,14 @@ const (
 	bucketName = "test_bucket_5"
 )
 
-// genTestTarget generates an initialised test Target struct.
-func genTestTarget(cfg *monitorpb.HermesProbeDef, t *testing.T) *target.Target {
-	filenames := make(map[int32]string)
-
+// genTestTarget generates a target with a journal holding the names of files 1-50.
+func genTestTarget(t *testing.T) *target.Target {
+	t.Helper()
+	target := probetest.NewTarget(t, "delete_test", probetest.TargetConfig(bucketName))
 	for i := firstID; i <= lastID; i++ {
-		var id string
-		if i <= 9 {
-			id = fmt.Sprintf("0%d", i)
-		} else {
-			id = fmt.Sprintf("%d", i)
-		}
-		filenames[i] = fmt.Sprintf("Hermes_%s_%s", id, hash)
-	}
-
-	metrics, err := m.NewMetrics(cfg, genTargetPb())
-	if err != nil {
-		t.Fatalf("could not initialise metrics using config and target provided: %v", err)
-	}
-
-	return &target.Target{
-		Target: cfg.GetTargets()[0],
-		Journal: &journalpb.StateJournal{
-			Intent:    &journalpb.Intent{},
-			Filenames: filenames,
-		},
-		LatencyMetrics: metrics,
+		target.Journal.Filenames[i] = fmt.Sprintf("Hermes_%02d_%s", i, hash)
 	}
+	return target
 }
 
 // createTestFiles creates a test bucket and the required test files.
@@ -145,17 +84,26 @@ func TestDeleteRandomFile(t *testing.T) {
 	client := fakegcs.NewClient()
 	createTestFiles(ctx, client, t)
 
-	target := genTestTarget(genTestConfig(testProbeName), t)
+	target := genTestTarget(t)
 
 	logger, err := logger.NewCloudproberLog(testProbeName)
 	if err != nil {
 		t.Fatalf("failed to initialise logger: %v", err)
 	}
 
-	fileID, err := DeleteFile(ctx, PickFileToDelete(), target, client, logger)
+	fileID, err := DeleteFile(ctx, mustPick(t, target), target, client, logger)
 	if err != nil {
 		t.Errorf("deleteRandomFile(ID: %d) failed: expected error as %v, got %v", fileID, nil, err)
 	}
+	if got := target.Journal.GetIntent().GetFileOperation(); got != journalpb.Intent_DELETE {
+		t.Errorf("deleteRandomFile(ID: %d) logged intent %v, want %v", fileID, got, journalpb.Intent_DELETE)
+	}
+	if got := target.Journal.GetLastDeletedFileId(); got != fileID {
+		t.Errorf("deleteRandomFile(ID: %d) recorded last deleted file %d, want %d", fileID, got, fileID)
+	}
+	if got := target.Journal.GetDeletions(); got != 1 {
+		t.Errorf("deleteRandomFile(ID: %d) recorded %d deletions, want 1", fileID, got)
+	}
 	filename := target.Journal.Filenames[fileID]
 	objects := client.Bucket(bucketName).Objects(ctx, &storage.Query{Prefix: filename})
 	for {
@@ -173,3 +121,33 @@ func TestDeleteRandomFile(t *testing.T) {
 }
 
 // TODO(evanSpendlove): Add more tests that check that DeleteFile() throws the correct errors.
+
+func TestDeleteFileOutsideRotatingRange(t *testing.T) {
+	testProbeName := "testDeleteLayout"
+	ctx := context.Background()
+
+	client := fakegcs.NewClient()
+	createTestFiles(ctx, client, t)
+
+	target := genTestTarget(t)
+	target.Target.FileLayout = &monitorpb.Target_FileLayout{NumFiles: lastID, NumPermanentFiles: 20}
+
+	logger, err := logger.NewCloudproberLog(testProbeName)
+	if err != nil {
+		t.Fatalf("failed to initialise logger: %v", err)
+	}
+
+	for _, id := range []int32{0, 1, 20, lastID + 1} {
+		if _, err := DeleteFile(ctx, id, target, client, logger); m.StatusOf(err) != m.InvalidArgument {
+			t.Errorf("DeleteFile(ID: %d) = %v, want status %q", id, err, m.ExitStatusName[m.InvalidArgument])
+		}
+		if _, ok := target.Journal.Filenames[id]; !ok && id >= firstID && id <= lastID {
+			t.Errorf("DeleteFile(ID: %d) removed a file outside the rotating range from the journal", id)
+		}
+	}
+	for i := 0; i < 20; i++ {
+		if id := mustPick(t, target); id <= 20 || id > lastID {
+			t.Errorf("PickFileToDelete() = %d, want a rotating file in [21, %d]", id, lastID)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Pick implements the strategies used to pick the rotating file to delete.
+
+package delete
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/googleinterns/step224-2020/hermes/probe/layout"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+var (
+	// rng is the source of the RANDOM strategy. It is seeded once, rather than on every pick,
+	// and shared by the targets, which are probed concurrently.
+	rng   = rand.New(rand.NewSource(time.Now().UnixNano()))
+	rngMu sync.Mutex
+)
+
+// candidates returns the IDs of the rotating files in the journal of the target in ascending order.
+func candidates(target *target.Target) ([]int32, error) {
+	l, err := layout.New(target.Target.GetFileLayout())
+	if err != nil {
+		return nil, metrics.NewProbeError(metrics.InvalidArgument, err)
+	}
+	var ids []int32
+	for _, id := range l.RotatingFileIDs() {
+		if _, ok := target.Journal.Filenames[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, metrics.NewProbeError(metrics.FileMissing, fmt.Errorf("no rotating files %d-%d in the journal", l.MinRotatingFileID(), l.MaxFileID()))
+	}
+	return ids, nil
+}
+
+// pickRoundRobin returns the first ID after the last deleted file ID, wrapping around to the first ID.
+func pickRoundRobin(ids []int32, lastDeleted int32) int32 {
+	for _, id := range ids {
+		if id > lastDeleted {
+			return id
+		}
+	}
+	return ids[0]
+}
+
+// pickOldest returns the ID with the smallest time in the map given. IDs without a time are the oldest.
+// Ties are broken by the smallest ID.
+func pickOldest(ids []int32, times map[int32]int64) int32 {
+	oldest := ids[0]
+	for _, id := range ids[1:] {
+		if times[id] < times[oldest] {
+			oldest = id
+		}
+	}
+	return oldest
+}
+
+// created returns the time at which each file was created.
+func created(ids []int32, target *target.Target) map[int32]int64 {
+	times := make(map[int32]int64, len(ids))
+	for _, id := range ids {
+		times[id] = target.Journal.Files[id].GetCreatedUnixSec()
+	}
+	return times
+}
+
+// lastVerified returns the time at which each file was last verified, or created if it was never verified.
+func lastVerified(ids []int32, target *target.Target) map[int32]int64 {
+	times := make(map[int32]int64, len(ids))
+	for _, id := range ids {
+		entry := target.Journal.Files[id]
+		times[id] = entry.GetCreatedUnixSec()
+		if t := entry.GetLastVerifiedUnixSec(); t != 0 {
+			times[id] = t
+		}
+	}
+	return times
+}
+
+// pickSeeded returns the file at the position of the deletion given in a random order of the files.
+// The order is shuffled again, from the seed given and the cycle number, at the start of every cycle of len(ids) deletions.
+func pickSeeded(ids []int32, seed, deletions int64) int32 {
+	n := int64(len(ids))
+	order := rand.New(rand.NewSource(seed + deletions/n)).Perm(len(ids))
+	return ids[order[deletions%n]]
+}
+
+// PickFileToDelete picks which rotating file to delete using the delete strategy of the target and returns its ID.
+// Only rotating files in the journal of the target are picked. The strategies use the file metadata in the journal:
+//	- RANDOM: a file picked uniformly at random.
+//	- ROUND_ROBIN: the file after the last deleted file, in ascending order of ID.
+//	- OLDEST_FIRST: the file that was created first.
+//	- LEAST_RECENTLY_VERIFIED: the file whose contents were verified least recently.
+//	- SEEDED: the files in a random order, deterministically from the delete seed and the number of files deleted.
+//	  Every rotating file is picked once in every cycle of as many deletions as there are rotating files.
+// Arguments:
+//	- target: the target to pick a file on.
+// Returns:
+//	- ID: returns the ID of the file to be deleted.
+//	- err: returns a *metrics.ProbeError if there are no rotating files in the journal or the file layout is invalid.
+func PickFileToDelete(target *target.Target) (int32, error) {
+	ids, err := candidates(target)
+	if err != nil {
+		return 0, fmt.Errorf("PickFileToDelete(%q) failed: %w", target.Target.GetBucketName(), err)
+	}
+	lastDeleted := target.Journal.GetLastDeletedFileId()
+	switch target.Target.GetDeleteStrategy() {
+	case probepb.Target_ROUND_ROBIN:
+		return pickRoundRobin(ids, lastDeleted), nil
+	case probepb.Target_OLDEST_FIRST:
+		return pickOldest(ids, created(ids, target)), nil
+	case probepb.Target_LEAST_RECENTLY_VERIFIED:
+		return pickOldest(ids, lastVerified(ids, target)), nil
+	case probepb.Target_SEEDED:
+		return pickSeeded(ids, target.Target.GetDeleteSeed(), target.Journal.GetDeletions()), nil
+	default:
+		rngMu.Lock()
+		defer rngMu.Unlock()
+		return ids[rng.Intn(len(ids))], nil
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delete
+
+import (
+	"testing"
+
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+
+	monitorpb "github.com/googleinterns/step224-2020/config/proto"
+	journalpb "github.com/googleinterns/step224-2020/hermes/proto"
+)
+
+// mustPick picks a file to delete on the target and fails the test if no file could be picked.
+func mustPick(t *testing.T, target *target.Target) int32 {
+	t.Helper()
+	id, err := PickFileToDelete(target)
+	if err != nil {
+		t.Fatalf("PickFileToDelete() failed: %v", err)
+	}
+	return id
+}
+
+// genPickTarget generates a target with a layout of 5 files, of which files 3-5 rotate.
+func genPickTarget(t *testing.T, strategy monitorpb.Target_DeleteStrategy) *target.Target {
+	target := genTestTarget(t)
+	target.Target.FileLayout = &monitorpb.Target_FileLayout{NumFiles: 5, NumPermanentFiles: 2}
+	target.Target.DeleteStrategy = strategy
+	for id := range target.Journal.Filenames {
+		if id > 5 {
+			delete(target.Journal.Filenames, id)
+		}
+	}
+	target.Journal.Files = map[int32]*journalpb.FileEntry{
+		1: {CreatedUnixSec: 10},
+		2: {CreatedUnixSec: 20},
+		3: {CreatedUnixSec: 300, LastVerifiedUnixSec: 400},
+		4: {CreatedUnixSec: 100},
+		5: {CreatedUnixSec: 200, LastVerifiedUnixSec: 500},
+	}
+	return target
+}
+
+func TestPickFileToDelete(t *testing.T) {
+	tests := []struct {
+		desc        string
+		strategy    monitorpb.Target_DeleteStrategy
+		lastDeleted int32
+		want        int32
+	}{
+		{"round robin from the start", monitorpb.Target_ROUND_ROBIN, 0, 3},
+		{"round robin", monitorpb.Target_ROUND_ROBIN, 3, 4},
+		{"round robin wraps around", monitorpb.Target_ROUND_ROBIN, 5, 3},
+		{"oldest first", monitorpb.Target_OLDEST_FIRST, 0, 4},
+		{"least recently verified", monitorpb.Target_LEAST_RECENTLY_VERIFIED, 0, 4},
+	}
+	for _, tc := range tests {
+		target := genPickTarget(t, tc.strategy)
+		target.Journal.LastDeletedFileId = tc.lastDeleted
+		if got := mustPick(t, target); got != tc.want {
+			t.Errorf("%s: PickFileToDelete() = %d, want %d", tc.desc, got, tc.want)
+		}
+	}
+
+	target := genPickTarget(t, monitorpb.Target_LEAST_RECENTLY_VERIFIED)
+	target.Journal.Files[4].LastVerifiedUnixSec = 600
+	if got, want := mustPick(t, target), int32(3); got != want {
+		t.Errorf("least recently verified after verifying file 4: PickFileToDelete() = %d, want %d", got, want)
+	}
+}
+
+func TestPickFileToDeleteSkipsMissingFiles(t *testing.T) {
+	target := genPickTarget(t, monitorpb.Target_ROUND_ROBIN)
+	delete(target.Journal.Filenames, 3)
+	delete(target.Journal.Filenames, 4)
+	for _, strategy := range []monitorpb.Target_DeleteStrategy{monitorpb.Target_RANDOM, monitorpb.Target_ROUND_ROBIN, monitorpb.Target_OLDEST_FIRST, monitorpb.Target_SEEDED} {
+		target.Target.DeleteStrategy = strategy
+		if got := mustPick(t, target); got != 5 {
+			t.Errorf("%v: PickFileToDelete() = %d, want the only rotating file in the journal 5", strategy, got)
+		}
+	}
+	delete(target.Journal.Filenames, 5)
+	if _, err := PickFileToDelete(target); metrics.StatusOf(err) != metrics.FileMissing {
+		t.Errorf("PickFileToDelete() without rotating files = %v, want status %q", err, metrics.ExitStatusName[metrics.FileMissing])
+	}
+}
+
+func TestPickFileToDeleteSeeded(t *testing.T) {
+	picks := func(seed int64) []int32 {
+		target := genPickTarget(t, monitorpb.Target_SEEDED)
+		target.Target.DeleteSeed = seed
+		var ids []int32
+		for i := 0; i < 10; i++ {
+			id := mustPick(t, target)
+			if id < 3 || id > 5 {
+				t.Fatalf("PickFileToDelete() = %d, want a rotating file in [3, 5]", id)
+			}
+			ids = append(ids, id)
+			target.Journal.LastDeletedFileId = id
+			target.Journal.Deletions++
+		}
+		return ids
+	}
+	first, second := picks(42), picks(42)
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("PickFileToDelete() with the same seed picked %v then %v, want the same files", first, second)
+		}
+	}
+}
+
+func TestPickFileToDeleteSeededPicksEveryFile(t *testing.T) {
+	target := genPickTarget(t, monitorpb.Target_SEEDED)
+	target.Target.DeleteSeed = 7
+	// Every rotating file is picked once in every cycle of 3 deletions.
+	for cycle := 0; cycle < 5; cycle++ {
+		picked := make(map[int32]bool)
+		for i := 0; i < 3; i++ {
+			picked[mustPick(t, target)] = true
+			target.Journal.Deletions++
+		}
+		for id := int32(3); id <= 5; id++ {
+			if !picked[id] {
+				t.Errorf("cycle %d: PickFileToDelete() picked %v, want every rotating file in [3, 5]", cycle, picked)
+			}
+		}
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package durability implements the long-term durability tracking of the
+// permanent files, which are never deleted. The creation time and checksum of
+// every permanent file are recorded in its StateJournal entry and its contents are
+// verified against them on a slower schedule than the rest of the probe.
+package durability
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/google/cloudprober/logger"
+	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+	"github.com/googleinterns/step224-2020/hermes/probe/checksum"
+	"github.com/googleinterns/step224-2020/hermes/probe/journal"
+	"github.com/googleinterns/step224-2020/hermes/probe/layout"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/read"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+
+	journalpb "github.com/googleinterns/step224-2020/hermes/proto"
+)
+
+const (
+	apiLatency           = "hermes_api_latency_seconds"
+	probeLatency         = "hermes_probe_latency_seconds"
+	defaultCheckInterval = 24 * time.Hour
+	defaultMinAgeDays    = 30
+	day                  = 24 * time.Hour
+)
+
+// checkInterval returns the minimum time between two verifications of the contents of a permanent file.
+func checkInterval(target *target.Target) time.Duration {
+	if sec := target.Target.GetDurabilityCheckIntervalSec(); sec > 0 {
+		return time.Duration(sec) * time.Second
+	}
+	return defaultCheckInterval
+}
+
+// minAge returns the age above which the corruption of a permanent file is reported as DurableFileCorrupted.
+func minAge(target *target.Target) time.Duration {
+	if days := target.Target.GetDurabilityMinAgeDays(); days > 0 {
+		return time.Duration(days) * day
+	}
+	return defaultMinAgeDays * day
+}
+
+// readFile reads the contents of a file and returns their checksum and the attributes of the file.
+func readFile(ctx context.Context, target *target.Target, client stiface.Client, fileName, algorithm string) (string, *storage.ObjectAttrs, error) {
+	alg, err := checksum.Parse(algorithm)
+	if err != nil {
+		return "", nil, metrics.NewProbeError(metrics.InvalidArgument, err)
+	}
+	h, err := checksum.New(alg)
+	if err != nil {
+		return "", nil, metrics.NewProbeError(metrics.InvalidArgument, err)
+	}
+	// TODO(#77): Refactor timing into using function from metrics.go
+	start := time.Now()
+	reader, err := client.Bucket(target.Target.GetBucketName()).Object(fileName).NewReader(ctx)
+	if err != nil {
+		status := metrics.APICallFailed
+		switch err {
+		case storage.ErrObjectNotExist:
+			status = metrics.FileMissing
+		case storage.ErrBucketNotExist:
+			status = metrics.BucketMissing
+		}
+		target.LatencyMetrics.APICallLatency[metrics.APIGetFile][status].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+		return "", nil, metrics.NewProbeError(status, fmt.Errorf("could not read file %q: %w", fileName, err))
+	}
+	defer reader.Close()
+	if _, err := io.Copy(h, reader); err != nil {
+		target.LatencyMetrics.APICallLatency[metrics.APIGetFile][metrics.FileReadFailure].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+		return "", nil, metrics.NewProbeError(metrics.FileReadFailure, fmt.Errorf("could not read file %q: %w", fileName, err))
+	}
+	target.LatencyMetrics.APICallLatency[metrics.APIGetFile][metrics.Success].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+
+	start = time.Now()
+	attrs, err := client.Bucket(target.Target.GetBucketName()).Object(fileName).Attrs(ctx)
+	status := metrics.Success
+	if err != nil {
+		status = metrics.APICallFailed
+		if err == storage.ErrObjectNotExist {
+			status = metrics.FileMissing
+		}
+	}
+	target.LatencyMetrics.APICallLatency[metrics.APIGetFileAttrs][status].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+	if err != nil {
+		return "", nil, metrics.NewProbeError(status, fmt.Errorf("could not get attributes of file %q: %w", fileName, err))
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), attrs, nil
+}
+
+// verifyFile verifies the contents of a permanent file against the checksum recorded in its journal entry.
+// A file without a recorded checksum, e.g. one created before durability tracking, is verified against the
+// checksum in its name, which is then recorded, and is recorded with its creation time as reported by the
+// target if it has none. Legacy files are recorded with the checksum of their current contents instead.
+// Returns whether the file was due to be verified, in which case its exit status is to be recorded, see journal.RecordStatus.
+func verifyFile(ctx context.Context, target *target.Target, client stiface.Client, logger *logger.Logger, fileID int32, entry *journalpb.FileEntry, now time.Time) (bool, error) {
+	recorded := entry.GetChecksum() != ""
+	if recorded && now.Sub(time.Unix(entry.GetLastVerifiedUnixSec(), 0)) < checkInterval(target) {
+		return false, nil
+	}
+
+	fileName := entry.GetFilename()
+	algorithm := entry.GetChecksumAlgorithm()
+	want := entry.GetChecksum()
+	legacy := false
+	if !recorded {
+		alg, nameSum, isLegacy, err := read.FileNameChecksum(fileName, fileID)
+		if err != nil {
+			return true, metrics.NewProbeError(metrics.FileCorrupted, err)
+		}
+		legacy = isLegacy
+		if !legacy {
+			algorithm = checksum.Name(alg)
+			want = nameSum
+		}
+	}
+	if algorithm == "" {
+		algorithm = checksum.Name(target.Target.GetChecksumAlgorithm())
+	}
+	sum, attrs, err := readFile(ctx, target, client, fileName, algorithm)
+	if err != nil {
+		return true, err
+	}
+	if !recorded {
+		if entry.GetCreatedUnixSec() == 0 {
+			created := attrs.Created
+			if created.IsZero() {
+				created = now
+			}
+			entry.CreatedUnixSec = created.Unix()
+		}
+		if legacy {
+			want = sum
+		}
+		entry.ChecksumAlgorithm = algorithm
+		entry.Checksum = want
+		logger.Infof("Durability(%q): recorded %s checksum %s of file %d %q.", target.Target.GetBucketName(), algorithm, want, fileID, fileName)
+	}
+
+	if sum != want {
+		age := now.Sub(time.Unix(entry.GetCreatedUnixSec(), 0))
+		status := metrics.FileCorrupted
+		if age >= minAge(target) {
+			status = metrics.DurableFileCorrupted
+		}
+		return true, metrics.NewProbeError(status, fmt.Errorf("file %d %q created %v ago: %s checksum %s does not match the recorded checksum %s",
+			fileID, fileName, age.Round(time.Second), algorithm, sum, want))
+	}
+	return true, nil
+}
+
+// moreSevere reports whether status a is more severe than status b.
+// DurableFileCorrupted is the most severe status, followed by FileCorrupted and then any other failure.
+func moreSevere(a, b metrics.ExitStatus) bool {
+	rank := func(s metrics.ExitStatus) int {
+		switch s {
+		case metrics.Success:
+			return 0
+		case metrics.FileCorrupted:
+			return 2
+		case metrics.DurableFileCorrupted:
+			return 3
+		default:
+			return 1
+		}
+	}
+	return rank(a) > rank(b)
+}
+
+// Verify verifies the contents of every permanent file in the journal of the target whose contents
+// have not been verified for longer than the durability check interval, and exports the age of every
+// permanent file and the time since it was last verified. The exit status of every verification is
+// recorded in the journal entry of the file, and the journal is written to the journal stores if any
+// file was verified.
+// Arguments:
+//	- ctx: the context of the probe run.
+//	- target: the target whose permanent files are verified.
+//	- client: initialised storage client for this target system.
+//	- stores: the stores holding copies of the journal of the target.
+//	- logger: logger associated with the probe calling this function.
+// Returns:
+//	- err: returns a *metrics.ProbeError with the most severe status found, including:
+//		- DurableFileCorrupted: a permanent file older than the durability minimum age was corrupted.
+//		- FileCorrupted: a younger permanent file was corrupted.
+//	  Verification continues with the remaining files after an error.
+func Verify(ctx context.Context, target *target.Target, client stiface.Client, stores []journal.Store, logger *logger.Logger) error {
+	bucketName := target.Target.GetBucketName()
+	l, err := layout.New(target.Target.GetFileLayout())
+	if err != nil {
+		return metrics.NewProbeError(metrics.InvalidArgument, fmt.Errorf("Verify(%q) failed: %w", bucketName, err))
+	}
+	// TODO(#77): Refactor timing into using function from metrics.go
+	start := time.Now()
+	status := metrics.Success
+	var errs []error
+	changed := false
+	for id := int32(layout.MinFileID); id <= l.MaxPermanentFileID(); id++ {
+		entry, ok := target.Journal.Files[id]
+		if !ok {
+			continue
+		}
+		checked, err := verifyFile(ctx, target, client, logger, id, entry, start)
+		if checked {
+			// A corrupted file keeps its last verified time, so that it is verified again on every run until it is fixed.
+			journal.RecordStatus(target.Journal, id, metrics.StatusOf(err), start)
+			changed = true
+		}
+		if err != nil {
+			logger.Errorf("Durability(%q): %v", bucketName, err)
+			if s := metrics.StatusOf(err); moreSevere(s, status) {
+				status = s
+			}
+			errs = append(errs, err)
+		}
+		if entry.GetChecksum() != "" {
+			age := start.Sub(time.Unix(entry.GetCreatedUnixSec(), 0))
+			sinceVerified := start.Sub(time.Unix(entry.GetLastVerifiedUnixSec(), 0))
+			target.LatencyMetrics.Durability[id] = metrics.NewDurabilityGauge(target.Target, id, age, sinceVerified)
+		}
+	}
+	if changed {
+		if err := journal.WriteAll(ctx, target, stores); err != nil {
+			logger.Errorf("Durability(%q): %v", bucketName, err)
+			if s := metrics.StatusOf(err); moreSevere(s, status) {
+				status = s
+			}
+			errs = append(errs, err)
+		}
+	}
+	target.LatencyMetrics.ProbeOpLatency[metrics.VerifyDurability][status].Metric(probeLatency).AddFloat64(time.Now().Sub(start).Seconds())
+	if len(errs) > 0 {
+		return metrics.NewProbeError(status, fmt.Errorf("Verify(%q) failed for %d files: %v", bucketName, len(errs), errs))
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package durability
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+	"github.com/googleinterns/step224-2020/hermes/probe/fakegcs"
+	"github.com/googleinterns/step224-2020/hermes/probe/journal"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/probetest"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+
+	journalpb "github.com/googleinterns/step224-2020/hermes/proto"
+)
+
+const (
+	bucketName = "test_bucket_durability"
+	contents   = "abc123"
+	// permanentID is the ID of a permanent file in the default file layout.
+	permanentID = int32(3)
+	// rotatingID is the ID of a rotating file in the default file layout.
+	rotatingID = int32(30)
+)
+
+// genTestTarget generates a target with the files given in its journal.
+func genTestTarget(t *testing.T, filenames map[int32]string) *target.Target {
+	t.Helper()
+	target := probetest.NewTarget(t, "durability_test", probetest.TargetConfig(bucketName))
+	for id, name := range filenames {
+		journal.AddFile(target.Journal, id, &journalpb.FileEntry{Filename: name})
+	}
+	return target
+}
+
+// record records the checksum and creation time of a file in its journal entry, as CreateFile does.
+func record(target *target.Target, fileID int32, sum string, created time.Time) *journalpb.FileEntry {
+	entry := target.Journal.Files[fileID]
+	entry.ChecksumAlgorithm = "sha1"
+	entry.Checksum = sum
+	entry.CreatedUnixSec = created.Unix()
+	entry.LastVerifiedUnixSec = created.Unix()
+	return entry
+}
+
+// writeFile writes a file with the contents given to the test bucket.
+func writeFile(ctx context.Context, t *testing.T, client stiface.Client, name, data string) {
+	t.Helper()
+	w := client.Bucket(bucketName).Object(name).NewWriter(ctx)
+	if _, err := w.Write([]byte(data)); err != nil {
+		t.Fatalf("failed to write file %q: %v", name, err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close file %q: %v", name, err)
+	}
+}
+
+// setUp creates the test bucket with a permanent and a rotating file and returns a target tracking them.
+func setUp(ctx context.Context, t *testing.T, client stiface.Client) *target.Target {
+	t.Helper()
+	if err := client.Bucket(bucketName).Create(ctx, bucketName, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	filenames := map[int32]string{
+		permanentID: fmt.Sprintf("Hermes_%02d_permanent", permanentID),
+		rotatingID:  fmt.Sprintf("Hermes_%02d_rotating", rotatingID),
+	}
+	for _, name := range filenames {
+		writeFile(ctx, t, client, name, contents)
+	}
+	return genTestTarget(t, filenames)
+}
+
+func TestVerifyRecordsUntrackedFiles(t *testing.T) {
+	ctx := context.Background()
+	client := fakegcs.NewClient()
+	target := setUp(ctx, t, client)
+	logger := fakegcs.NewLogger(ctx).Logger
+
+	if err := Verify(ctx, target, client, []journal.Store{journal.NewBucketStore(client)}, logger); err != nil {
+		t.Fatalf("Verify() failed: %v", err)
+	}
+	rec := target.Journal.Files[permanentID]
+	if want := fmt.Sprintf("%x", sha1.Sum([]byte(contents))); rec.GetChecksum() != want || rec.GetChecksumAlgorithm() != "sha1" {
+		t.Errorf("Verify() recorded %s checksum %s, want sha1 checksum %s", rec.GetChecksumAlgorithm(), rec.GetChecksum(), want)
+	}
+	if target.Journal.Files[rotatingID].GetChecksum() != "" {
+		t.Errorf("Verify() recorded rotating file %d, want only permanent files recorded", rotatingID)
+	}
+	if _, ok := target.LatencyMetrics.Durability[permanentID]; !ok {
+		t.Errorf("Verify() did not export the durability gauges of file %d", permanentID)
+	}
+	saved, err := journal.Read(ctx, target, client)
+	if err != nil {
+		t.Fatalf("journal.Read() after Verify() failed: %v", err)
+	}
+	if !proto.Equal(saved.GetFiles()[permanentID], rec) {
+		t.Errorf("Verify() wrote journal entry %v, want %v", saved.GetFiles()[permanentID], rec)
+	}
+}
+
+func TestVerifyCorruption(t *testing.T) {
+	tests := []struct {
+		desc       string
+		age        time.Duration
+		verified   time.Duration
+		wantStatus metrics.ExitStatus
+	}{
+		{"verified recently", 100 * day, time.Hour, metrics.Success},
+		{"young file", 2 * day, 2 * day, metrics.FileCorrupted},
+		{"old file", 31 * day, 2 * day, metrics.DurableFileCorrupted},
+	}
+	for _, tc := range tests {
+		ctx := context.Background()
+		client := fakegcs.NewClient()
+		target := setUp(ctx, t, client)
+		logger := fakegcs.NewLogger(ctx).Logger
+
+		now := time.Now()
+		record(target, permanentID, "a checksum that does not match", now.Add(-tc.age)).LastVerifiedUnixSec = now.Add(-tc.verified).Unix()
+
+		err := Verify(ctx, target, client, []journal.Store{journal.NewBucketStore(client)}, logger)
+		if got := metrics.StatusOf(err); got != tc.wantStatus {
+			t.Errorf("%s: Verify() = %v, want status %q", tc.desc, err, metrics.ExitStatusName[tc.wantStatus])
+		}
+		if got := target.Journal.Files[permanentID].GetLastVerifiedUnixSec(); got != now.Add(-tc.verified).Unix() {
+			t.Errorf("%s: Verify() updated the last verification time of file %d", tc.desc, permanentID)
+		}
+		wantFailures := int32(1)
+		if tc.wantStatus == metrics.Success {
+			wantFailures = 0
+		}
+		if got := target.Journal.Files[permanentID].GetConsecutiveFailures(); got != wantFailures {
+			t.Errorf("%s: Verify() recorded %d consecutive failures of file %d, want %d", tc.desc, got, permanentID, wantFailures)
+		}
+	}
+}
+
+func TestVerifyUpdatesLastVerified(t *testing.T) {
+	ctx := context.Background()
+	client := fakegcs.NewClient()
+	target := setUp(ctx, t, client)
+	logger := fakegcs.NewLogger(ctx).Logger
+
+	sum := sha1.Sum([]byte(contents))
+	created := time.Now().Add(-40 * day)
+	record(target, permanentID, fmt.Sprintf("%x", sum), created)
+	if err := Verify(ctx, target, client, []journal.Store{journal.NewBucketStore(client)}, logger); err != nil {
+		t.Fatalf("Verify() failed: %v", err)
+	}
+	rec := target.Journal.Files[permanentID]
+	if got := time.Since(time.Unix(rec.GetLastVerifiedUnixSec(), 0)); got > time.Minute {
+		t.Errorf("Verify() left the last verification of file %d %v ago, want it updated", permanentID, got)
+	}
+	if rec.GetLastStatus() != metrics.ExitStatusName[metrics.Success] {
+		t.Errorf("Verify() recorded status %q of file %d, want %q", rec.GetLastStatus(), permanentID, metrics.ExitStatusName[metrics.Success])
+	}
+	if rec.GetCreatedUnixSec() != created.Unix() {
+		t.Errorf("Verify() changed the creation time of file %d to %d, want %d", permanentID, rec.GetCreatedUnixSec(), created.Unix())
+	}
+}
+
+func TestVerifyChecksumInFileName(t *testing.T) {
+	sum := fmt.Sprintf("%x", sha1.Sum([]byte(contents)))
+	tests := []struct {
+		desc       string
+		nameSum    string
+		wantStatus metrics.ExitStatus
+	}{
+		{"matching checksum", sum, metrics.Success},
+		{"corrupted contents", fmt.Sprintf("%x", sha1.Sum([]byte("other"))), metrics.FileCorrupted},
+	}
+	for _, tc := range tests {
+		ctx := context.Background()
+		client := fakegcs.NewClient()
+		if err := client.Bucket(bucketName).Create(ctx, bucketName, nil); err != nil {
+			t.Fatalf("failed to create fake bucket: %v", err)
+		}
+		name := fmt.Sprintf("Hermes_%02d_%016x_sha1_%s", permanentID, 42, tc.nameSum)
+		writeFile(ctx, t, client, name, contents)
+		target := genTestTarget(t, map[int32]string{permanentID: name})
+		logger := fakegcs.NewLogger(ctx).Logger
+
+		err := Verify(ctx, target, client, []journal.Store{journal.NewBucketStore(client)}, logger)
+		if got := metrics.StatusOf(err); got != tc.wantStatus {
+			t.Errorf("%s: Verify() = %q, %v, want %q", tc.desc, metrics.ExitStatusName[got], err, metrics.ExitStatusName[tc.wantStatus])
+		}
+		// The checksum in the name is recorded, so that a corrupted file is not recorded as correct.
+		if rec := target.Journal.Files[permanentID]; rec.GetChecksum() != tc.nameSum || rec.GetChecksumAlgorithm() != "sha1" {
+			t.Errorf("%s: Verify() recorded %s checksum %s, want sha1 checksum %s", tc.desc, rec.GetChecksumAlgorithm(), rec.GetChecksum(), tc.nameSum)
+		}
+	}
+}
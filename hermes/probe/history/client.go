@@ -0,0 +1,98 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Client implements a storage client that records the names of the objects a probe run accesses.
+
+package history
+
+import (
+	"sort"
+	"sync"
+
+	"cloud.google.com/go/storage"
+	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+)
+
+// Files records the names of the objects accessed during a probe run. It is safe for concurrent use.
+type Files struct {
+	mu    sync.Mutex
+	names map[string]bool
+}
+
+// Reset forgets the objects recorded, at the start of a run.
+func (f *Files) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.names = nil
+}
+
+func (f *Files) add(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.names == nil {
+		f.names = make(map[string]bool)
+	}
+	f.names[name] = true
+}
+
+// Names returns the names of the objects recorded since the last reset, sorted.
+func (f *Files) Names() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var names []string
+	for name := range f.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewClient returns a storage client that records the name of every object accessed through it in files.
+// Arguments:
+//	- client: the storage client making the API calls.
+//	- files: records the names of the objects accessed.
+// Returns:
+//	- client: returns the recording client.
+func NewClient(client stiface.Client, files *Files) stiface.Client {
+	return &filesClient{Client: client, files: files}
+}
+
+type filesClient struct {
+	stiface.Client
+	files *Files
+}
+
+func (c *filesClient) Bucket(name string) stiface.BucketHandle {
+	return &filesBucket{BucketHandle: c.Client.Bucket(name), files: c.files}
+}
+
+type filesBucket struct {
+	stiface.BucketHandle
+	files *Files
+}
+
+func (b *filesBucket) If(conds storage.BucketConditions) stiface.BucketHandle {
+	return &filesBucket{BucketHandle: b.BucketHandle.If(conds), files: b.files}
+}
+
+func (b *filesBucket) UserProject(projectID string) stiface.BucketHandle {
+	return &filesBucket{BucketHandle: b.BucketHandle.UserProject(projectID), files: b.files}
+}
+
+// Object records the name of the object and returns the handle of the underlying client,
+// so that the handle can be passed back to that client, e.g. as the source of a copy.
+func (b *filesBucket) Object(name string) stiface.ObjectHandle {
+	b.files.add(name)
+	return b.BucketHandle.Object(name)
+}
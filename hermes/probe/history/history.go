@@ -0,0 +1,227 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package history implements the run history of a probe, a local store of the
+// result of every probe run of its targets, which is queried by target and time range.
+package history
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+const (
+	defaultRetention = 7 * 24 * time.Hour
+
+	// dayFormat names the file holding the runs of a target that started on a day, in UTC.
+	dayFormat  = "2006-01-02"
+	fileSuffix = ".runs"
+	day        = 24 * time.Hour
+)
+
+// Store stores the runs of every target in a directory per target, holding a file per day
+// of length-delimited RunRecords. Runs are removed a day at a time once they are older than
+// the retention. It is safe for concurrent use.
+type Store struct {
+	dir       string
+	retention time.Duration
+	mu        sync.Mutex
+}
+
+// New creates the run history store of the config given.
+// Arguments:
+//	- conf: the history config of the probe.
+// Returns:
+//	- store: returns the store, or nil if conf is nil.
+//	- err: returns an error if no directory is given or the retention is negative.
+func New(conf *probepb.HistoryConfig) (*Store, error) {
+	if conf == nil {
+		return nil, nil
+	}
+	if conf.GetDir() == "" {
+		return nil, fmt.Errorf("invalid history config: no dir given")
+	}
+	if conf.GetRetentionSec() < 0 {
+		return nil, fmt.Errorf("invalid history config: retention_sec = %d; want a non-negative value", conf.GetRetentionSec())
+	}
+	s := &Store{dir: conf.GetDir(), retention: defaultRetention}
+	if sec := conf.GetRetentionSec(); sec > 0 {
+		s.retention = time.Duration(sec) * time.Second
+	}
+	return s, nil
+}
+
+// targetDir returns the directory of the runs of a target.
+func (s *Store) targetDir(target *probepb.Target) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s_%s", target.GetName(), target.GetBucketName()))
+}
+
+// Append records a run of a target, then removes the runs of the target that are older than the retention.
+// Arguments:
+//	- run: the run to record.
+// Returns:
+//	- err: returns an error if the run could not be written or the runs could not be removed.
+func (s *Store) Append(run *probepb.RunRecord) error {
+	data, err := proto.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("could not marshal run: %w", err)
+	}
+	record := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(data))
+	record = append(record[:binary.PutUvarint(record, uint64(len(data)))], data...)
+
+	dir := s.targetDir(run.GetTarget())
+	start := time.Unix(0, run.GetStartUnixMs()*int64(time.Millisecond)).UTC()
+	path := filepath.Join(dir, start.Format(dayFormat)+fileSuffix)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("could not create history directory %q: %w", dir, err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("could not open history file %q: %w", path, err)
+	}
+	if _, err := f.Write(record); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write history file %q: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("could not close history file %q: %w", path, err)
+	}
+	return s.prune(dir, time.Now())
+}
+
+// days returns the days of the files in the directory of a target, oldest first.
+func days(dir string) ([]time.Time, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not list history directory %q: %w", dir, err)
+	}
+	var found []time.Time
+	for _, info := range infos {
+		name := info.Name()
+		if !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		d, err := time.Parse(dayFormat, strings.TrimSuffix(name, fileSuffix))
+		if err != nil {
+			continue
+		}
+		found = append(found, d)
+	}
+	// Files are listed by name, which sorts them by day.
+	return found, nil
+}
+
+// prune removes the files of a target whose every run is older than the retention at the time given.
+func (s *Store) prune(dir string, now time.Time) error {
+	found, err := days(dir)
+	if err != nil {
+		return err
+	}
+	for _, d := range found {
+		if d.Add(day).After(now.Add(-s.retention)) {
+			break
+		}
+		path := filepath.Join(dir, d.Format(dayFormat)+fileSuffix)
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("could not remove history file %q: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// read reads the runs of a history file. A record truncated at the end of the file, left
+// by a write that was interrupted, is ignored.
+func read(path string) ([]*probepb.RunRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open history file %q: %w", path, err)
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	var runs []*probepb.RunRecord
+	for {
+		size, err := binary.ReadUvarint(r)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return runs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read history file %q: %w", path, err)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err == io.EOF || err == io.ErrUnexpectedEOF {
+			return runs, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("could not read history file %q: %w", path, err)
+		}
+		run := &probepb.RunRecord{}
+		if err := proto.Unmarshal(data, run); err != nil {
+			return nil, fmt.Errorf("could not parse history file %q: %w", path, err)
+		}
+		runs = append(runs, run)
+	}
+}
+
+// Query returns the runs of a target that started within [start, end), ordered by start time.
+// Arguments:
+//	- target: the target, identified by its name and bucket name.
+//	- start: the start of the time range.
+//	- end: the end of the time range.
+// Returns:
+//	- runs: returns the runs of the target that started within the time range.
+//	- err: returns an error if a history file could not be read.
+func (s *Store) Query(target *probepb.Target, start, end time.Time) ([]*probepb.RunRecord, error) {
+	dir := s.targetDir(target)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	found, err := days(dir)
+	if err != nil {
+		return nil, err
+	}
+	var runs []*probepb.RunRecord
+	for _, d := range found {
+		if !d.Add(day).After(start) || !d.Before(end) {
+			continue
+		}
+		dayRuns, err := read(filepath.Join(dir, d.Format(dayFormat)+fileSuffix))
+		if err != nil {
+			return nil, err
+		}
+		for _, run := range dayRuns {
+			t := time.Unix(0, run.GetStartUnixMs()*int64(time.Millisecond))
+			if !t.Before(start) && t.Before(end) {
+				runs = append(runs, run)
+			}
+		}
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].GetStartUnixMs() < runs[j].GetStartUnixMs() })
+	return runs, nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/googleinterns/step224-2020/hermes/probe/fakegcs"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+// tempDir creates a temporary directory which is removed at the end of the test.
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "history_test")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir() failed: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+var testTarget = &probepb.Target{
+	Name:         "hermes",
+	TargetSystem: probepb.Target_GOOGLE_CLOUD_STORAGE,
+	BucketName:   "test_bucket_history",
+}
+
+func genRun(start time.Time, status string) *probepb.RunRecord {
+	return &probepb.RunRecord{
+		Target:      testTarget,
+		StartUnixMs: start.UnixNano() / int64(time.Millisecond),
+		EndUnixMs:   start.Add(time.Second).UnixNano() / int64(time.Millisecond),
+		Status:      status,
+		Operations:  []*probepb.RunProbeResult{{Operation: "check_nil", Status: status, LatencySec: 0.5}},
+		Files:       []string{"Hermes_01_a", "Hermes_Nil"},
+	}
+}
+
+// statuses returns the status of each run.
+func statuses(runs []*probepb.RunRecord) string {
+	var s []string
+	for _, run := range runs {
+		s = append(s, run.GetStatus())
+	}
+	return fmt.Sprint(s)
+}
+
+func TestNew(t *testing.T) {
+	if s, err := New(nil); s != nil || err != nil {
+		t.Errorf("New(nil) = %v, %v, want nil, nil", s, err)
+	}
+	for _, conf := range []*probepb.HistoryConfig{{}, {Dir: proto.String("/tmp"), RetentionSec: proto.Int64(-1)}} {
+		if _, err := New(conf); err == nil {
+			t.Errorf("New(%v) succeeded, want an error", conf)
+		}
+	}
+}
+
+func TestAppendQuery(t *testing.T) {
+	s, err := New(&probepb.HistoryConfig{Dir: proto.String(tempDir(t))})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	now := time.Now().Truncate(time.Millisecond)
+	if runs, err := s.Query(testTarget, now.Add(-time.Hour), now); err != nil || len(runs) != 0 {
+		t.Errorf("Query() of an empty history = %v, %v, want no runs", runs, err)
+	}
+
+	// The runs span three days, so they are stored in three files.
+	for _, run := range []*probepb.RunRecord{
+		genRun(now.Add(-50*time.Hour), "file_missing"),
+		genRun(now.Add(-2*time.Hour), "success"),
+		genRun(now.Add(-26*time.Hour), "probe_failed"),
+		genRun(now.Add(-time.Hour), "file_corrupted"),
+	} {
+		if err := s.Append(run); err != nil {
+			t.Fatalf("Append() failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		desc  string
+		start time.Time
+		end   time.Time
+		want  string
+	}{
+		{desc: "every run", start: now.Add(-72 * time.Hour), end: now, want: "[file_missing probe_failed success file_corrupted]"},
+		{desc: "last day", start: now.Add(-24 * time.Hour), end: now, want: "[success file_corrupted]"},
+		{desc: "range across days", start: now.Add(-51 * time.Hour), end: now.Add(-90 * time.Minute), want: "[file_missing probe_failed success]"},
+		{desc: "end excluded", start: now.Add(-72 * time.Hour), end: now.Add(-50 * time.Hour), want: "[]"},
+	}
+	for _, tc := range tests {
+		runs, err := s.Query(testTarget, tc.start, tc.end)
+		if err != nil {
+			t.Fatalf("%s: Query() failed: %v", tc.desc, err)
+		}
+		if got := statuses(runs); got != tc.want {
+			t.Errorf("%s: Query() = %s, want %s", tc.desc, got, tc.want)
+		}
+	}
+
+	runs, err := s.Query(testTarget, now.Add(-time.Hour), now)
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("Query() = %v, %v, want one run", runs, err)
+	}
+	if want := genRun(now.Add(-time.Hour), "file_corrupted"); !proto.Equal(runs[0], want) {
+		t.Errorf("Query() = %v, want %v", runs[0], want)
+	}
+	other := proto.Clone(testTarget).(*probepb.Target)
+	other.BucketName = "test_bucket_history_2"
+	if runs, err := s.Query(other, now.Add(-72*time.Hour), now); err != nil || len(runs) != 0 {
+		t.Errorf("Query() of another target = %v, %v, want no runs", runs, err)
+	}
+}
+
+func TestRetention(t *testing.T) {
+	dir := tempDir(t)
+	s, err := New(&probepb.HistoryConfig{Dir: proto.String(dir), RetentionSec: proto.Int64(2 * 86400)})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	now := time.Now().Truncate(time.Millisecond)
+	for _, run := range []*probepb.RunRecord{
+		genRun(now.Add(-10*24*time.Hour), "file_missing"),
+		genRun(now.Add(-5*24*time.Hour), "probe_failed"),
+		genRun(now, "success"),
+	} {
+		if err := s.Append(run); err != nil {
+			t.Fatalf("Append() failed: %v", err)
+		}
+	}
+	runs, err := s.Query(testTarget, now.Add(-30*24*time.Hour), now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Query() failed: %v", err)
+	}
+	if got, want := statuses(runs), "[success]"; got != want {
+		t.Errorf("Query() after the retention = %s, want %s", got, want)
+	}
+	files, err := ioutil.ReadDir(s.targetDir(testTarget))
+	if err != nil {
+		t.Fatalf("failed to list history directory: %v", err)
+	}
+	if len(files) != 1 {
+		t.Errorf("Append() left %d history files, want the file of the last day only", len(files))
+	}
+}
+
+func TestQueryTruncated(t *testing.T) {
+	s, err := New(&probepb.HistoryConfig{Dir: proto.String(tempDir(t))})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	now := time.Now().Truncate(time.Millisecond)
+	for _, status := range []string{"success", "probe_failed"} {
+		if err := s.Append(genRun(now, status)); err != nil {
+			t.Fatalf("Append() failed: %v", err)
+		}
+	}
+	// Truncate the last record, as an interrupted write would.
+	path := filepath.Join(s.targetDir(testTarget), now.UTC().Format(dayFormat)+fileSuffix)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read history file: %v", err)
+	}
+	if err := ioutil.WriteFile(path, data[:len(data)-3], 0644); err != nil {
+		t.Fatalf("failed to write history file: %v", err)
+	}
+	runs, err := s.Query(testTarget, now.Add(-time.Minute), now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Query() of a truncated history file failed: %v", err)
+	}
+	if got, want := statuses(runs), "[success]"; got != want {
+		t.Errorf("Query() of a truncated history file = %s, want %s", got, want)
+	}
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	fake := fakegcs.NewClient()
+	bucket := testTarget.GetBucketName()
+	if err := fake.Bucket(bucket).Create(ctx, bucket, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	files := &Files{}
+	client := NewClient(fake, files)
+	for _, name := range []string{"Hermes_02_b", "Hermes_01_a", "Hermes_02_b"} {
+		client.Bucket(bucket).Object(name).Attrs(ctx)
+	}
+	if got, want := fmt.Sprint(files.Names()), "[Hermes_01_a Hermes_02_b]"; got != want {
+		t.Errorf("Names() = %s, want %s", got, want)
+	}
+	files.Reset()
+	if got := files.Names(); len(got) != 0 {
+		t.Errorf("Names() after Reset() = %v, want none", got)
+	}
+}
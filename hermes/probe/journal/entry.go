@@ -0,0 +1,120 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Entry implements the per-file history kept in the StateJournal.
+
+package journal
+
+import (
+	"strings"
+	"time"
+
+	"github.com/googleinterns/step224-2020/hermes/probe/checksum"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+	journalpb "github.com/googleinterns/step224-2020/hermes/proto"
+)
+
+// checksumAlgorithm returns the name of the checksum algorithm encoded in a filename,
+// Hermes_ID_seed_algorithm_checksum or Hermes_ID_algorithm_checksum.
+// Filenames created before the algorithm was encoded in them, Hermes_ID_checksum, use SHA1.
+func checksumAlgorithm(filename string) string {
+	parts := strings.Split(filename, "_")
+	switch len(parts) {
+	case 3:
+		return checksum.Name(probepb.Target_SHA1)
+	case 4, 5:
+		name := parts[len(parts)-2]
+		if _, err := checksum.Parse(name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// Migrate migrates a journal written before the files map was added.
+// It creates an entry in the files map for every file in the filenames map. Entries of files that
+// are no longer in the filenames map are removed.
+// Arguments:
+//	- j: the journal to be migrated.
+// Returns:
+//	- changed: returns whether the journal was changed, in which case it should be written back.
+func Migrate(j *journalpb.StateJournal) bool {
+	changed := false
+	if j.Files == nil {
+		j.Files = make(map[int32]*journalpb.FileEntry)
+	}
+	for id, name := range j.Filenames {
+		if entry, ok := j.Files[id]; ok && entry.GetFilename() == name {
+			continue
+		}
+		j.Files[id] = &journalpb.FileEntry{
+			Filename:          name,
+			ChecksumAlgorithm: checksumAlgorithm(name),
+		}
+		changed = true
+	}
+	for id := range j.Files {
+		if _, ok := j.Filenames[id]; !ok {
+			delete(j.Files, id)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// AddFile records a new file in the filenames and files maps of the journal.
+// Arguments:
+//	- j: the journal of the target the file was created on.
+//	- id: the ID of the file.
+//	- entry: the entry of the file, its last status is set to success.
+func AddFile(j *journalpb.StateJournal, id int32, entry *journalpb.FileEntry) {
+	if j.Filenames == nil {
+		j.Filenames = make(map[int32]string)
+	}
+	if j.Files == nil {
+		j.Files = make(map[int32]*journalpb.FileEntry)
+	}
+	entry.LastStatus = metrics.ExitStatusName[metrics.Success]
+	j.Filenames[id] = entry.GetFilename()
+	j.Files[id] = entry
+}
+
+// RemoveFile removes a file from the filenames and files maps of the journal.
+func RemoveFile(j *journalpb.StateJournal, id int32) {
+	delete(j.Filenames, id)
+	delete(j.Files, id)
+}
+
+// RecordStatus records the exit status of an operation that verified the contents of a file.
+// A successful operation updates the last verified time of the file and resets its consecutive failures.
+// Arguments:
+//	- j: the journal of the target the file is on.
+//	- id: the ID of the file.
+//	- status: the exit status of the operation.
+//	- now: the time at which the operation completed.
+func RecordStatus(j *journalpb.StateJournal, id int32, status metrics.ExitStatus, now time.Time) {
+	entry, ok := j.Files[id]
+	if !ok {
+		return
+	}
+	entry.LastStatus = metrics.ExitStatusName[status]
+	if status != metrics.Success {
+		entry.ConsecutiveFailures++
+		return
+	}
+	entry.ConsecutiveFailures = 0
+	entry.LastVerifiedUnixSec = now.Unix()
+}
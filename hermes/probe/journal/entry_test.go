@@ -0,0 +1,120 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/googleinterns/step224-2020/hermes/probe/fakegcs"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+
+	journalpb "github.com/googleinterns/step224-2020/hermes/proto"
+)
+
+// oldJournal returns a journal as written before the files map was added.
+func oldJournal() *journalpb.StateJournal {
+	return &journalpb.StateJournal{
+		Intent: &journalpb.Intent{},
+		Filenames: map[int32]string{
+			1: "Hermes_01_6367c48dd193d56ea7b0baad25b19455e529f5ee",
+			2: "Hermes_02_sha256_abcd",
+		},
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	j := oldJournal()
+	if !Migrate(j) {
+		t.Errorf("Migrate() of an old journal = false, want true")
+	}
+	want := map[int32]*journalpb.FileEntry{
+		1: {Filename: "Hermes_01_6367c48dd193d56ea7b0baad25b19455e529f5ee", ChecksumAlgorithm: "sha1"},
+		2: {Filename: "Hermes_02_sha256_abcd", ChecksumAlgorithm: "sha256"},
+	}
+	for id, entry := range want {
+		if !proto.Equal(j.Files[id], entry) {
+			t.Errorf("Migrate() entry of file %d = %v, want %v", id, j.Files[id], entry)
+		}
+	}
+	if Migrate(j) {
+		t.Errorf("Migrate() of a migrated journal = true, want false")
+	}
+
+	delete(j.Filenames, 2)
+	if !Migrate(j) {
+		t.Errorf("Migrate() of a journal with a stale entry = false, want true")
+	}
+	if _, ok := j.Files[2]; ok {
+		t.Errorf("Migrate() kept the entry of file 2, which is not in the filenames map")
+	}
+}
+
+func TestReconcileMigratesOldJournal(t *testing.T) {
+	ctx := context.Background()
+	logger := fakegcs.NewLogger(ctx).Logger
+	client := fakegcs.NewClient()
+	if err := client.Bucket(bucketName).Create(ctx, bucketName, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	stores := []Store{NewBucketStore(client), NewLocalStore(tempDir(t))}
+	for _, s := range stores {
+		if err := s.Write(ctx, genTestTarget(t, oldJournal())); err != nil {
+			t.Fatalf("%s.Write() failed: %v", s.Name(), err)
+		}
+	}
+	target := genTestTarget(t, nil)
+	got, err := Reconcile(ctx, target, stores, logger)
+	if err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	if len(got.Files) != 2 || got.Files[2].GetChecksumAlgorithm() != "sha256" {
+		t.Errorf("Reconcile() of an old journal = %v, want it migrated to the files map", got)
+	}
+	// The migrated journal is written back, so that it is not migrated again on every run.
+	for _, s := range stores {
+		if j, err := s.Read(ctx, target); err != nil || !proto.Equal(j, got) {
+			t.Errorf("%s.Read() after Reconcile() = %v, %v, want %v", s.Name(), j, err, got)
+		}
+	}
+}
+
+func TestRecordStatus(t *testing.T) {
+	j := &journalpb.StateJournal{}
+	AddFile(j, 4, &journalpb.FileEntry{Filename: "Hermes_04_a"})
+	if j.Filenames[4] != "Hermes_04_a" || j.Files[4].GetLastStatus() != "success" {
+		t.Fatalf("AddFile() = %v, want file 4 in the filenames and files maps with status success", j)
+	}
+
+	now := time.Unix(1000, 0)
+	RecordStatus(j, 4, metrics.FileCorrupted, now)
+	RecordStatus(j, 4, metrics.FileMissing, now)
+	if got := j.Files[4]; got.GetConsecutiveFailures() != 2 || got.GetLastStatus() != "file_missing" || got.GetLastVerifiedUnixSec() != 0 {
+		t.Errorf("RecordStatus() after two failures = %v, want 2 consecutive failures, status file_missing and no verification", got)
+	}
+	RecordStatus(j, 4, metrics.Success, now)
+	if got := j.Files[4]; got.GetConsecutiveFailures() != 0 || got.GetLastStatus() != "success" || got.GetLastVerifiedUnixSec() != 1000 {
+		t.Errorf("RecordStatus() after a success = %v, want no consecutive failures, status success and verified at 1000", got)
+	}
+	// Files that are not in the journal are ignored.
+	RecordStatus(j, 5, metrics.Success, now)
+
+	RemoveFile(j, 4)
+	if len(j.Filenames) != 0 || len(j.Files) != 0 {
+		t.Errorf("RemoveFile() = %v, want an empty journal", j)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package journal implements the storage of the StateJournal of a target in
+// the NIL file of its bucket and in optional local copies, see Store.
+package journal
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/golang/protobuf/proto"
+	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+
+	journalpb "github.com/googleinterns/step224-2020/hermes/proto"
+)
+
+const (
+	// NilFileName is the name of the NIL file, file ID 0, which stores the StateJournal of a target.
+	NilFileName = "Hermes_00_NIL"
+	// ContentType is the content type of the NIL file.
+	ContentType = "application/x-protobuf"
+	apiLatency  = "hermes_api_latency_seconds"
+)
+
+// statusOf returns the exit status of a failed API call on the NIL file.
+func statusOf(err error) metrics.ExitStatus {
+	switch err {
+	case storage.ErrObjectNotExist:
+		return metrics.FileMissing
+	case storage.ErrBucketNotExist:
+		return metrics.BucketMissing
+	default:
+		return metrics.APICallFailed
+	}
+}
+
+// Read reads the StateJournal stored in the NIL file of the target bucket.
+// The journal is returned as stored, journals written by older versions of Hermes are migrated by Reconcile.
+// Arguments:
+//	- ctx: the context of the probe run.
+//	- target: the target whose journal is read.
+//	- client: initialised storage client for this target system.
+// Returns:
+//	- journal: returns the StateJournal stored in the NIL file.
+//	- err: returns a *metrics.ProbeError with status:
+//		- FileMissing: the NIL file does not exist.
+//		- BucketMissing: the target bucket does not exist.
+//		- FileCorrupted: the NIL file could not be parsed.
+//		- APICallFailed: the NIL file could not be read.
+func Read(ctx context.Context, target *target.Target, client stiface.Client) (*journalpb.StateJournal, error) {
+	// TODO(#77): Refactor timing into using function from metrics.go
+	start := time.Now()
+	record := func(status metrics.ExitStatus) {
+		target.LatencyMetrics.APICallLatency[metrics.APIReadJournal][status].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+	}
+	reader, err := client.Bucket(target.Target.GetBucketName()).Object(NilFileName).NewReader(ctx)
+	if err != nil {
+		status := statusOf(err)
+		record(status)
+		return nil, metrics.NewProbeError(status, fmt.Errorf("could not open NIL file %q: %w", NilFileName, err))
+	}
+	defer reader.Close()
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		record(metrics.FileReadFailure)
+		return nil, metrics.NewProbeError(metrics.FileReadFailure, fmt.Errorf("could not read NIL file %q: %w", NilFileName, err))
+	}
+	record(metrics.Success)
+
+	journal := &journalpb.StateJournal{}
+	if err := proto.Unmarshal(data, journal); err != nil {
+		return nil, metrics.NewProbeError(metrics.FileCorrupted, fmt.Errorf("could not parse NIL file %q: %w", NilFileName, err))
+	}
+	if journal.Filenames == nil {
+		journal.Filenames = make(map[int32]string)
+	}
+	return journal, nil
+}
+
+// Write writes the StateJournal of the target to the NIL file of the target bucket, replacing its previous contents.
+// Arguments:
+//	- ctx: the context of the probe run.
+//	- target: the target whose journal is written.
+//	- client: initialised storage client for this target system.
+// Returns:
+//	- err: returns a *metrics.ProbeError if the journal could not be written.
+func Write(ctx context.Context, target *target.Target, client stiface.Client) error {
+	data, err := proto.Marshal(target.Journal)
+	if err != nil {
+		return metrics.NewProbeError(metrics.ProbeFailed, fmt.Errorf("could not marshal journal: %w", err))
+	}
+	// TODO(#77): Refactor timing into using function from metrics.go
+	start := time.Now()
+	record := func(status metrics.ExitStatus) {
+		target.LatencyMetrics.APICallLatency[metrics.APIWriteJournal][status].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+	}
+	wc := client.Bucket(target.Target.GetBucketName()).Object(NilFileName).NewWriter(ctx)
+	wc.ObjectAttrs().ContentType = ContentType
+	if _, err := wc.Write(data); err != nil {
+		status := statusOf(err)
+		record(status)
+		wc.Close()
+		return metrics.NewProbeError(status, fmt.Errorf("could not write NIL file %q: %w", NilFileName, err))
+	}
+	if err := wc.Close(); err != nil {
+		status := metrics.WriterCloseFailed
+		if err == storage.ErrBucketNotExist {
+			status = metrics.BucketMissing
+		}
+		record(status)
+		return metrics.NewProbeError(status, fmt.Errorf("could not write NIL file %q: %w", NilFileName, err))
+	}
+	record(metrics.Success)
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/googleinterns/step224-2020/hermes/probe/fakegcs"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/probetest"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+
+	journalpb "github.com/googleinterns/step224-2020/hermes/proto"
+)
+
+const bucketName = "test_bucket_journal"
+
+// genTestTarget generates a target with the journal given.
+func genTestTarget(t *testing.T, journal *journalpb.StateJournal) *target.Target {
+	t.Helper()
+	target := probetest.NewTarget(t, "journal_test", probetest.TargetConfig(bucketName))
+	target.Journal = journal
+	return target
+}
+
+func TestWriteRead(t *testing.T) {
+	ctx := context.Background()
+	client := fakegcs.NewClient()
+	if err := client.Bucket(bucketName).Create(ctx, bucketName, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	want := &journalpb.StateJournal{
+		Intent:    &journalpb.Intent{FileOperation: journalpb.Intent_CREATE, Filename: "Hermes_02_b"},
+		Filenames: map[int32]string{1: "Hermes_01_a", 2: "Hermes_02_b"},
+		Files: map[int32]*journalpb.FileEntry{
+			1: {Filename: "Hermes_01_a", Seed: 11, CreatedUnixSec: 100, Generation: 3, SizeBytes: 1000, LastStatus: "success"},
+			2: {Filename: "Hermes_02_b", Seed: 22, LastStatus: "file_corrupted", ConsecutiveFailures: 2},
+		},
+	}
+	if err := Write(ctx, genTestTarget(t, want), client); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	got, err := Read(ctx, genTestTarget(t, nil), client)
+	if err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("Read() = %v, want %v", got, want)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	ctx := context.Background()
+	client := fakegcs.NewClient()
+	target := genTestTarget(t, nil)
+
+	if _, err := Read(ctx, target, client); metrics.StatusOf(err) != metrics.BucketMissing {
+		t.Errorf("Read() without a bucket = %v, want status %q", err, metrics.ExitStatusName[metrics.BucketMissing])
+	}
+	if err := client.Bucket(bucketName).Create(ctx, bucketName, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	if _, err := Read(ctx, target, client); metrics.StatusOf(err) != metrics.FileMissing {
+		t.Errorf("Read() without a NIL file = %v, want status %q", err, metrics.ExitStatusName[metrics.FileMissing])
+	}
+
+	w := client.Bucket(bucketName).Object(NilFileName).NewWriter(ctx)
+	if _, err := w.Write([]byte("not a journal")); err != nil {
+		t.Fatalf("failed to write NIL file: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close NIL file: %v", err)
+	}
+	if _, err := Read(ctx, target, client); metrics.StatusOf(err) != metrics.FileCorrupted {
+		t.Errorf("Read() of a corrupted NIL file = %v, want status %q", err, metrics.ExitStatusName[metrics.FileCorrupted])
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Reconcile implements writing the journal to several stores and reconciling their copies.
+
+package journal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/cloudprober/logger"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+
+	journalpb "github.com/googleinterns/step224-2020/hermes/proto"
+)
+
+const probeLatency = "hermes_probe_latency_seconds"
+
+// WriteAll increments the sequence number of the journal of the target and writes it to every store.
+// Every store is written even if writing to a previous store failed.
+// Arguments:
+//	- ctx: the context of the probe run.
+//	- target: the target whose journal is written.
+//	- stores: the stores the journal is written to.
+// Returns:
+//	- err: returns a *metrics.ProbeError with the status of the first failed write if any store could not be written.
+func WriteAll(ctx context.Context, target *target.Target, stores []Store) error {
+	target.Journal.Sequence++
+	var errs []error
+	status := metrics.Success
+	for _, s := range stores {
+		if err := s.Write(ctx, target); err != nil {
+			if status == metrics.Success {
+				status = metrics.StatusOf(err)
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", s.Name(), err))
+		}
+	}
+	if len(errs) > 0 {
+		return metrics.NewProbeError(status, fmt.Errorf("WriteAll(%q) failed for %d of %d stores: %v", target.Target.GetBucketName(), len(errs), len(stores), errs))
+	}
+	return nil
+}
+
+// Reconcile reads the journal of the target from every store and returns the latest copy, the one with
+// the highest sequence number. Ties are broken by the order of the stores. If a store is missing the
+// journal, holds a corrupted copy or a copy that differs from the latest one, the mismatch is logged
+// and reported in the reconcile_journal metric, and the latest copy is written to that store.
+// A latest copy written by an older version of Hermes is migrated, see Migrate, and written to every store.
+// Arguments:
+//	- ctx: the context of the probe run.
+//	- target: the target whose journal is reconciled.
+//	- stores: the stores holding copies of the journal.
+//	- logger: logger associated with the probe calling this function.
+// Returns:
+//	- journal: returns the latest copy of the journal.
+//	- err: returns a *metrics.ProbeError with status:
+//		- FileMissing: no store holds a journal for the target.
+//		- the status of the first failed read if no store could be read.
+//	  Failing to repair a store is logged but not returned.
+func Reconcile(ctx context.Context, target *target.Target, stores []Store, logger *logger.Logger) (*journalpb.StateJournal, error) {
+	bucketName := target.Target.GetBucketName()
+	// TODO(#77): Refactor timing into using function from metrics.go
+	start := time.Now()
+
+	copies := make([]*journalpb.StateJournal, len(stores))
+	errs := make([]error, len(stores))
+	var latest *journalpb.StateJournal
+	missing := 0
+	for i, s := range stores {
+		copies[i], errs[i] = s.Read(ctx, target)
+		switch {
+		case errs[i] == nil:
+			if latest == nil || copies[i].GetSequence() > latest.GetSequence() {
+				latest = copies[i]
+			}
+		case metrics.StatusOf(errs[i]) == metrics.FileMissing:
+			missing++
+		default:
+			logger.Warningf("Reconcile(%q): could not read journal from %s: %v", bucketName, s.Name(), errs[i])
+		}
+	}
+	if latest == nil {
+		if missing == len(stores) {
+			return nil, metrics.NewProbeError(metrics.FileMissing, fmt.Errorf("Reconcile(%q) failed: no store holds a journal", bucketName))
+		}
+		for _, err := range errs {
+			if err != nil && metrics.StatusOf(err) != metrics.FileMissing {
+				return nil, fmt.Errorf("Reconcile(%q) failed: %w", bucketName, err)
+			}
+		}
+	}
+
+	var stale []Store
+	for i, s := range stores {
+		if errs[i] != nil || !proto.Equal(copies[i], latest) {
+			logger.Warningf("Reconcile(%q): journal in %s does not match the latest journal, sequence %d: %v", bucketName, s.Name(), latest.GetSequence(), describe(copies[i], errs[i]))
+			stale = append(stale, s)
+		}
+	}
+	status := metrics.Success
+	if len(stale) > 0 {
+		status = metrics.JournalMismatch
+	}
+	if Migrate(latest) {
+		logger.Infof("Reconcile(%q): migrated journal, sequence %d, writing it to every store.", bucketName, latest.GetSequence())
+		stale = stores
+	}
+	if len(stale) > 0 {
+		// The stores write the journal of the target they are given, so the latest copy is written via a shallow copy of the target.
+		repaired := *target
+		repaired.Journal = latest
+		for _, s := range stale {
+			if err := s.Write(ctx, &repaired); err != nil {
+				logger.Errorf("Reconcile(%q): could not repair journal in %s: %v", bucketName, s.Name(), err)
+			}
+		}
+	}
+	target.LatencyMetrics.ProbeOpLatency[metrics.ReconcileJournal][status].Metric(probeLatency).AddFloat64(time.Now().Sub(start).Seconds())
+	return latest, nil
+}
+
+// describe returns a description of a copy of the journal read from a store for logs.
+func describe(j *journalpb.StateJournal, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("sequence %d with %d files", j.GetSequence(), len(j.GetFilenames()))
+}
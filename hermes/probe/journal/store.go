@@ -0,0 +1,161 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Store implements the locations the StateJournal of a target can be stored in.
+
+package journal
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+
+	journalpb "github.com/googleinterns/step224-2020/hermes/proto"
+)
+
+// Store is a location the StateJournal of a target is stored in.
+type Store interface {
+	// Name returns the name of the store used in logs.
+	Name() string
+	// Read reads the journal of the target from the store, as it is stored.
+	// It returns a *metrics.ProbeError with status FileMissing if the store holds no journal for the target.
+	Read(ctx context.Context, target *target.Target) (*journalpb.StateJournal, error)
+	// Write writes the journal of the target to the store, replacing its previous contents.
+	Write(ctx context.Context, target *target.Target) error
+}
+
+// BucketStore stores the journal of a target in the NIL file of the target bucket.
+type BucketStore struct {
+	client stiface.Client
+}
+
+// NewBucketStore returns a store for the NIL files of the buckets accessed with the client given.
+func NewBucketStore(client stiface.Client) *BucketStore {
+	return &BucketStore{client: client}
+}
+
+// Name returns the name of the store used in logs.
+func (s *BucketStore) Name() string {
+	return "bucket"
+}
+
+// Read reads the journal from the NIL file of the target bucket, see Read.
+func (s *BucketStore) Read(ctx context.Context, target *target.Target) (*journalpb.StateJournal, error) {
+	return Read(ctx, target, s.client)
+}
+
+// Write writes the journal to the NIL file of the target bucket, see Write.
+func (s *BucketStore) Write(ctx context.Context, target *target.Target) error {
+	return Write(ctx, target, s.client)
+}
+
+// LocalStore stores the journal of each target in a file in a local directory.
+// Each file holds the SHA-256 checksum of the journal followed by the journal,
+// so that a partially written or corrupted file is detected when it is read.
+// Files are written to a temporary file which is then renamed, so that a crash
+// during a write leaves the previous journal in place.
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore returns a store for the journals of the targets in the directory given.
+// The directory is created when a journal is first written.
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{dir: dir}
+}
+
+// Name returns the name of the store used in logs.
+func (s *LocalStore) Name() string {
+	return "local:" + s.dir
+}
+
+// path returns the path of the journal file of the target.
+func (s *LocalStore) path(target *target.Target) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s_%s.journal", target.Target.GetName(), target.Target.GetBucketName()))
+}
+
+// Read reads the journal of the target from its file and verifies its checksum.
+// Returns a *metrics.ProbeError with status:
+//	- FileMissing: the journal file does not exist.
+//	- FileCorrupted: the checksum of the journal file does not match or the journal could not be parsed.
+//	- FileReadFailure: the journal file could not be read.
+func (s *LocalStore) Read(ctx context.Context, target *target.Target) (*journalpb.StateJournal, error) {
+	path := s.path(target)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, metrics.NewProbeError(metrics.FileMissing, fmt.Errorf("journal file %q does not exist", path))
+	}
+	if err != nil {
+		return nil, metrics.NewProbeError(metrics.FileReadFailure, fmt.Errorf("could not read journal file %q: %w", path, err))
+	}
+	if len(data) < sha256.Size {
+		return nil, metrics.NewProbeError(metrics.FileCorrupted, fmt.Errorf("journal file %q is truncated: %d bytes", path, len(data)))
+	}
+	sum, data := data[:sha256.Size], data[sha256.Size:]
+	if got := sha256.Sum256(data); !bytes.Equal(got[:], sum) {
+		return nil, metrics.NewProbeError(metrics.FileCorrupted, fmt.Errorf("journal file %q has checksum %x, want %x", path, got, sum))
+	}
+	journal := &journalpb.StateJournal{}
+	if err := proto.Unmarshal(data, journal); err != nil {
+		return nil, metrics.NewProbeError(metrics.FileCorrupted, fmt.Errorf("could not parse journal file %q: %w", path, err))
+	}
+	if journal.Filenames == nil {
+		journal.Filenames = make(map[int32]string)
+	}
+	return journal, nil
+}
+
+// Write writes the journal of the target and its checksum to a temporary file and renames it to the journal file.
+func (s *LocalStore) Write(ctx context.Context, target *target.Target) error {
+	data, err := proto.Marshal(target.Journal)
+	if err != nil {
+		return metrics.NewProbeError(metrics.ProbeFailed, fmt.Errorf("could not marshal journal: %w", err))
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return metrics.NewProbeError(metrics.ProbeFailed, fmt.Errorf("could not create journal directory %q: %w", s.dir, err))
+	}
+	path := s.path(target)
+	tmp, err := ioutil.TempFile(s.dir, filepath.Base(path)+".tmp")
+	if err != nil {
+		return metrics.NewProbeError(metrics.ProbeFailed, fmt.Errorf("could not create temporary journal file: %w", err))
+	}
+	// Removing the temporary file fails once it has been renamed, which is expected.
+	defer os.Remove(tmp.Name())
+
+	sum := sha256.Sum256(data)
+	if _, err := tmp.Write(append(sum[:], data...)); err != nil {
+		tmp.Close()
+		return metrics.NewProbeError(metrics.ProbeFailed, fmt.Errorf("could not write temporary journal file %q: %w", tmp.Name(), err))
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return metrics.NewProbeError(metrics.ProbeFailed, fmt.Errorf("could not sync temporary journal file %q: %w", tmp.Name(), err))
+	}
+	if err := tmp.Close(); err != nil {
+		return metrics.NewProbeError(metrics.WriterCloseFailed, fmt.Errorf("could not close temporary journal file %q: %w", tmp.Name(), err))
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return metrics.NewProbeError(metrics.ProbeFailed, fmt.Errorf("could not rename %q to %q: %w", tmp.Name(), path, err))
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journal
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/googleinterns/step224-2020/hermes/probe/fakegcs"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+
+	journalpb "github.com/googleinterns/step224-2020/hermes/proto"
+)
+
+// tempDir creates a temporary directory which is removed at the end of the test.
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "journal_test")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir() failed: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func TestLocalStoreWriteRead(t *testing.T) {
+	ctx := context.Background()
+	store := NewLocalStore(tempDir(t))
+	target := genTestTarget(t, nil)
+	if _, err := store.Read(ctx, target); metrics.StatusOf(err) != metrics.FileMissing {
+		t.Errorf("LocalStore.Read() without a journal file = %v, want status %q", err, metrics.ExitStatusName[metrics.FileMissing])
+	}
+
+	want := &journalpb.StateJournal{
+		Intent:    &journalpb.Intent{FileOperation: journalpb.Intent_DELETE, Filename: "Hermes_02_b"},
+		Filenames: map[int32]string{1: "Hermes_01_a"},
+		Files:     map[int32]*journalpb.FileEntry{1: {Filename: "Hermes_01_a", Seed: 11, LastStatus: "success"}},
+		Sequence:  4,
+	}
+	if err := store.Write(ctx, genTestTarget(t, want)); err != nil {
+		t.Fatalf("LocalStore.Write() failed: %v", err)
+	}
+	got, err := store.Read(ctx, target)
+	if err != nil {
+		t.Fatalf("LocalStore.Read() failed: %v", err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("LocalStore.Read() = %v, want %v", got, want)
+	}
+
+	// Flip a bit of the journal after the checksum.
+	path := store.path(target)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read journal file: %v", err)
+	}
+	data[len(data)-1] ^= 1
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to write journal file: %v", err)
+	}
+	if _, err := store.Read(ctx, target); metrics.StatusOf(err) != metrics.FileCorrupted {
+		t.Errorf("LocalStore.Read() of a corrupted journal file = %v, want status %q", err, metrics.ExitStatusName[metrics.FileCorrupted])
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	older := &journalpb.StateJournal{
+		Filenames: map[int32]string{1: "Hermes_01_a"},
+		Files:     map[int32]*journalpb.FileEntry{1: {Filename: "Hermes_01_a"}},
+		Sequence:  1,
+	}
+	newer := &journalpb.StateJournal{
+		Filenames: map[int32]string{1: "Hermes_01_a", 2: "Hermes_02_b"},
+		Files:     map[int32]*journalpb.FileEntry{1: {Filename: "Hermes_01_a"}, 2: {Filename: "Hermes_02_b"}},
+		Sequence:  2,
+	}
+	tests := []struct {
+		desc       string
+		bucket     *journalpb.StateJournal
+		local      *journalpb.StateJournal
+		want       *journalpb.StateJournal
+		wantStatus metrics.ExitStatus
+	}{
+		{
+			desc:       "no journal",
+			wantStatus: metrics.FileMissing,
+		},
+		{
+			desc:       "matching journals",
+			bucket:     newer,
+			local:      newer,
+			want:       newer,
+			wantStatus: metrics.Success,
+		},
+		{
+			desc:       "local journal is newer",
+			bucket:     older,
+			local:      newer,
+			want:       newer,
+			wantStatus: metrics.Success,
+		},
+		{
+			desc:       "bucket journal is newer",
+			bucket:     newer,
+			local:      older,
+			want:       newer,
+			wantStatus: metrics.Success,
+		},
+		{
+			desc:       "local journal is missing",
+			bucket:     older,
+			want:       older,
+			wantStatus: metrics.Success,
+		},
+	}
+
+	for _, tc := range tests {
+		ctx := context.Background()
+		logger := fakegcs.NewLogger(ctx).Logger
+		client := fakegcs.NewClient()
+		if err := client.Bucket(bucketName).Create(ctx, bucketName, nil); err != nil {
+			t.Fatalf("failed to create fake bucket: %v", err)
+		}
+		stores := []Store{NewBucketStore(client), NewLocalStore(tempDir(t))}
+		for i, j := range []*journalpb.StateJournal{tc.bucket, tc.local} {
+			if j == nil {
+				continue
+			}
+			if err := stores[i].Write(ctx, genTestTarget(t, j)); err != nil {
+				t.Fatalf("%s: %s.Write() failed: %v", tc.desc, stores[i].Name(), err)
+			}
+		}
+
+		target := genTestTarget(t, nil)
+		got, err := Reconcile(ctx, target, stores, logger)
+		if status := metrics.StatusOf(err); status != tc.wantStatus {
+			t.Errorf("%s: Reconcile() = %v, want status %q", tc.desc, err, metrics.ExitStatusName[tc.wantStatus])
+		}
+		if err != nil {
+			continue
+		}
+		if !proto.Equal(got, tc.want) {
+			t.Errorf("%s: Reconcile() = %v, want %v", tc.desc, got, tc.want)
+		}
+		// Every store holds the latest journal after reconciling.
+		for _, s := range stores {
+			if j, err := s.Read(ctx, target); err != nil || !proto.Equal(j, tc.want) {
+				t.Errorf("%s: %s.Read() after Reconcile() = %v, %v, want %v", tc.desc, s.Name(), j, err, tc.want)
+			}
+		}
+	}
+}
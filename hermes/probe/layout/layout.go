@@ -0,0 +1,127 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package layout implements the layout of the file IDs Hermes keeps on a target.
+// Every probe operation uses it to decide which file IDs are valid.
+package layout
+
+import (
+	"fmt"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+const (
+	// NilFileID is the ID of the NIL file, which stores the StateJournal of a target.
+	NilFileID = 0
+	// MinFileID is the smallest ID of a file created by Hermes.
+	MinFileID       = 1
+	defaultNumFiles = 50
+	// permanentFraction is the default number of permanent files as a fraction of the number of files.
+	permanentFraction = 5
+)
+
+// Layout holds the ranges of file IDs kept on a target.
+// Files MinFileID to MaxFileID() exist on the target once it is bootstrapped.
+// Permanent files, MinFileID to MaxPermanentFileID(), are never deleted.
+// Rotating files, MinRotatingFileID() to MaxFileID(), are deleted and recreated.
+type Layout struct {
+	// NumFiles is the number of files kept on the target.
+	NumFiles int32
+	// NumPermanentFiles is the number of files that are never deleted.
+	NumPermanentFiles int32
+}
+
+// Default returns the layout of a target without a FileLayout config: 50 files of which files 1-10 are permanent.
+func Default() *Layout {
+	return &Layout{NumFiles: defaultNumFiles, NumPermanentFiles: defaultNumFiles / permanentFraction}
+}
+
+// New returns the layout described by the FileLayout config of a target.
+// Arguments:
+//	- conf: FileLayout config of the target, unset fields take their default values.
+// Returns:
+//	- layout: returns the layout of the target.
+//	- err: returns an error if the config has negative values or leaves no files to rotate.
+func New(conf *probepb.Target_FileLayout) (*Layout, error) {
+	l := &Layout{NumFiles: conf.GetNumFiles(), NumPermanentFiles: conf.GetNumPermanentFiles()}
+	if l.NumFiles == 0 {
+		l.NumFiles = defaultNumFiles
+	}
+	if l.NumPermanentFiles == 0 {
+		l.NumPermanentFiles = l.NumFiles / permanentFraction
+	}
+	if l.NumFiles < 0 || l.NumPermanentFiles < 0 {
+		return nil, fmt.Errorf("invalid file layout: num_files = %d, num_permanent_files = %d; want non-negative values", l.NumFiles, l.NumPermanentFiles)
+	}
+	if l.NumPermanentFiles >= l.NumFiles {
+		return nil, fmt.Errorf("invalid file layout: num_permanent_files = %d; want num_permanent_files < num_files = %d", l.NumPermanentFiles, l.NumFiles)
+	}
+	return l, nil
+}
+
+// MaxFileID returns the largest ID of a file on the target.
+func (l *Layout) MaxFileID() int32 {
+	return l.NumFiles
+}
+
+// MaxPermanentFileID returns the largest ID of a permanent file, or 0 if there are no permanent files.
+func (l *Layout) MaxPermanentFileID() int32 {
+	return l.NumPermanentFiles
+}
+
+// MinRotatingFileID returns the smallest ID of a rotating file.
+func (l *Layout) MinRotatingFileID() int32 {
+	return l.NumPermanentFiles + 1
+}
+
+// Contains reports whether the ID is the ID of a file created by Hermes on the target.
+func (l *Layout) Contains(id int32) bool {
+	return id >= MinFileID && id <= l.MaxFileID()
+}
+
+// IsPermanent reports whether the ID is the ID of a permanent file.
+func (l *Layout) IsPermanent(id int32) bool {
+	return id >= MinFileID && id <= l.MaxPermanentFileID()
+}
+
+// IsRotating reports whether the ID is the ID of a rotating file.
+func (l *Layout) IsRotating(id int32) bool {
+	return id >= l.MinRotatingFileID() && id <= l.MaxFileID()
+}
+
+// FileIDs returns the IDs of every file on the target in ascending order.
+func (l *Layout) FileIDs() []int32 {
+	return idRange(MinFileID, l.MaxFileID())
+}
+
+// RotatingFileIDs returns the IDs of the rotating files in ascending order.
+func (l *Layout) RotatingFileIDs() []int32 {
+	return idRange(l.MinRotatingFileID(), l.MaxFileID())
+}
+
+// String returns the ranges of the layout.
+func (l *Layout) String() string {
+	return fmt.Sprintf("files [%d, %d], permanent [%d, %d], rotating [%d, %d]",
+		MinFileID, l.MaxFileID(), MinFileID, l.MaxPermanentFileID(), l.MinRotatingFileID(), l.MaxFileID())
+}
+
+// idRange returns the IDs from min to max inclusive.
+func idRange(min, max int32) []int32 {
+	var ids []int32
+	for id := min; id <= max; id++ {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package layout
+
+import (
+	"reflect"
+	"testing"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		desc    string
+		conf    *probepb.Target_FileLayout
+		want    *Layout
+		wantErr bool
+	}{
+		{"unset", nil, &Layout{NumFiles: 50, NumPermanentFiles: 10}, false},
+		{"default permanent files", &probepb.Target_FileLayout{NumFiles: 20}, &Layout{NumFiles: 20, NumPermanentFiles: 4}, false},
+		{"single rotating file", &probepb.Target_FileLayout{NumFiles: 1}, &Layout{NumFiles: 1, NumPermanentFiles: 0}, false},
+		{"explicit", &probepb.Target_FileLayout{NumFiles: 30, NumPermanentFiles: 29}, &Layout{NumFiles: 30, NumPermanentFiles: 29}, false},
+		{"no rotating files", &probepb.Target_FileLayout{NumFiles: 30, NumPermanentFiles: 30}, nil, true},
+		{"negative files", &probepb.Target_FileLayout{NumFiles: -5}, nil, true},
+		{"negative permanent files", &probepb.Target_FileLayout{NumPermanentFiles: -1}, nil, true},
+	}
+	for _, tc := range tests {
+		got, err := New(tc.conf)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: New(%v) returned error %v, want error: %v", tc.desc, tc.conf, err, tc.wantErr)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: New(%v) = %v, want %v", tc.desc, tc.conf, got, tc.want)
+		}
+	}
+	if got := Default(); !reflect.DeepEqual(got, &Layout{NumFiles: 50, NumPermanentFiles: 10}) {
+		t.Errorf("Default() = %v, want files [1, 50] of which [1, 10] are permanent", got)
+	}
+}
+
+func TestRanges(t *testing.T) {
+	l := &Layout{NumFiles: 5, NumPermanentFiles: 2}
+	if got, want := l.FileIDs(), []int32{1, 2, 3, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("%v: FileIDs() = %v, want %v", l, got, want)
+	}
+	if got, want := l.RotatingFileIDs(), []int32{3, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("%v: RotatingFileIDs() = %v, want %v", l, got, want)
+	}
+	tests := []struct {
+		id                                int32
+		contains, isPermanent, isRotating bool
+	}{
+		{NilFileID, false, false, false},
+		{1, true, true, false},
+		{2, true, true, false},
+		{3, true, false, true},
+		{5, true, false, true},
+		{6, false, false, false},
+	}
+	for _, tc := range tests {
+		if got := l.Contains(tc.id); got != tc.contains {
+			t.Errorf("%v: Contains(%d) = %v, want %v", l, tc.id, got, tc.contains)
+		}
+		if got := l.IsPermanent(tc.id); got != tc.isPermanent {
+			t.Errorf("%v: IsPermanent(%d) = %v, want %v", l, tc.id, got, tc.isPermanent)
+		}
+		if got := l.IsRotating(tc.id); got != tc.isRotating {
+			t.Errorf("%v: IsRotating(%d) = %v, want %v", l, tc.id, got, tc.isRotating)
+		}
+	}
+}
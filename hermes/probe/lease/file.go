@@ -0,0 +1,163 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// File implements a locker storing the leases of the targets as files in a local directory.
+
+package lease
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+
+	leasepb "github.com/googleinterns/step224-2020/hermes/proto"
+)
+
+// FileLocker stores the lease of each target in a file in a local directory.
+// It stands in for the BucketLocker in tests and single-host setups: only instances sharing the
+// directory exclude each other. Lease files are only replaced while holding a lock file, which is
+// created exclusively, so two instances racing to acquire an expired lease cannot both succeed.
+type FileLocker struct {
+	dir      string
+	holder   string
+	duration time.Duration
+	// now returns the current time, it is replaced in tests.
+	now func() time.Time
+}
+
+// NewFileLocker returns a locker storing leases in the directory given, which is created if needed.
+// Arguments:
+//	- dir: the directory holding the lease files.
+//	- holder: identifies this instance in the leases it holds, it must be unique among the instances.
+//	- duration: time after which a lease that was not renewed expires.
+func NewFileLocker(dir, holder string, duration time.Duration) *FileLocker {
+	return &FileLocker{dir: dir, holder: holder, duration: duration, now: time.Now}
+}
+
+// path returns the path of the lease file of the target.
+func (l *FileLocker) path(target *target.Target) string {
+	return filepath.Join(l.dir, fmt.Sprintf("%s_%s.lease", target.Target.GetName(), target.Target.GetBucketName()))
+}
+
+// lock creates the lock file of the target. It returns false if the lock is held by another instance.
+// A lock file older than the lease duration was left behind by a crashed instance and is removed.
+func (l *FileLocker) lock(target *target.Target) (bool, error) {
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return false, metrics.NewProbeError(metrics.ProbeFailed, fmt.Errorf("could not create lease directory %q: %w", l.dir, err))
+	}
+	path := l.path(target) + ".lock"
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if os.IsExist(err) {
+		if info, statErr := os.Stat(path); statErr == nil && l.now().Sub(info.ModTime()) > l.duration {
+			os.Remove(path)
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, metrics.NewProbeError(metrics.ProbeFailed, fmt.Errorf("could not create lock file %q: %w", path, err))
+	}
+	return true, f.Close()
+}
+
+// unlock removes the lock file of the target.
+func (l *FileLocker) unlock(target *target.Target) {
+	os.Remove(l.path(target) + ".lock")
+}
+
+// read reads the lease of the target, which is nil if there is no lease file.
+func (l *FileLocker) read(target *target.Target) (*leasepb.Lease, error) {
+	path := l.path(target)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, metrics.NewProbeError(metrics.FileReadFailure, fmt.Errorf("could not read lease file %q: %w", path, err))
+	}
+	lease := &leasepb.Lease{}
+	if err := proto.Unmarshal(data, lease); err != nil {
+		return nil, metrics.NewProbeError(metrics.FileCorrupted, fmt.Errorf("could not parse lease file %q: %w", path, err))
+	}
+	return lease, nil
+}
+
+// Acquire acquires or renews the lease of the target by writing its lease file.
+// Arguments:
+//	- ctx: the context of the probe run.
+//	- target: the target whose lease is acquired.
+// Returns:
+//	- held: returns whether this instance holds the lease of the target.
+//	- err: returns a *metrics.ProbeError if the lease file could not be read or written.
+func (l *FileLocker) Acquire(ctx context.Context, target *target.Target) (bool, error) {
+	bucketName := target.Target.GetBucketName()
+	locked, err := l.lock(target)
+	if err != nil || !locked {
+		return false, err
+	}
+	defer l.unlock(target)
+
+	current, err := l.read(target)
+	if err != nil {
+		return false, fmt.Errorf("Acquire(%q) failed: %w", bucketName, err)
+	}
+	next, ok := claim(current, l.holder, l.now(), l.duration)
+	if !ok {
+		return false, nil
+	}
+	data, err := proto.Marshal(next)
+	if err != nil {
+		return false, metrics.NewProbeError(metrics.ProbeFailed, fmt.Errorf("Acquire(%q) could not marshal lease: %w", bucketName, err))
+	}
+	if err := ioutil.WriteFile(l.path(target), data, 0644); err != nil {
+		return false, metrics.NewProbeError(metrics.ProbeFailed, fmt.Errorf("Acquire(%q) could not write lease file: %w", bucketName, err))
+	}
+	return true, nil
+}
+
+// Release removes the lease file of the target if it holds a lease of this instance.
+// Arguments:
+//	- ctx: the context used to release the lease.
+//	- target: the target whose lease is released.
+// Returns:
+//	- err: returns a *metrics.ProbeError if the lease file could not be read or removed.
+func (l *FileLocker) Release(ctx context.Context, target *target.Target) error {
+	bucketName := target.Target.GetBucketName()
+	locked, err := l.lock(target)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return metrics.NewProbeError(metrics.ProbeFailed, fmt.Errorf("Release(%q) failed: lease file is locked by another instance", bucketName))
+	}
+	defer l.unlock(target)
+
+	current, err := l.read(target)
+	if err != nil {
+		return fmt.Errorf("Release(%q) failed: %w", bucketName, err)
+	}
+	if current == nil || current.GetHolder() != l.holder {
+		return nil
+	}
+	if err := os.Remove(l.path(target)); err != nil {
+		return metrics.NewProbeError(metrics.ProbeFailed, fmt.Errorf("Release(%q) could not remove lease file: %w", bucketName, err))
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lease implements the per-target leases that let several Hermes
+// instances run for high availability. Only the instance holding the lease
+// of a target probes it. The holder renews the lease on every probe run and
+// another instance takes it over once it expires.
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/golang/protobuf/proto"
+	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+	"google.golang.org/api/googleapi"
+
+	leasepb "github.com/googleinterns/step224-2020/hermes/proto"
+)
+
+const (
+	// FileName is the name of the lock object holding the lease of a target in its bucket.
+	FileName = "Hermes_00_LEASE"
+	// ContentType is the content type of the lock object.
+	ContentType = "application/x-protobuf"
+	apiLatency  = "hermes_api_latency_seconds"
+)
+
+// Locker acquires and releases the leases of targets on behalf of a Hermes instance.
+type Locker interface {
+	// Acquire acquires the lease of the target, or renews it if it is already held by this instance.
+	// It returns false if the lease is held by another instance and has not expired.
+	Acquire(ctx context.Context, target *target.Target) (bool, error)
+	// Release releases the lease of the target if it is held by this instance,
+	// so that another instance can take over without waiting for it to expire.
+	Release(ctx context.Context, target *target.Target) error
+}
+
+// claim returns the lease the holder acquires given the current lease, which is nil if there is none.
+// Returns false if the current lease is held by another holder and has not expired at time now.
+func claim(current *leasepb.Lease, holder string, now time.Time, duration time.Duration) (*leasepb.Lease, bool) {
+	if current != nil && current.GetHolder() != holder && now.Unix() < current.GetExpiresUnixSec() {
+		return nil, false
+	}
+	return &leasepb.Lease{
+		Holder:         holder,
+		RenewedUnixSec: now.Unix(),
+		ExpiresUnixSec: now.Add(duration).Unix(),
+	}, true
+}
+
+// BucketLocker stores the lease of a target in a lock object in the target bucket.
+// The lock object is only replaced if its generation has not changed since it was read,
+// so two instances racing to acquire an expired lease cannot both succeed.
+type BucketLocker struct {
+	client   stiface.Client
+	holder   string
+	duration time.Duration
+	// now returns the current time, it is replaced in tests.
+	now func() time.Time
+}
+
+// NewBucketLocker returns a locker storing leases in the target buckets.
+// Arguments:
+//	- client: initialised storage client for the target system.
+//	- holder: identifies this instance in the leases it holds, it must be unique among the instances.
+//	- duration: time after which a lease that was not renewed expires.
+func NewBucketLocker(client stiface.Client, holder string, duration time.Duration) *BucketLocker {
+	return &BucketLocker{client: client, holder: holder, duration: duration, now: time.Now}
+}
+
+// isPreconditionFailed reports whether an API call failed because a generation precondition did not hold.
+func isPreconditionFailed(err error) bool {
+	var e *googleapi.Error
+	return errors.As(err, &e) && e.Code == http.StatusPreconditionFailed
+}
+
+// read reads the lease of the target and the generation of its lock object.
+// The lease is nil and the generation 0 if there is no lock object.
+func (l *BucketLocker) read(ctx context.Context, target *target.Target) (*leasepb.Lease, int64, error) {
+	// TODO(#77): Refactor timing into using function from metrics.go
+	start := time.Now()
+	record := func(status metrics.ExitStatus) {
+		target.LatencyMetrics.APICallLatency[metrics.APIReadLease][status].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+	}
+	fail := func(err error) (*leasepb.Lease, int64, error) {
+		status := metrics.APICallFailed
+		if err == storage.ErrBucketNotExist {
+			status = metrics.BucketMissing
+		}
+		record(status)
+		return nil, 0, metrics.NewProbeError(status, fmt.Errorf("could not read lease %q: %w", FileName, err))
+	}
+	obj := l.client.Bucket(target.Target.GetBucketName()).Object(FileName)
+	attrs, err := obj.Attrs(ctx)
+	if err == storage.ErrObjectNotExist {
+		record(metrics.Success)
+		return nil, 0, nil
+	}
+	if err != nil {
+		return fail(err)
+	}
+	// The generation read is pinned, so that the lease is replaced only if it is still the one read.
+	reader, err := obj.Generation(attrs.Generation).NewReader(ctx)
+	if err == storage.ErrObjectNotExist {
+		record(metrics.Success)
+		return nil, 0, nil
+	}
+	if err != nil {
+		return fail(err)
+	}
+	defer reader.Close()
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		record(metrics.FileReadFailure)
+		return nil, 0, metrics.NewProbeError(metrics.FileReadFailure, fmt.Errorf("could not read lease %q: %w", FileName, err))
+	}
+	record(metrics.Success)
+
+	lease := &leasepb.Lease{}
+	if err := proto.Unmarshal(data, lease); err != nil {
+		return nil, 0, metrics.NewProbeError(metrics.FileCorrupted, fmt.Errorf("could not parse lease %q: %w", FileName, err))
+	}
+	return lease, attrs.Generation, nil
+}
+
+// Acquire acquires or renews the lease of the target by writing the lock object,
+// on the condition that it was not replaced since it was read.
+// Arguments:
+//	- ctx: the context of the probe run.
+//	- target: the target whose lease is acquired.
+// Returns:
+//	- held: returns whether this instance holds the lease of the target.
+//	- err: returns a *metrics.ProbeError if the lease could not be read or written.
+func (l *BucketLocker) Acquire(ctx context.Context, target *target.Target) (bool, error) {
+	bucketName := target.Target.GetBucketName()
+	current, generation, err := l.read(ctx, target)
+	if err != nil {
+		return false, fmt.Errorf("Acquire(%q) failed: %w", bucketName, err)
+	}
+	next, ok := claim(current, l.holder, l.now(), l.duration)
+	if !ok {
+		return false, nil
+	}
+	data, err := proto.Marshal(next)
+	if err != nil {
+		return false, metrics.NewProbeError(metrics.ProbeFailed, fmt.Errorf("Acquire(%q) could not marshal lease: %w", bucketName, err))
+	}
+
+	conds := storage.Conditions{DoesNotExist: true}
+	if generation != 0 {
+		conds = storage.Conditions{GenerationMatch: generation}
+	}
+	// TODO(#77): Refactor timing into using function from metrics.go
+	start := time.Now()
+	wc := l.client.Bucket(bucketName).Object(FileName).If(conds).NewWriter(ctx)
+	wc.ObjectAttrs().ContentType = ContentType
+	_, err = wc.Write(data)
+	if closeErr := wc.Close(); err == nil {
+		err = closeErr
+	}
+	if isPreconditionFailed(err) {
+		// Another instance acquired the lease since it was read.
+		target.LatencyMetrics.APICallLatency[metrics.APIWriteLease][metrics.Success].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+		return false, nil
+	}
+	if err != nil {
+		target.LatencyMetrics.APICallLatency[metrics.APIWriteLease][metrics.WriterCloseFailed].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+		return false, metrics.NewProbeError(metrics.WriterCloseFailed, fmt.Errorf("Acquire(%q) could not write lease %q: %w", bucketName, FileName, err))
+	}
+	target.LatencyMetrics.APICallLatency[metrics.APIWriteLease][metrics.Success].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+	return true, nil
+}
+
+// Release deletes the lock object of the target if it holds a lease of this instance.
+// Arguments:
+//	- ctx: the context used to release the lease.
+//	- target: the target whose lease is released.
+// Returns:
+//	- err: returns a *metrics.ProbeError if the lease could not be read or deleted.
+func (l *BucketLocker) Release(ctx context.Context, target *target.Target) error {
+	bucketName := target.Target.GetBucketName()
+	current, generation, err := l.read(ctx, target)
+	if err != nil {
+		return fmt.Errorf("Release(%q) failed: %w", bucketName, err)
+	}
+	if current == nil || current.GetHolder() != l.holder {
+		return nil
+	}
+	// TODO(#77): Refactor timing into using function from metrics.go
+	start := time.Now()
+	err = l.client.Bucket(bucketName).Object(FileName).If(storage.Conditions{GenerationMatch: generation}).Delete(ctx)
+	if err != nil && !isPreconditionFailed(err) && err != storage.ErrObjectNotExist {
+		target.LatencyMetrics.APICallLatency[metrics.APIReleaseLease][metrics.APICallFailed].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+		return metrics.NewProbeError(metrics.APICallFailed, fmt.Errorf("Release(%q) could not delete lease %q: %w", bucketName, FileName, err))
+	}
+	target.LatencyMetrics.APICallLatency[metrics.APIReleaseLease][metrics.Success].Metric(apiLatency).AddFloat64(time.Now().Sub(start).Seconds())
+	return nil
+}
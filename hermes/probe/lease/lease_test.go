@@ -0,0 +1,115 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lease
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/googleinterns/step224-2020/hermes/probe/fakegcs"
+	"github.com/googleinterns/step224-2020/hermes/probe/probetest"
+)
+
+const (
+	bucketName = "test_bucket_lease"
+	duration   = time.Minute
+)
+
+// clock is a fake clock shared by the lockers of a test.
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+// newFileLockers returns two lockers sharing a lease directory, which is removed at the end of the test.
+func newFileLockers(ctx context.Context, t *testing.T, c *clock) (Locker, Locker) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "lease_test")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir() failed: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	a, b := NewFileLocker(dir, "a", duration), NewFileLocker(dir, "b", duration)
+	a.now, b.now = c.now, c.now
+	return a, b
+}
+
+// newBucketLockers returns two lockers sharing a fake bucket.
+func newBucketLockers(ctx context.Context, t *testing.T, c *clock) (Locker, Locker) {
+	t.Helper()
+	client := fakegcs.NewClient()
+	if err := client.Bucket(bucketName).Create(ctx, bucketName, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	a, b := NewBucketLocker(client, "a", duration), NewBucketLocker(client, "b", duration)
+	a.now, b.now = c.now, c.now
+	return a, b
+}
+
+func TestLockers(t *testing.T) {
+	lockers := []struct {
+		desc string
+		new  func(context.Context, *testing.T, *clock) (Locker, Locker)
+	}{
+		{desc: "FileLocker", new: newFileLockers},
+		{desc: "BucketLocker", new: newBucketLockers},
+	}
+
+	for _, l := range lockers {
+		ctx := context.Background()
+		c := &clock{t: time.Unix(1000000, 0)}
+		a, b := l.new(ctx, t, c)
+		target := probetest.NewTarget(t, "lease_test", probetest.TargetConfig(bucketName))
+
+		steps := []struct {
+			desc    string
+			locker  Locker
+			advance time.Duration
+			release bool
+			want    bool
+		}{
+			{desc: "a acquires the free lease", locker: a, want: true},
+			{desc: "b cannot acquire the lease held by a", locker: b, want: false},
+			{desc: "a renews its lease", locker: a, advance: duration / 2, want: true},
+			{desc: "b cannot acquire the renewed lease", locker: b, advance: duration / 2, want: false},
+			{desc: "b takes over the expired lease", locker: b, advance: duration, want: true},
+			{desc: "a cannot acquire the lease taken over by b", locker: a, want: false},
+			{desc: "b releases its lease", locker: b, release: true},
+			{desc: "a acquires the released lease", locker: a, want: true},
+		}
+		for _, s := range steps {
+			c.t = c.t.Add(s.advance)
+			if s.release {
+				if err := s.locker.Release(ctx, target); err != nil {
+					t.Errorf("%s: %s: Release() failed: %v", l.desc, s.desc, err)
+				}
+				continue
+			}
+			got, err := s.locker.Acquire(ctx, target)
+			if err != nil {
+				t.Errorf("%s: %s: Acquire() failed: %v", l.desc, s.desc, err)
+			}
+			if got != s.want {
+				t.Errorf("%s: %s: Acquire() = %t, want %t", l.desc, s.desc, got, s.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package maintenance implements the planned maintenance windows of the targets,
+// during which Hermes does not probe a target or marks its results as maintenance.
+package maintenance
+
+import (
+	"fmt"
+	"time"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+// Window is a maintenance window, either a one-off range of time or a recurring window
+// starting at every match of a cron schedule.
+type Window struct {
+	// start and end bound a one-off window.
+	start, end time.Time
+	// cron, duration and location define a recurring window.
+	cron     *Cron
+	duration time.Duration
+	location *time.Location
+}
+
+// New returns the maintenance window defined by the config given.
+// Arguments:
+//	- conf: the config of the window, either a one-off range or a schedule with a duration.
+// Returns:
+//	- window: returns the maintenance window.
+//	- err: returns an error if the config does not define a valid window.
+func New(conf *probepb.MaintenanceWindow) (*Window, error) {
+	if conf.GetSchedule() == "" {
+		if conf.GetStartUnixSec() <= 0 || conf.GetEndUnixSec() <= conf.GetStartUnixSec() {
+			return nil, fmt.Errorf("invalid maintenance window: start_unix_sec = %d, end_unix_sec = %d; want a schedule or a start before the end", conf.GetStartUnixSec(), conf.GetEndUnixSec())
+		}
+		return &Window{start: time.Unix(conf.GetStartUnixSec(), 0), end: time.Unix(conf.GetEndUnixSec(), 0)}, nil
+	}
+	if conf.GetStartUnixSec() != 0 || conf.GetEndUnixSec() != 0 {
+		return nil, fmt.Errorf("invalid maintenance window: a window has either a schedule or a start and end, not both")
+	}
+	if conf.GetDurationSec() <= 0 {
+		return nil, fmt.Errorf("invalid maintenance window: duration_sec = %d; want a positive duration for schedule %q", conf.GetDurationSec(), conf.GetSchedule())
+	}
+	cron, err := ParseCron(conf.GetSchedule())
+	if err != nil {
+		return nil, fmt.Errorf("invalid maintenance window: %w", err)
+	}
+	location := time.UTC
+	if tz := conf.GetTimeZone(); tz != "" {
+		if location, err = time.LoadLocation(tz); err != nil {
+			return nil, fmt.Errorf("invalid maintenance window: time_zone = %q: %w", tz, err)
+		}
+	}
+	return &Window{cron: cron, duration: time.Duration(conf.GetDurationSec()) * time.Second, location: location}, nil
+}
+
+// NewWindows returns the maintenance windows defined by the configs given.
+func NewWindows(confs []*probepb.MaintenanceWindow) ([]*Window, error) {
+	var windows []*Window
+	for _, conf := range confs {
+		w, err := New(conf)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// Active returns true if the time given is within the window.
+// A recurring window is active if its schedule matched a minute within its duration before the time.
+func (w *Window) Active(now time.Time) bool {
+	if w.cron == nil {
+		return !now.Before(w.start) && now.Before(w.end)
+	}
+	// Walk back through the start of every minute the window would still be active from.
+	minute := now.In(w.location).Truncate(time.Minute)
+	for start := minute; now.Sub(start) < w.duration; start = start.Add(-time.Minute) {
+		if w.cron.Matches(start) {
+			return true
+		}
+	}
+	return false
+}
+
+// Active returns true if the time given is within any of the windows given.
+func Active(windows []*Window, now time.Time) bool {
+	for _, w := range windows {
+		if w.Active(now) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+func date(day, hour, minute int) time.Time {
+	// 2020-08-01 is a Saturday.
+	return time.Date(2020, time.August, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestCronMatches(t *testing.T) {
+	tests := []struct {
+		spec string
+		time time.Time
+		want bool
+	}{
+		{spec: "* * * * *", time: date(1, 13, 7), want: true},
+		{spec: "0 2 * * 6", time: date(1, 2, 0), want: true},
+		{spec: "0 2 * * 6", time: date(2, 2, 0), want: false},
+		{spec: "0 2 * * 6", time: date(1, 2, 1), want: false},
+		{spec: "*/15 * * * *", time: date(3, 4, 45), want: true},
+		{spec: "*/15 * * * *", time: date(3, 4, 40), want: false},
+		{spec: "0 9-17 * * 1-5", time: date(3, 12, 0), want: true},
+		{spec: "0 9-17 * * 1-5", time: date(3, 18, 0), want: false},
+		{spec: "0 0 1,15 * *", time: date(15, 0, 0), want: true},
+		// Sunday is both 0 and 7.
+		{spec: "30 1 * * 7", time: date(2, 1, 30), want: true},
+		// As in cron, a restricted day of month or day of week matches.
+		{spec: "0 0 15 * 6", time: date(1, 0, 0), want: true},
+		{spec: "0 0 15 * 6", time: date(15, 0, 0), want: true},
+		{spec: "0 0 15 * 6", time: date(3, 0, 0), want: false},
+		{spec: "0 0 * 9 *", time: date(1, 0, 0), want: false},
+	}
+	for _, tc := range tests {
+		c, err := ParseCron(tc.spec)
+		if err != nil {
+			t.Fatalf("ParseCron(%q) failed: %v", tc.spec, err)
+		}
+		if got := c.Matches(tc.time); got != tc.want {
+			t.Errorf("ParseCron(%q).Matches(%v) = %t, want %t", tc.spec, tc.time, got, tc.want)
+		}
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestWindowActive(t *testing.T) {
+	tests := []struct {
+		desc    string
+		conf    *probepb.MaintenanceWindow
+		time    time.Time
+		want    bool
+		wantErr bool
+	}{
+		{
+			desc: "within a one-off window",
+			conf: &probepb.MaintenanceWindow{StartUnixSec: date(1, 2, 0).Unix(), EndUnixSec: date(1, 4, 0).Unix()},
+			time: date(1, 3, 0),
+			want: true,
+		},
+		{
+			desc: "at the end of a one-off window",
+			conf: &probepb.MaintenanceWindow{StartUnixSec: date(1, 2, 0).Unix(), EndUnixSec: date(1, 4, 0).Unix()},
+			time: date(1, 4, 0),
+			want: false,
+		},
+		{
+			desc: "within a recurring window",
+			conf: &probepb.MaintenanceWindow{Schedule: "0 2 * * 6", DurationSec: 7200},
+			time: date(8, 3, 59),
+			want: true,
+		},
+		{
+			desc: "after a recurring window",
+			conf: &probepb.MaintenanceWindow{Schedule: "0 2 * * 6", DurationSec: 7200},
+			time: date(8, 4, 0),
+			want: false,
+		},
+		{
+			desc: "recurring window spanning midnight",
+			conf: &probepb.MaintenanceWindow{Schedule: "0 23 * * 6", DurationSec: 7200},
+			time: date(2, 0, 30),
+			want: true,
+		},
+		{
+			desc: "recurring window in a time zone",
+			conf: &probepb.MaintenanceWindow{Schedule: "0 2 * * 6", DurationSec: 3600, TimeZone: "America/New_York"},
+			// 02:00 EDT is 06:00 UTC.
+			time: date(1, 6, 30),
+			want: true,
+		},
+		{
+			desc:    "empty window",
+			conf:    &probepb.MaintenanceWindow{},
+			wantErr: true,
+		},
+		{
+			desc:    "end before start",
+			conf:    &probepb.MaintenanceWindow{StartUnixSec: date(1, 4, 0).Unix(), EndUnixSec: date(1, 2, 0).Unix()},
+			wantErr: true,
+		},
+		{
+			desc:    "schedule without a duration",
+			conf:    &probepb.MaintenanceWindow{Schedule: "0 2 * * 6"},
+			wantErr: true,
+		},
+		{
+			desc:    "schedule and range",
+			conf:    &probepb.MaintenanceWindow{Schedule: "0 2 * * 6", DurationSec: 60, StartUnixSec: 1, EndUnixSec: 2},
+			wantErr: true,
+		},
+		{
+			desc:    "unknown time zone",
+			conf:    &probepb.MaintenanceWindow{Schedule: "0 2 * * 6", DurationSec: 60, TimeZone: "Nowhere/Atlantis"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		w, err := New(tc.conf)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: New() = %v, want error %t", tc.desc, err, tc.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got := w.Active(tc.time); got != tc.want {
+			t.Errorf("%s: Active(%v) = %t, want %t", tc.desc, tc.time, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Errors implements the error type returned by probe operations so that the
+// exit status of a failed operation can be recovered by the caller.
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ProbeError is an error returned by a probe operation.
+// It records the exit status of the operation alongside the underlying error.
+type ProbeError struct {
+	// Status is the exit status of the failed operation.
+	Status ExitStatus
+	// Err is the underlying error.
+	Err error
+}
+
+// NewProbeError creates a new *ProbeError with the status and error given.
+func NewProbeError(status ExitStatus, err error) *ProbeError {
+	return &ProbeError{Status: status, Err: err}
+}
+
+// Error returns the error string prefixed by the exit status.
+func (e *ProbeError) Error() string {
+	return fmt.Sprintf("%s: %v", ExitStatusName[e.Status], e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ProbeError) Unwrap() error {
+	return e.Err
+}
+
+// StatusOf returns the exit status of a probe operation from the error it returned.
+// Arguments:
+//	- err: the error returned by the probe operation.
+// Returns:
+//	- status: Success if err is nil, the status of the ProbeError in the chain of err if there is one
+//	  and ProbeFailed otherwise.
+func StatusOf(err error) ExitStatus {
+	if err == nil {
+		return Success
+	}
+	var pe *ProbeError
+	if errors.As(err, &pe) {
+		return pe.Status
+	}
+	return ProbeFailed
+}
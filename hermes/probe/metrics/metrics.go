@@ -45,6 +45,12 @@ const (
 	DeleteFile
 	// CreateFile is the metric label for the create file operation.
 	CreateFile
+	// VerifyDurability is the metric label for the verification of the contents of the permanent files.
+	VerifyDurability
+	// ReconcileJournal is the metric label for the reconciliation of the copies of the journal in its stores.
+	ReconcileJournal
+	// AbortUpload is the metric label for the abort of an incomplete resumable upload in RESUMABLE upload mode.
+	AbortUpload
 )
 
 // APICall represents a possible API call metric label.
@@ -59,6 +65,52 @@ const (
 	APIDeleteFile
 	// APIGetFile is the metric label for the get file API call.
 	APIGetFile
+	// APIGetFileAttrs is the metric label for the get file attributes API call.
+	APIGetFileAttrs
+	// APIGetFileRange is the metric label for the get file byte range API call.
+	APIGetFileRange
+	// APIUploadChunk is the metric label for the upload of a single chunk of a resumable upload.
+	APIUploadChunk
+	// APIAbortUpload is the metric label for the abort of an incomplete resumable upload.
+	APIAbortUpload
+	// APICopyFile is the metric label for the copy file API call.
+	APICopyFile
+	// APIReadJournal is the metric label for the read NIL file API call.
+	APIReadJournal
+	// APIWriteJournal is the metric label for the write NIL file API call.
+	APIWriteJournal
+	// APICreateBucket is the metric label for the create bucket API call.
+	APICreateBucket
+	// APIReadLease is the metric label for the read lease API call.
+	APIReadLease
+	// APIWriteLease is the metric label for the write lease API call, which acquires or renews a lease.
+	APIWriteLease
+	// APIReleaseLease is the metric label for the delete lease API call, which releases a lease.
+	APIReleaseLease
+)
+
+// ConsistencyCheck represents a possible consistency check metric label.
+type ConsistencyCheck int
+
+const (
+	// ListAfterWrite is the metric label for the lag before a created file is listed.
+	ListAfterWrite ConsistencyCheck = iota
+	// ListAfterDelete is the metric label for the lag before a deleted file is no longer listed.
+	ListAfterDelete
+	// ReadAfterDelete is the metric label for the lag before a deleted file can no longer be read.
+	ReadAfterDelete
+)
+
+// AttemptResult represents a possible result of a single attempt of an API call.
+type AttemptResult int
+
+const (
+	// AttemptSuccess indicates that the attempt succeeded.
+	AttemptSuccess AttemptResult = iota
+	// AttemptTransientError indicates that the attempt failed with a transient error, e.g. HTTP 503.
+	AttemptTransientError
+	// AttemptError indicates that the attempt failed with an error that is not transient.
+	AttemptError
 )
 
 // ExitStatus represents a possible exit status metric label.
@@ -81,13 +133,32 @@ const (
 	FileCorrupted
 	// FileReadFailure indicates that the target file could not be read.
 	FileReadFailure
-	// FileMetadataMismatch indicates that the target file did not match the expectation from its metadata
-	// TODO(evanSpendlove): Review if we need this? When would we use it?
+	// FileMetadataMismatch indicates that the size, content type or custom metadata of the target file
+	// did not match the values set when it was created.
 	FileMetadataMismatch
-	// UnknownFileFound indicates that an unknown file, not created by Hermes, was found in the target bucket.
+	// UnknownFileFound indicates that a Hermes file that is not in the StateJournal was found in the target bucket.
 	UnknownFileFound
 	// AllFilesMissing indicates that all of the Hermes files were missing.
 	AllFilesMissing
+	// WriterCloseFailed indicates that the writer used to create the target file could not be closed.
+	WriterCloseFailed
+	// InvalidArgument indicates that the operation was called with an invalid argument.
+	InvalidArgument
+	// IncompleteUploadFound indicates that an aborted upload left a file behind in the target bucket.
+	IncompleteUploadFound
+	// ConsistencyLagExceeded indicates that a change took longer than the lag SLO to become visible.
+	ConsistencyLagExceeded
+	// DurableFileCorrupted indicates that the contents of a permanent file older than the durability
+	// minimum age no longer match the checksum recorded when it was created. This is a high severity
+	// status as it indicates that data at rest was lost by the target.
+	DurableFileCorrupted
+	// JournalMismatch indicates that the copies of the journal in its stores disagreed.
+	JournalMismatch
+	// LeaseNotHeld indicates that the target was not probed as its lease is held by another Hermes instance.
+	LeaseNotHeld
+	// Maintenance indicates that the target was probed during maintenance, while it was paused
+	// or in one of its maintenance windows, so failures are expected.
+	Maintenance
 )
 
 var (
@@ -99,27 +170,61 @@ var (
 		VerifyFileContents: "verify_file_contents",
 		DeleteFile:         "delete_file",
 		CreateFile:         "create_file",
+		VerifyDurability:   "verify_durability",
+		ReconcileJournal:   "reconcile_journal",
+		AbortUpload:        "abort_upload",
 	}
 	// APICallName maps ApiCall constants to their metric label string equivalent.
 	APICallName = map[APICall]string{
-		APIListFiles:  "list_files",
-		APICreateFile: "create_file",
-		APIDeleteFile: "delete_file",
-		APIGetFile:    "get_file",
+		APIListFiles:    "list_files",
+		APICreateFile:   "create_file",
+		APIDeleteFile:   "delete_file",
+		APIGetFile:      "get_file",
+		APIGetFileAttrs: "get_file_attrs",
+		APIGetFileRange: "get_file_range",
+		APIUploadChunk:  "upload_chunk",
+		APIAbortUpload:  "abort_upload",
+		APICopyFile:     "copy_file",
+		APIReadJournal:  "read_journal",
+		APIWriteJournal: "write_journal",
+		APICreateBucket: "create_bucket",
+		APIReadLease:    "read_lease",
+		APIWriteLease:   "write_lease",
+		APIReleaseLease: "release_lease",
+	}
+	// ConsistencyCheckName maps ConsistencyCheck constants to their metric label string equivalent.
+	ConsistencyCheckName = map[ConsistencyCheck]string{
+		ListAfterWrite:  "list_after_write",
+		ListAfterDelete: "list_after_delete",
+		ReadAfterDelete: "read_after_delete",
+	}
+	// AttemptResultName maps AttemptResult constants to their metric label string equivalent.
+	AttemptResultName = map[AttemptResult]string{
+		AttemptSuccess:        "success",
+		AttemptTransientError: "transient_error",
+		AttemptError:          "error",
 	}
 	// ExitStatusName maps ExitStatus constants to their metric label string equivalent.
 	ExitStatusName = map[ExitStatus]string{
-		Success:              "success",
-		OpTimeout:            "op_timeout",
-		ProbeFailed:          "probe_failed",
-		APICallFailed:        "api_call_failed",
-		FileMissing:          "file_missing",
-		BucketMissing:        "bucket_missing",
-		FileCorrupted:        "file_corrupted",
-		FileReadFailure:      "file_read_failure",
-		FileMetadataMismatch: "file_metadata_mismatch",
-		UnknownFileFound:     "unknown_file_found",
-		AllFilesMissing:      "all_files_missing",
+		Success:                "success",
+		OpTimeout:              "op_timeout",
+		ProbeFailed:            "probe_failed",
+		APICallFailed:          "api_call_failed",
+		FileMissing:            "file_missing",
+		BucketMissing:          "bucket_missing",
+		FileCorrupted:          "file_corrupted",
+		FileReadFailure:        "file_read_failure",
+		FileMetadataMismatch:   "file_metadata_mismatch",
+		UnknownFileFound:       "unknown_file_found",
+		AllFilesMissing:        "all_files_missing",
+		WriterCloseFailed:      "writer_close_failed",
+		InvalidArgument:        "invalid_argument",
+		IncompleteUploadFound:  "incomplete_upload_found",
+		ConsistencyLagExceeded: "consistency_lag_exceeded",
+		DurableFileCorrupted:   "durable_file_corrupted",
+		JournalMismatch:        "journal_mismatch",
+		LeaseNotHeld:           "lease_not_held",
+		Maintenance:            "maintenance",
 	}
 )
 
@@ -133,6 +238,105 @@ type Metrics struct {
 	// per exit status per API call per target.
 	// Recommended usage: apiCallLatency[ApiCall][ExitStatus].Metric("latency").AddFloat64(<val>)
 	APICallLatency map[APICall]map[ExitStatus]*metrics.EventMetrics
+	// APIAttempts counts every attempt of each API call per attempt result per target,
+	// including the attempts that were retried, while APICallLatency records the final outcome of each call.
+	// Recommended usage: APIAttempts[APICall][AttemptResult].Metric("hermes_api_attempts_total").(*metrics.AtomicInt).Inc()
+	APIAttempts map[APICall]map[AttemptResult]*metrics.EventMetrics
+	// ConsistencyLag is used to record the time taken for a change to become visible
+	// with distinct labels per exit status per consistency check per target.
+	// Recommended usage: ConsistencyLag[ConsistencyCheck][ExitStatus].Metric("hermes_consistency_lag_seconds").AddFloat64(<val>)
+	ConsistencyLag map[ConsistencyCheck]map[ExitStatus]*metrics.EventMetrics
+	// Durability holds a gauge per permanent file ID with the age of the file
+	// and the time since its contents were last verified.
+	// It is replaced with NewDurabilityGauge on every probe run.
+	Durability map[int32]*metrics.EventMetrics
+	// SkippedRuns counts the scheduled runs of the target that were skipped
+	// because the previous run was still in flight.
+	// Recommended usage: SkippedRuns.Metric("hermes_skipped_runs_total").(*metrics.AtomicInt).Inc()
+	SkippedRuns *metrics.EventMetrics
+	// Paused is a gauge of 1 while the target is paused or in one of its maintenance windows, 0 otherwise.
+	// It is replaced with NewPausedGauge on every probe run.
+	Paused *metrics.EventMetrics
+	// ForeignFiles is a gauge of the number of files in the target bucket that were not created by Hermes.
+	// It is replaced with NewForeignFilesGauge on every CheckNil.
+	ForeignFiles *metrics.EventMetrics
+	// SLO holds a gauge per SLO window with the service level indicators of the target over the window.
+	// It is replaced with NewSLOGauge on every probe run, and is empty if no SLO is configured.
+	SLO []*metrics.EventMetrics
+}
+
+// NewDurabilityGauge creates the gauges of the age of a permanent file and the time since its contents were last verified.
+// Arguments:
+//	- target: the target the file is on.
+//	- fileID: the ID of the permanent file.
+//	- age: the time since the file was created.
+//	- sinceVerified: the time since the contents of the file were last verified.
+// Returns:
+//	- em: returns the gauges with the labels of the file.
+func NewDurabilityGauge(target *probepb.Target, fileID int32, age, sinceVerified time.Duration) *metrics.EventMetrics {
+	em := metrics.NewEventMetrics(time.Now()).
+		AddMetric("hermes_durable_file_age_seconds", metrics.NewFloat(age.Seconds())).
+		AddMetric("hermes_durable_file_since_verified_seconds", metrics.NewFloat(sinceVerified.Seconds())).
+		AddLabel("storage_system", target.GetTargetSystem().String()).
+		AddLabel("target", fmt.Sprintf("%s:%s", target.GetName(), target.GetBucketName())).
+		AddLabel("file_id", fmt.Sprintf("%d", fileID))
+	em.Kind = metrics.GAUGE
+	return em
+}
+
+// NewPausedGauge creates the gauge of whether a target is paused or in one of its maintenance windows.
+// Arguments:
+//	- target: the target the gauge is for.
+//	- paused: whether the target is paused or in one of its maintenance windows.
+// Returns:
+//	- em: returns the gauge with the labels of the target.
+func NewPausedGauge(target *probepb.Target, paused bool) *metrics.EventMetrics {
+	var value int64
+	if paused {
+		value = 1
+	}
+	em := metrics.NewEventMetrics(time.Now()).
+		AddMetric("hermes_target_paused", metrics.NewInt(value)).
+		AddLabel("storage_system", target.GetTargetSystem().String()).
+		AddLabel("target", fmt.Sprintf("%s:%s", target.GetName(), target.GetBucketName()))
+	em.Kind = metrics.GAUGE
+	return em
+}
+
+// NewForeignFilesGauge creates the gauge of the number of files in the bucket of a target that were not created by Hermes.
+// Arguments:
+//	- target: the target the gauge is for.
+//	- count: the number of files not created by Hermes found in the bucket.
+// Returns:
+//	- em: returns the gauge with the labels of the target.
+func NewForeignFilesGauge(target *probepb.Target, count int) *metrics.EventMetrics {
+	em := metrics.NewEventMetrics(time.Now()).
+		AddMetric("hermes_foreign_files", metrics.NewInt(int64(count))).
+		AddLabel("storage_system", target.GetTargetSystem().String()).
+		AddLabel("target", fmt.Sprintf("%s:%s", target.GetName(), target.GetBucketName()))
+	em.Kind = metrics.GAUGE
+	return em
+}
+
+// NewSLOGauge creates the gauges of the service level indicators of a target over an SLO window.
+// Arguments:
+//	- target: the target the gauges are for.
+//	- window: the label of the SLO window, e.g. 1h, 1d or 28d.
+//	- sli: the service level indicators of the target over the window.
+// Returns:
+//	- em: returns the gauges with the labels of the target and the window.
+func NewSLOGauge(target *probepb.Target, window string, sli *probepb.SLI) *metrics.EventMetrics {
+	em := metrics.NewEventMetrics(time.Now()).
+		AddMetric("hermes_slo_runs", metrics.NewInt(sli.GetRuns())).
+		AddMetric("hermes_slo_success_ratio", metrics.NewFloat(sli.GetSuccessRatio())).
+		AddMetric("hermes_slo_latency_ratio", metrics.NewFloat(sli.GetLatencyRatio())).
+		AddMetric("hermes_slo_error_budget_remaining", metrics.NewFloat(sli.GetErrorBudgetRemaining())).
+		AddMetric("hermes_slo_latency_budget_remaining", metrics.NewFloat(sli.GetLatencyBudgetRemaining())).
+		AddLabel("storage_system", target.GetTargetSystem().String()).
+		AddLabel("target", fmt.Sprintf("%s:%s", target.GetName(), target.GetBucketName())).
+		AddLabel("window", window)
+	em.Kind = metrics.GAUGE
+	return em
 }
 
 // NewMetrics creates a new *Metrics object and initialises the fields inside it.
@@ -146,6 +350,15 @@ func NewMetrics(conf *probepb.HermesProbeDef, target *probepb.Target) (*Metrics,
 	m := &Metrics{
 		ProbeOpLatency: make(map[ProbeOperation]map[ExitStatus]*metrics.EventMetrics, len(ProbeOpName)),
 		APICallLatency: make(map[APICall]map[ExitStatus]*metrics.EventMetrics, len(APICallName)),
+		APIAttempts:    make(map[APICall]map[AttemptResult]*metrics.EventMetrics, len(APICallName)),
+		ConsistencyLag: make(map[ConsistencyCheck]map[ExitStatus]*metrics.EventMetrics, len(ConsistencyCheckName)),
+		Durability:     make(map[int32]*metrics.EventMetrics),
+		SkippedRuns: metrics.NewEventMetrics(time.Now()).
+			AddMetric("hermes_skipped_runs_total", metrics.NewAtomicInt(0)).
+			AddLabel("storage_system", target.GetTargetSystem().String()).
+			AddLabel("target", fmt.Sprintf("%s:%s", target.GetName(), target.GetBucketName())),
+		Paused:       NewPausedGauge(target, false),
+		ForeignFiles: NewForeignFilesGauge(target, 0),
 	}
 
 	probeOpLatDist, err := metrics.NewDistributionFromProto(conf.GetProbeLatencyDistribution())
@@ -182,5 +395,38 @@ func NewMetrics(conf *probepb.HermesProbeDef, target *probepb.Target) (*Metrics,
 		}
 	}
 
+	for call := range APICallName {
+		m.APIAttempts[call] = make(map[AttemptResult]*metrics.EventMetrics, len(AttemptResultName))
+		for r := range AttemptResultName {
+			m.APIAttempts[call][r] = metrics.NewEventMetrics(time.Now()).
+				AddMetric("hermes_api_attempts_total", metrics.NewAtomicInt(0)).
+				AddLabel("storage_system", target.GetTargetSystem().String()).
+				AddLabel("target", fmt.Sprintf("%s:%s", target.GetName(), target.GetBucketName())).
+				AddLabel("api_call", APICallName[call]).
+				AddLabel("result", AttemptResultName[r])
+		}
+	}
+
+	lagDistConf := conf.GetConsistencyLagDistribution()
+	if lagDistConf == nil {
+		lagDistConf = conf.GetApiCallLatencyDistribution()
+	}
+	lagDist, err := metrics.NewDistributionFromProto(lagDistConf)
+	if err != nil {
+		return nil, fmt.Errorf("invalid argument: error creating consistency lag distribution from the specification (%v): %w", lagDistConf, err)
+	}
+
+	for check := range ConsistencyCheckName {
+		m.ConsistencyLag[check] = make(map[ExitStatus]*metrics.EventMetrics, len(ExitStatusName))
+		for e := range ExitStatusName {
+			m.ConsistencyLag[check][e] = metrics.NewEventMetrics(time.Now()).
+				AddMetric("hermes_consistency_lag_seconds", lagDist.Clone()).
+				AddLabel("storage_system", target.GetTargetSystem().String()).
+				AddLabel("target", fmt.Sprintf("%s:%s", target.GetName(), target.GetBucketName())).
+				AddLabel("consistency_check", ConsistencyCheckName[check]).
+				AddLabel("exit_status", ExitStatusName[e])
+		}
+	}
+
 	return m, nil
 }
@@ -23,32 +23,111 @@ package probe
 import (
 	"context"
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
+	"cloud.google.com/go/storage"
 	"github.com/google/cloudprober/logger"
 	"github.com/google/cloudprober/probes/options"
+	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+	"github.com/googleinterns/step224-2020/hermes/probe/alert"
+	"github.com/googleinterns/step224-2020/hermes/probe/bootstrap"
+	"github.com/googleinterns/step224-2020/hermes/probe/checknil"
+	"github.com/googleinterns/step224-2020/hermes/probe/create"
+	"github.com/googleinterns/step224-2020/hermes/probe/durability"
+	"github.com/googleinterns/step224-2020/hermes/probe/history"
+	"github.com/googleinterns/step224-2020/hermes/probe/journal"
+	"github.com/googleinterns/step224-2020/hermes/probe/layout"
+	"github.com/googleinterns/step224-2020/hermes/probe/lease"
+	"github.com/googleinterns/step224-2020/hermes/probe/maintenance"
 	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/ratelimit"
+	"github.com/googleinterns/step224-2020/hermes/probe/read"
+	"github.com/googleinterns/step224-2020/hermes/probe/retry"
+	"github.com/googleinterns/step224-2020/hermes/probe/schedule"
+	"github.com/googleinterns/step224-2020/hermes/probe/shard"
+	"github.com/googleinterns/step224-2020/hermes/probe/slo"
 	"github.com/googleinterns/step224-2020/hermes/probe/target"
 
 	cpmetrics "github.com/google/cloudprober/metrics"
 	probepb "github.com/googleinterns/step224-2020/config/proto"
+	deletefile "github.com/googleinterns/step224-2020/hermes/probe/delete"
 	journalpb "github.com/googleinterns/step224-2020/hermes/proto"
 )
 
 const (
 	// TODO(evanSpendlove): Refactor to use constant from metrics.go
-	probeLatency = "hermes_probe_latency_seconds"
+	probeLatency                = "hermes_probe_latency_seconds"
+	defaultMaxConcurrentTargets = 10
+	defaultIntervalJitter       = 0.1
+	skippedRuns                 = "hermes_skipped_runs_total"
 )
 
 // Probe holds aggregate information about all probe runs, per-target.
 // It also holds the config and options used to initialise the probe.
 type Probe struct {
+	// UnimplementedHermesServer answers the RPCs of the Hermes service that the probe does not implement.
+	probepb.UnimplementedHermesServer
+
 	name    string
 	config  *probepb.HermesProbeDef
 	targets []*target.Target
 	opts    *options.Options
 	logger  *logger.Logger
+	// client is the storage client shared by all targets, it is created on the first probe run.
+	client stiface.Client
+	// locker acquires the leases of the targets, it is nil if leasing is not configured.
+	locker lease.Locker
+	// clientMu guards the creation of the client and the locker, which targets run concurrently.
+	clientMu sync.Mutex
+
+	// slots bounds the number of targets probed at the same time, a run holds a slot while it runs.
+	slots chan struct{}
+	// targetLimiters limits the rate of the API calls made to each target.
+	targetLimiters map[*target.Target]*ratelimit.Limiter
+	// backendLimiters limits the rate of the API calls made to each storage system backend.
+	backendLimiters map[probepb.Target_TargetSystem]*ratelimit.Limiter
+	// retryPolicies holds the retry policy of each API call, it is nil if no API call is retried.
+	retryPolicies *retry.Policies
+
+	// instance identifies this instance on the shard ring.
+	instance string
+	// ring shards the targets across the fleet, it is nil if sharding is not configured.
+	// It is replaced when the membership changes, so it is guarded by shardMu.
+	ring    *shard.Ring
+	shardMu sync.RWMutex
+
+	// schedules holds the interval, phase offset and jitter of the runs of each target.
+	schedules map[*target.Target]*schedule.Schedule
+	// tokens holds the run token of each target, a channel with a capacity of one. Scheduled and
+	// on-demand runs hold the token of their target while they run, so that they never overlap.
+	tokens map[*target.Target]chan struct{}
+	// runs tracks the runs in flight, so that Start returns once they completed.
+	runs sync.WaitGroup
+
+	// windows holds the maintenance windows of each target, including those of the probe.
+	windows map[*target.Target][]*maintenance.Window
+	// pauses holds the targets paused by PauseTarget, it is updated by RPCs so it is guarded by pauseMu.
+	pauses  map[*target.Target]*pause
+	pauseMu sync.Mutex
+
+	// slos tracks the runs of each target over the SLO windows, it is empty if no SLO is configured.
+	slos map[*target.Target]*slo.Tracker
+	// alerts evaluates the alert rules after every run, it is nil if alerting is not configured.
+	alerts *alert.Engine
+	// sends tracks the alert notifications being sent, which are sent outside of the runs.
+	sends sync.WaitGroup
+	// history records the result of every run, it is nil if the run history is not configured.
+	history *history.Store
+	// files records the objects accessed by the run of each target in flight, if history is configured.
+	files map[*target.Target]*history.Files
+}
+
+// pause holds why a target is paused and until when, the zero time if it is paused until resumed.
+type pause struct {
+	reason string
+	until  time.Time
 }
 
 // interval returns the probing interval as a time.Duration.
@@ -58,6 +137,57 @@ func (p *Probe) interval() time.Duration {
 	return time.Duration(p.config.GetIntervalSec()) * time.Second
 }
 
+// targetInterval returns the probing interval of a target, which overrides the probing interval if set.
+// Arguments:
+//	- target: the target to be probed.
+// Returns:
+//	- time.Duration: returns the probing interval of the target.
+func (p *Probe) targetInterval(target *target.Target) time.Duration {
+	if sec := target.Target.GetIntervalSec(); sec > 0 {
+		return time.Duration(sec) * time.Second
+	}
+	return p.interval()
+}
+
+// maxInterval returns the longest probing interval of the targets.
+func (p *Probe) maxInterval() time.Duration {
+	max := p.interval()
+	for _, t := range p.targets {
+		if interval := p.targetInterval(t); interval > max {
+			max = interval
+		}
+	}
+	return max
+}
+
+// maxRenewalInterval returns the longest time between two renewals of the lease of a target: the longest
+// jittered probing interval of the targets, plus the probe timeout, as the lease is renewed at the start of a run.
+func (p *Probe) maxRenewalInterval() time.Duration {
+	max := time.Duration(float64(p.interval()) * (1 + p.jitter()))
+	for _, t := range p.targets {
+		if interval := time.Duration(float64(p.targetInterval(t)) * (1 + p.targetJitter(t))); interval > max {
+			max = interval
+		}
+	}
+	return max + p.timeout()
+}
+
+// targetJitter returns the jitter of the probing interval of a target, which overrides the jitter of the probe if set.
+func (p *Probe) targetJitter(target *target.Target) float64 {
+	if jitter := target.Target.GetIntervalJitter(); jitter > 0 {
+		return jitter
+	}
+	return p.jitter()
+}
+
+// jitter returns the jitter of the probing interval of the probe.
+func (p *Probe) jitter() float64 {
+	if p.config.IntervalJitter == nil {
+		return defaultIntervalJitter
+	}
+	return p.config.GetIntervalJitter()
+}
+
 // timeout returns the probe timeout as a time.Duration.
 // Returns:
 //	- time.Duration: returns the probe timeout
@@ -77,14 +207,108 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 	p.name = name
 	p.config = conf
 
-	for _, t := range p.config.GetTargets() {
-		p.targets = append(p.targets, &target.Target{
+	if conf := p.config.GetShard(); conf != nil {
+		p.instance = conf.GetInstance()
+		if p.instance == "" {
+			p.instance = p.config.GetLease().GetHolder()
+		}
+		if p.instance == "" {
+			p.instance = defaultInstanceID()
+		}
+		members := conf.GetMembers()
+		if len(members) == 0 {
+			members = []string{p.instance}
+		}
+		p.ring = shard.New(members, int(conf.GetVirtualNodes()))
+		if !contains(p.ring.Members(), p.instance) {
+			return fmt.Errorf("invalid argument: shard instance %q is not one of the shard members %v", p.instance, members)
+		}
+	}
+	size := int(p.config.GetMaxConcurrentTargets())
+	if size < 0 {
+		return fmt.Errorf("invalid argument: max_concurrent_targets = %d; want a non-negative value", size)
+	}
+	if size == 0 {
+		size = defaultMaxConcurrentTargets
+	}
+	p.slots = make(chan struct{}, size)
+	policies, err := retry.NewPolicies(p.config.GetRetryPolicies())
+	if err != nil {
+		return fmt.Errorf("invalid argument: %w", err)
+	}
+	p.retryPolicies = policies
+	p.targetLimiters = make(map[*target.Target]*ratelimit.Limiter)
+	p.backendLimiters = make(map[probepb.Target_TargetSystem]*ratelimit.Limiter)
+	p.schedules = make(map[*target.Target]*schedule.Schedule)
+	p.tokens = make(map[*target.Target]chan struct{})
+	p.windows = make(map[*target.Target][]*maintenance.Window)
+	p.pauses = make(map[*target.Target]*pause)
+	p.slos = make(map[*target.Target]*slo.Tracker)
+	alerts, err := alert.New(p.name, p.config.GetAlerting(), slo.Windows(p.config.GetSlo()))
+	if err != nil {
+		return fmt.Errorf("invalid argument: %w", err)
+	}
+	p.alerts = alerts
+	store, err := history.New(p.config.GetHistory())
+	if err != nil {
+		return fmt.Errorf("invalid argument: %w", err)
+	}
+	p.history = store
+	p.files = make(map[*target.Target]*history.Files)
+	probeWindows, err := maintenance.NewWindows(p.config.GetMaintenanceWindows())
+	if err != nil {
+		return fmt.Errorf("invalid argument: %w", err)
+	}
+	for i, t := range p.config.GetTargets() {
+		if _, err := layout.New(t.GetFileLayout()); err != nil {
+			return fmt.Errorf("invalid argument: target %q: %w", t.GetName(), err)
+		}
+		target := &target.Target{
 			Target: t,
 			Journal: &journalpb.StateJournal{
 				Intent:    &journalpb.Intent{},
 				Filenames: make(map[int32]string),
+				Files:     make(map[int32]*journalpb.FileEntry),
 			},
-		})
+		}
+		if t.GetIntervalSec() < 0 {
+			return fmt.Errorf("invalid argument: target %q: interval_sec = %d; want a non-negative interval", t.GetName(), t.GetIntervalSec())
+		}
+		lm, err := metrics.NewMetrics(p.config, t)
+		if err != nil {
+			return fmt.Errorf("NewMetrics(%v) failed: %w", t, err)
+		}
+		target.LatencyMetrics = lm
+		// Seed every schedule differently, so that targets and instances start at different offsets.
+		s, err := schedule.New(p.targetInterval(target), p.targetJitter(target), time.Now().UnixNano()+int64(i))
+		if err != nil {
+			return fmt.Errorf("invalid argument: target %q: %w", t.GetName(), err)
+		}
+		p.schedules[target] = s
+		p.tokens[target] = make(chan struct{}, 1)
+		windows, err := maintenance.NewWindows(t.GetMaintenanceWindows())
+		if err != nil {
+			return fmt.Errorf("invalid argument: target %q: %w", t.GetName(), err)
+		}
+		p.windows[target] = append(windows, probeWindows...)
+		tracker, err := slo.New(p.config.GetSlo(), p.timeout())
+		if err != nil {
+			return fmt.Errorf("invalid argument: %w", err)
+		}
+		if tracker != nil {
+			p.slos[target] = tracker
+		}
+		p.targets = append(p.targets, target)
+		if p.history != nil {
+			p.files[target] = &history.Files{}
+		}
+		p.targetLimiters[target] = ratelimit.New(t.GetApiQpsLimit())
+		if _, ok := p.backendLimiters[t.GetTargetSystem()]; !ok {
+			p.backendLimiters[t.GetTargetSystem()] = ratelimit.New(p.config.GetBackendApiQpsLimit())
+		}
+	}
+	if sec := p.config.GetLease().GetDurationSec(); sec < 0 || (sec > 0 && time.Duration(sec)*time.Second <= p.maxRenewalInterval()) {
+		return fmt.Errorf("invalid argument: lease duration_sec = %d; want a duration longer than the longest jittered probing interval plus the timeout, %v", sec, p.maxRenewalInterval())
 	}
 	p.opts = opts
 	p.logger = opts.Logger
@@ -93,26 +317,73 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 }
 
 // Start runs the probe indefinitely, unless cancelled, at the configured interval.
-// Probe metrics will be sent via the metricChan at the end of the probe run.
+// Every target runs on its own schedule, starting at a random offset within its interval
+// and with a jittered interval, so that targets sharing a backend are not probed in bursts.
+// A scheduled run is skipped if the previous run of the target is still in flight.
+// Probe metrics will be sent via the metricChan at the end of each target run.
 // This is a required method to implement the cloudprober.Probes.Probe interface.
 // Arguments:
 //	- ctx: context provided for cancelling probe.
 //	- metricChan: bidirectional channel used for sending metrics to be surfaced.
 //		- Must be bidirectional to satisfy cloudprober.Probes.Probe interface.
 func (p *Probe) Start(ctx context.Context, metricChan chan *cpmetrics.EventMetrics) {
-	ticker := time.NewTicker(p.interval())
+	var wg sync.WaitGroup
+	for _, t := range p.targets {
+		wg.Add(1)
+		t := t
+		go func() {
+			defer wg.Done()
+			p.scheduleTarget(ctx, t, metricChan)
+		}()
+	}
+	wg.Wait()
+	p.runs.Wait()
+	p.releaseLeases()
+	p.stopAlerting()
+}
 
+// scheduleTarget launches the runs of the probe against a target on the schedule of the target
+// until the context is cancelled.
+func (p *Probe) scheduleTarget(ctx context.Context, target *target.Target, metricChan chan<- *cpmetrics.EventMetrics) {
+	s := p.schedules[target]
+	timer := time.NewTimer(s.Offset())
+	defer timer.Stop()
 	for {
 		select {
 		case <-ctx.Done():
-			ticker.Stop()
 			return
-		case <-ticker.C:
-			p.runProbe(ctx, metricChan)
+		case <-timer.C:
+			p.launch(ctx, target, metricChan)
+			timer.Reset(s.Next())
 		}
 	}
 }
 
+// launch starts a run of the probe against a target, unless the previous run of the target is still
+// in flight, in which case the run is skipped and counted in the skipped runs metric of the target.
+// Arguments:
+//	- ctx: pass context to allow for complete cancellation of the probe.
+//	- target: the target to be probed.
+//	- metricChan: pass the metrics channel for surfacing metrics to Cloudprober.
+// Returns:
+//	- bool: returns true if the run was started, false if it was skipped.
+func (p *Probe) launch(ctx context.Context, target *target.Target, metricChan chan<- *cpmetrics.EventMetrics) bool {
+	select {
+	case p.tokens[target] <- struct{}{}:
+	default:
+		target.LatencyMetrics.SkippedRuns.Metric(skippedRuns).(*cpmetrics.AtomicInt).Inc()
+		p.logger.Warningf("Target %q: skipped a run as the previous run is still in flight.", target.Target.GetName())
+		return false
+	}
+	p.runs.Add(1)
+	go func() {
+		defer p.runs.Done()
+		defer func() { <-p.tokens[target] }()
+		p.runTarget(ctx, target, metricChan)
+	}()
+	return true
+}
+
 // reportMetrics sends the metrics recorded in the current probe run to Cloudprober.
 // Arguments:
 //	- run: metrics from a probe run on a target.
@@ -131,52 +402,558 @@ func reportMetrics(run *metrics.Metrics, metricChan chan<- *cpmetrics.EventMetri
 			metricChan <- m
 		}
 	}
+
+	for _, call := range run.APIAttempts {
+		for _, m := range call {
+			m.Timestamp = time.Now()
+			metricChan <- m
+		}
+	}
+
+	for _, check := range run.ConsistencyLag {
+		for _, m := range check {
+			m.Timestamp = time.Now()
+			metricChan <- m
+		}
+	}
+
+	for _, m := range run.Durability {
+		m.Timestamp = time.Now()
+		metricChan <- m
+	}
+
+	run.SkippedRuns.Timestamp = time.Now()
+	metricChan <- run.SkippedRuns
+
+	run.Paused.Timestamp = time.Now()
+	metricChan <- run.Paused
+
+	run.ForeignFiles.Timestamp = time.Now()
+	metricChan <- run.ForeignFiles
+
+	for _, m := range run.SLO {
+		m.Timestamp = time.Now()
+		metricChan <- m
+	}
+}
+
+// ensureClient creates the storage client and the locker shared by all targets if they do not exist yet.
+func (p *Probe) ensureClient(ctx context.Context) error {
+	p.clientMu.Lock()
+	defer p.clientMu.Unlock()
+	if p.client == nil {
+		client, err := storage.NewClient(ctx)
+		if err != nil {
+			return fmt.Errorf("storage.NewClient() failed: %w", err)
+		}
+		p.client = stiface.AdaptClient(client)
+	}
+	if p.locker == nil && p.config.Lease != nil {
+		p.locker = p.newLocker()
+	}
+	return nil
 }
 
-// runProbe runs the probe against each target, collects metrics on probe run
-// and surface metrics to Cloudprober.
+// clientFor returns the storage client used for the API calls made to the target,
+// limited by the QPS limits of the target and of its backend. API calls that failed with
+// a transient error are retried as configured, and every attempt counts against the limits.
+// If the run history is configured, the objects accessed through the client are recorded.
+func (p *Probe) clientFor(target *target.Target) stiface.Client {
+	limited := ratelimit.NewClient(p.client, p.targetLimiters[target], p.backendLimiters[target.Target.GetTargetSystem()])
+	client := retry.NewClient(limited, p.retryPolicies, target)
+	if files, ok := p.files[target]; ok {
+		return history.NewClient(client, files)
+	}
+	return client
+}
+
+// runTarget runs the probe once against a target owned by this instance, once a slot of the worker pool
+// is free, and surfaces the metrics of the target to Cloudprober. The run times out after one interval of the target.
+// During maintenance the target is not probed, or its result is reported with the maintenance exit status,
+// as configured by the maintenance mode of the target.
 // Arguments:
 //	- ctx: pass context to allow for complete cancellation of the probe.
+//	- target: the target to be probed.
 //	- metricChan: pass the metrics channel for surfacing metrics to Cloudprober.
-func (p *Probe) runProbe(ctx context.Context, metricChan chan<- *cpmetrics.EventMetrics) {
-	var wg sync.WaitGroup
-	for _, t := range p.targets {
-		wg.Add(1)
-		t := t
-		go func() {
-			defer wg.Done()
-			if t.LatencyMetrics == nil {
-				lm, err := metrics.NewMetrics(p.config, t.Target)
-				if err != nil {
-					p.logger.Errorf("NewMetrics(%v) failed: %v", t.Target, err)
-					return
-				}
-				t.LatencyMetrics = lm
-			}
+func (p *Probe) runTarget(ctx context.Context, target *target.Target, metricChan chan<- *cpmetrics.EventMetrics) {
+	if owner, ok := p.owner(target); !ok {
+		p.disown(ctx, target, owner)
+		return
+	}
+	state := p.maintenanceState(target, time.Now())
+	inMaintenance := state.GetPaused() || state.GetInMaintenanceWindow()
+	target.LatencyMetrics.Paused = metrics.NewPausedGauge(target.Target, inMaintenance)
+	if inMaintenance && target.Target.GetMaintenanceMode() != probepb.Target_MARK {
+		p.logger.Infof("Target %q: skipped a run during maintenance.", target.Target.GetName())
+		reportMetrics(target.LatencyMetrics, metricChan)
+		return
+	}
+	release, err := p.acquireSlot(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			p.logger.Errorf("%v", err)
+		}
+		return
+	}
+	defer release()
+
+	if _, err := p.probeTarget(ctx, target, inMaintenance, nil); err != nil {
+		if inMaintenance {
+			p.logger.Warningf("Target %q: run during maintenance failed: %v", target.Target.GetName(), err)
+		} else {
+			p.logger.Errorf("%v", err)
+		}
+	}
+	p.updateSLOGauges(target, time.Now())
+	reportMetrics(target.LatencyMetrics, metricChan)
+}
 
-			probeCtx, _ := context.WithDeadline(ctx, time.Now().Add(p.interval()))
-			// TODO(evanSpendlove): Refactor to use closure func from metrics.go in metrics PR.
-			start := time.Now()
-			status, err := p.runProbeForTarget(probeCtx, t)
-			if err != nil {
-				p.logger.Errorf(err.Error())
+// acquireSlot waits for a free slot of the worker pool and creates the storage client if it does not exist yet.
+// Returns:
+//	- release: returns the function releasing the slot.
+//	- err: returns the error of the context if it is done before a slot is free, or an error creating the client.
+func (p *Probe) acquireSlot(ctx context.Context) (func(), error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case p.slots <- struct{}{}:
+	}
+	if err := p.ensureClient(ctx); err != nil {
+		<-p.slots
+		return nil, err
+	}
+	return func() { <-p.slots }, nil
+}
+
+// probeTarget runs the probe once against a target and records the latency of the run,
+// and its result in the run history if configured.
+// The run times out after one interval of the target. A run during maintenance is
+// recorded with the maintenance exit status, but the result streamed is the status of the run.
+// An on-demand run, which is streamed, does not count towards the SLO of the target.
+// Arguments:
+//	- ctx: pass context to allow for complete cancellation of the probe.
+//	- target: the target to be probed.
+//	- inMaintenance: whether the target is paused or in one of its maintenance windows.
+//	- report: streams the result of every operation of the run, nil if the run is not streamed.
+// Returns:
+//	- status: returns the exit status recorded for the run.
+//	- err: returns an error if one occurred during the probe run.
+func (p *Probe) probeTarget(ctx context.Context, target *target.Target, inMaintenance bool, report reporter) (metrics.ExitStatus, error) {
+	probeCtx, cancel := context.WithDeadline(ctx, time.Now().Add(p.targetInterval(target)))
+	defer cancel()
+	onDemand := report != nil
+	// Collect the result of every operation of the run for the run history.
+	var ops []*probepb.RunProbeResult
+	if files, ok := p.files[target]; ok {
+		files.Reset()
+		stream := report
+		report = func(result *probepb.RunProbeResult) {
+			if !result.GetFinal() {
+				ops = append(ops, result)
+			}
+			if stream != nil {
+				stream(result)
 			}
+		}
+	}
+	// TODO(evanSpendlove): Refactor to use closure func from metrics.go in metrics PR.
+	start := time.Now()
+	status, err := p.runProbeForTarget(probeCtx, target, report)
+	report.send(metrics.ProbeOpName[metrics.TotalProbeRun], status, err, start, true)
+	if status == metrics.LeaseNotHeld {
+		// The target is probed by the instance holding its lease, which records the run and alerts on it.
+		p.resolveAlerts(ctx, target)
+		return status, err
+	}
+	if inMaintenance {
+		status = metrics.Maintenance
+	}
 
-			t.LatencyMetrics.ProbeOpLatency[metrics.TotalProbeRun][status].Metric(probeLatency).AddFloat64(time.Now().Sub(start).Seconds())
-			reportMetrics(t.LatencyMetrics, metricChan)
-		}()
+	latency := time.Now().Sub(start)
+	target.LatencyMetrics.ProbeOpLatency[metrics.TotalProbeRun][status].Metric(probeLatency).AddFloat64(latency.Seconds())
+	// Runs during maintenance do not count against the SLO and do not fire or resolve alerts.
+	if !inMaintenance {
+		p.observe(ctx, target, status, err, latency, onDemand)
 	}
-	wg.Wait()
+	if p.history != nil {
+		p.recordRun(target, start, start.Add(latency), status, err, ops, onDemand)
+	}
+	return status, err
+}
+
+// recordRun records the result of a run of a target in the run history.
+// A run that cannot be recorded is logged, as the history must not fail the probe.
+func (p *Probe) recordRun(target *target.Target, start, end time.Time, status metrics.ExitStatus, err error, ops []*probepb.RunProbeResult, onDemand bool) {
+	run := &probepb.RunRecord{
+		Target:      target.Target,
+		StartUnixMs: start.UnixNano() / int64(time.Millisecond),
+		EndUnixMs:   end.UnixNano() / int64(time.Millisecond),
+		Status:      metrics.ExitStatusName[status],
+		Operations:  ops,
+		Files:       p.files[target].Names(),
+		OnDemand:    onDemand,
+	}
+	if err != nil {
+		run.Error = err.Error()
+	}
+	if err := p.history.Append(run); err != nil {
+		p.logger.Warningf("Target %q: failed to record the run in the run history: %v", target.Target.GetName(), err)
+	}
+}
+
+// observe records a scheduled run of a target in its SLO windows and evaluates the alert rules of the target.
+// A notification that cannot be sent is logged, and sent again after the next run of the target.
+func (p *Probe) observe(ctx context.Context, target *target.Target, status metrics.ExitStatus, err error, latency time.Duration, onDemand bool) {
+	now := time.Now()
+	obs := alert.Observation{Status: status, Err: err}
+	if tracker, ok := p.slos[target]; ok {
+		if !onDemand {
+			tracker.Record(now, status == metrics.Success, latency)
+		}
+		obs.Indicators = tracker.Indicators(now)
+	}
+	if p.alerts == nil {
+		return
+	}
+	p.notify(ctx, p.alerts.Evaluate(target.Target, obs, now))
+}
+
+// notify sends an alert notification in the background, so that the run waiting on the webhook
+// does not hold the worker slot and the run token of its target. A notification that cannot be sent
+// is logged, and sent again after the next run of the target.
+func (p *Probe) notify(ctx context.Context, n *alert.Notification) {
+	if n == nil {
+		return
+	}
+	p.sends.Add(1)
+	go func() {
+		defer p.sends.Done()
+		if err := p.alerts.Send(ctx, n); err != nil {
+			p.logger.Warningf("Alerting failed: %v", err)
+		}
+	}()
+}
+
+// resolveAlerts resolves the firing alerts of a target this instance gives up, as the instance taking
+// it over starts without them and fires them again if they still hold.
+func (p *Probe) resolveAlerts(ctx context.Context, target *target.Target) {
+	if p.alerts == nil {
+		return
+	}
+	p.notify(ctx, p.alerts.Resolve(target.Target, time.Now()))
+}
+
+// rebuildSLO replaces the runs counted in the SLO windows of a target with its scheduled runs
+// in the run history, so that the indicators survive a restart of the instance and a takeover
+// of the target by another instance sharing the history directory. The runs during maintenance
+// are not counted, as when they are recorded. If the history is not configured or cannot be read,
+// the windows are kept.
+func (p *Probe) rebuildSLO(target *target.Target, now time.Time) {
+	tracker, ok := p.slos[target]
+	if !ok || p.history == nil {
+		return
+	}
+	records, err := p.history.Query(target.Target, now.Add(-tracker.Longest()), now)
+	if err != nil {
+		p.logger.Warningf("Target %q: failed to rebuild the SLO windows from the run history: %v", target.Target.GetName(), err)
+		return
+	}
+	var runs []slo.Run
+	for _, r := range records {
+		if r.GetOnDemand() || r.GetStatus() == metrics.ExitStatusName[metrics.Maintenance] {
+			continue
+		}
+		success := r.GetStatus() == metrics.ExitStatusName[metrics.Success]
+		runs = append(runs, slo.Run{
+			End:     time.Unix(0, r.GetEndUnixMs()*int64(time.Millisecond)),
+			Success: success,
+			Latency: time.Duration(r.GetEndUnixMs()-r.GetStartUnixMs()) * time.Millisecond,
+		})
+	}
+	tracker.Rebuild(runs)
+}
+
+// updateSLOGauges replaces the SLO gauges of a target with its service level indicators at the time given.
+func (p *Probe) updateSLOGauges(target *target.Target, now time.Time) {
+	tracker, ok := p.slos[target]
+	if !ok {
+		return
+	}
+	var gauges []*cpmetrics.EventMetrics
+	for _, sli := range tracker.Indicators(now) {
+		gauges = append(gauges, metrics.NewSLOGauge(target.Target, slo.WindowName(sli.GetWindowSec()), sli))
+	}
+	target.LatencyMetrics.SLO = gauges
+}
+
+// defaultInstanceID returns the name identifying this instance if none is configured, <hostname>_<pid>.
+func defaultInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s_%d", hostname, os.Getpid())
+}
+
+// contains reports whether the list of names contains the name given.
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// owner returns the shard owning the target and whether it is this instance.
+// Every instance owns every target if sharding is not configured.
+func (p *Probe) owner(target *target.Target) (string, bool) {
+	p.shardMu.RLock()
+	defer p.shardMu.RUnlock()
+	if p.ring == nil {
+		return "", true
+	}
+	owner := p.ring.Owner(shard.Key(target.Target))
+	return owner, owner == p.instance
+}
+
+// disown stops probing a target that moved to another shard. Its lease is released so that
+// the new owner can take over immediately, its firing alerts are resolved, and it is bootstrapped
+// again if it moves back.
+func (p *Probe) disown(ctx context.Context, target *target.Target, owner string) {
+	if !target.Bootstrapped {
+		return
+	}
+	p.logger.Infof("Target %q moved to shard %q.", target.Target.GetName(), owner)
+	target.Bootstrapped = false
+	p.resolveAlerts(ctx, target)
+	if p.locker != nil {
+		if err := p.locker.Release(ctx, target); err != nil {
+			p.logger.Errorf("%v", err)
+		}
+	}
+}
+
+// newLocker returns the locker of the leases of the targets, as configured by the lease config.
+// The holder defaults to the shard instance or <hostname>_<pid> and the duration to three of the longest probing intervals.
+func (p *Probe) newLocker() lease.Locker {
+	conf := p.config.GetLease()
+	holder := conf.GetHolder()
+	if holder == "" {
+		holder = p.config.GetShard().GetInstance()
+	}
+	if holder == "" {
+		holder = defaultInstanceID()
+	}
+	duration := 3 * p.maxInterval()
+	if sec := conf.GetDurationSec(); sec > 0 {
+		duration = time.Duration(sec) * time.Second
+	}
+	if conf.GetDir() != "" {
+		return lease.NewFileLocker(conf.GetDir(), holder, duration)
+	}
+	return lease.NewBucketLocker(p.client, holder, duration)
+}
+
+// releaseLeases releases the leases held by this instance, so that a standby instance can take over
+// the targets without waiting for the leases to expire.
+func (p *Probe) releaseLeases() {
+	if p.locker == nil {
+		return
+	}
+	// The probe context is already cancelled when the leases are released.
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout())
+	defer cancel()
+	for _, t := range p.targets {
+		if _, ok := p.owner(t); !ok {
+			continue
+		}
+		if err := p.locker.Release(ctx, t); err != nil {
+			p.logger.Errorf("%v", err)
+		}
+	}
+}
+
+// stopAlerting resolves the firing alerts of the targets owned by this instance once it stops probing them,
+// and waits for the notifications being sent.
+func (p *Probe) stopAlerting() {
+	if p.alerts == nil {
+		return
+	}
+	// The notifications sent with the probe context fail once it is cancelled, so their alerts are
+	// resolved once they completed.
+	p.sends.Wait()
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout())
+	defer cancel()
+	for _, t := range p.targets {
+		if _, ok := p.owner(t); ok {
+			p.resolveAlerts(ctx, t)
+		}
+	}
+	p.sends.Wait()
+}
+
+// journalStores returns the stores holding copies of the journals of the targets:
+// the NIL file of the target bucket, accessed with the client given, and, if a journal
+// directory is configured, a file in that directory.
+func (p *Probe) journalStores(client stiface.Client) []journal.Store {
+	stores := []journal.Store{journal.NewBucketStore(client)}
+	if dir := p.config.GetJournalDir(); dir != "" {
+		stores = append(stores, journal.NewLocalStore(dir))
+	}
+	return stores
+}
+
+// Names of the operations of a probe run that are streamed to on-demand runs but have no probe operation metric label.
+const (
+	acquireLeaseOp = "acquire_lease"
+	bootstrapOp    = "bootstrap"
+	remediateOp    = "remediate"
+)
+
+// reporter streams the result of every operation of an on-demand probe run. A nil reporter discards the results.
+type reporter func(*probepb.RunProbeResult)
+
+// send streams the result of an operation that started at the time given.
+// Arguments:
+//	- op: the name of the operation.
+//	- status: the exit status of the operation.
+//	- err: the error the operation failed with, nil on success.
+//	- start: the time the operation started.
+//	- final: whether the operation is the whole run, which is the last result streamed.
+func (r reporter) send(op string, status metrics.ExitStatus, err error, start time.Time, final bool) {
+	if r == nil {
+		return
+	}
+	result := &probepb.RunProbeResult{
+		Operation:  op,
+		Status:     metrics.ExitStatusName[status],
+		LatencySec: time.Now().Sub(start).Seconds(),
+		Final:      final,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	r(result)
+}
+
+// writeJournal writes the journal of the target to every store after a change to the bucket.
+// The journal is written even if the change failed, as a file may have been created or deleted
+// before a later check of the change failed.
+// Arguments:
+//	- ctx: the context of the probe run.
+//	- target: the target whose journal is written.
+//	- stores: the stores the journal is written to.
+//	- opErr: the error the change failed with, nil if it succeeded.
+// Returns:
+//	- err: returns opErr if the change failed, otherwise the error of writing the journal.
+func writeJournal(ctx context.Context, target *target.Target, stores []journal.Store, opErr error) error {
+	if err := journal.WriteAll(ctx, target, stores); opErr == nil {
+		return err
+	}
+	return opErr
 }
 
 // runProbeForTarget runs the Hermes probing algorithm on a single target.
 // Arguments:
 //	- ctx: pass context to allow for cancellation of the probe.
 //	- target: the target to be probed
+//	- report: streams the result of every operation of the run, nil if the run is not streamed.
 // Returns:
 //	- status: returns the exit status of the probe run.
-//	- error: returns an error if one occurred during the probe run.
-func (p *Probe) runProbeForTarget(ctx context.Context, target *target.Target) (metrics.ExitStatus, error) {
-	// TODO(evanSpendlove): Add implementation of runProbeForTarget, i.e. Hermes probing algorithm.
-	return metrics.Success, nil
+//	- error: returns an error if one occurred during the probe run. A failed operation on the rotating files
+//	  takes precedence over a failed durability check.
+func (p *Probe) runProbeForTarget(ctx context.Context, target *target.Target, report reporter) (metrics.ExitStatus, error) {
+	if p.locker != nil {
+		start := time.Now()
+		held, err := p.locker.Acquire(ctx, target)
+		if err != nil {
+			report.send(acquireLeaseOp, metrics.StatusOf(err), err, start, false)
+			return metrics.StatusOf(err), err
+		}
+		if !held {
+			// The instance holding the lease changes the journal, so the target
+			// is bootstrapped again once this instance takes over the lease.
+			target.Bootstrapped = false
+			report.send(acquireLeaseOp, metrics.LeaseNotHeld, nil, start, false)
+			return metrics.LeaseNotHeld, nil
+		}
+		report.send(acquireLeaseOp, metrics.Success, nil, start, false)
+	}
+	client := p.clientFor(target)
+	stores := p.journalStores(client)
+	if !target.Bootstrapped {
+		start := time.Now()
+		err := bootstrap.Bootstrap(ctx, target, client, stores, p.logger)
+		report.send(bootstrapOp, metrics.StatusOf(err), err, start, false)
+		if err != nil {
+			return metrics.StatusOf(err), err
+		}
+		target.Bootstrapped = true
+		p.rebuildSLO(target, time.Now())
+	}
+	start := time.Now()
+	result, err := checknil.CheckNil(ctx, target, client, p.logger)
+	report.send(metrics.ProbeOpName[metrics.CheckNil], metrics.StatusOf(err), err, start, false)
+	if result != nil && (len(result.Unknown) > 0 || len(result.Aborted) > 0) {
+		start := time.Now()
+		_, err := checknil.Remediate(ctx, target, client, p.logger, result.Unknown)
+		if _, abortErr := checknil.DeleteAborted(ctx, target, client, p.logger, result.Aborted); err == nil {
+			err = abortErr
+		}
+		report.send(remediateOp, metrics.StatusOf(err), err, start, false)
+		if err != nil {
+			p.logger.Errorf("%v", err)
+		}
+	}
+	if err != nil {
+		return metrics.StatusOf(err), err
+	}
+	// A failed durability check does not affect the rotating files, so they are probed regardless
+	// and the durability status is returned if they succeed.
+	start = time.Now()
+	durabilityErr := durability.Verify(ctx, target, client, stores, p.logger)
+	report.send(metrics.ProbeOpName[metrics.VerifyDurability], metrics.StatusOf(durabilityErr), durabilityErr, start, false)
+	fileID, err := deletefile.PickFileToDelete(target)
+	if err != nil {
+		report.send(metrics.ProbeOpName[metrics.DeleteFile], metrics.StatusOf(err), err, time.Now(), false)
+		return metrics.StatusOf(err), err
+	}
+	// The file deleted is created again with new contents and read back, and the journal is written after each change.
+	// The abort of an incomplete upload is only probed in RESUMABLE upload mode.
+	resumable := target.Target.GetUploadMode() == probepb.Target_RESUMABLE
+	ops := []struct {
+		op   metrics.ProbeOperation
+		skip bool
+		run  func() error
+	}{
+		{metrics.DeleteFile, false, func() error {
+			_, err := deletefile.DeleteFile(ctx, fileID, target, client, p.logger)
+			return writeJournal(ctx, target, stores, err)
+		}},
+		{metrics.CreateFile, false, func() error {
+			err := create.CreateFile(ctx, target, fileID, bootstrap.FileSize(target), client, p.logger)
+			return writeJournal(ctx, target, stores, err)
+		}},
+		{metrics.AbortUpload, !resumable, func() error {
+			return create.AbortUpload(ctx, target, fileID, client, p.logger)
+		}},
+		{metrics.ReadFile, false, func() error {
+			return read.ReadFile(ctx, target, fileID, bootstrap.FileSize(target), client, p.logger)
+		}},
+	}
+	for _, o := range ops {
+		if o.skip {
+			continue
+		}
+		start := time.Now()
+		err := o.run()
+		status := metrics.StatusOf(err)
+		target.LatencyMetrics.ProbeOpLatency[o.op][status].Metric(probeLatency).AddFloat64(time.Now().Sub(start).Seconds())
+		report.send(metrics.ProbeOpName[o.op], status, err, start, false)
+		if err != nil {
+			return status, err
+		}
+	}
+	return metrics.StatusOf(durabilityErr), durabilityErr
 }
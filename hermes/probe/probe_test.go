@@ -22,14 +22,27 @@
 package probe
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/cloudprober/logger"
 	"github.com/google/cloudprober/probes/options"
+	"github.com/googleinterns/step224-2020/hermes/probe/alert"
+	"github.com/googleinterns/step224-2020/hermes/probe/fakegcs"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/probetest"
 
-	metricpb "github.com/google/cloudprober/metrics/proto"
+	cpmetrics "github.com/google/cloudprober/metrics"
 	probes_configpb "github.com/google/cloudprober/probes/proto"
 	monitorpb "github.com/googleinterns/step224-2020/config/proto"
 )
@@ -47,30 +60,7 @@ func GenTestConfig(name string) (*probes_configpb.ProbeDef, *monitorpb.HermesPro
 		Type: probes_configpb.ProbeDef_EXTENSION.Enum(),
 	}
 
-	hermesExtension := &monitorpb.HermesProbeDef{
-		ProbeName: proto.String(name),
-		Targets: []*monitorpb.Target{
-			&monitorpb.Target{
-				Name:                   "hermes",
-				TargetSystem:           monitorpb.Target_GOOGLE_CLOUD_STORAGE,
-				TotalSpaceAllocatedMib: int64(100),
-				BucketName:             "test_bucket_5",
-			},
-		},
-		TargetSystem: monitorpb.HermesProbeDef_GCS.Enum(),
-		IntervalSec:  proto.Int32(3600),
-		TimeoutSec:   proto.Int32(60),
-		ProbeLatencyDistribution: &metricpb.Dist{
-			Buckets: &metricpb.Dist_ExplicitBuckets{
-				ExplicitBuckets: "0.1, 0.2, 0.4, 0.6 0.8, 1.6, 3.2, 6.4, 12.8, 1000",
-			},
-		},
-		ApiCallLatencyDistribution: &metricpb.Dist{
-			Buckets: &metricpb.Dist_ExplicitBuckets{
-				ExplicitBuckets: "0.000000002, 0.000000004, 0.000000008, 0.000000016, 0.00000032, 0.000000064, 0.000000128, 100",
-			},
-		},
-	}
+	hermesExtension := probetest.Config(name, probetest.TargetConfig("test_bucket_5"))
 	proto.SetExtension(probeDef, monitorpb.E_HermesProbeDef_HermesProbeDef, hermesExtension)
 	return probeDef, hermesExtension
 }
@@ -109,4 +99,410 @@ func TestInit(t *testing.T) {
 	}
 }
 
+func TestRunProbeForTarget(t *testing.T) {
+	name := "testProbe2"
+	ctx := context.Background()
+	mp := &Probe{}
+	_, cfg := GenTestConfig(name)
+	cfg.GetTargets()[0].BootstrapFilesPerSec = 1000
+	if err := mp.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	mp.client = fakegcs.NewClient()
+	bucket := cfg.GetTargets()[0].GetBucketName()
+	if err := mp.client.Bucket(bucket).Create(ctx, bucket, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+
+	target := mp.targets[0]
+	var err error
+	if target.LatencyMetrics, err = metrics.NewMetrics(mp.config, target.Target); err != nil {
+		t.Fatalf("metrics.NewMetrics(): %v", err)
+	}
+	if status, err := mp.runProbeForTarget(ctx, target, nil); status != metrics.Success {
+		t.Errorf("runProbeForTarget() on an empty bucket = %q, %v, want %q", metrics.ExitStatusName[status], err, metrics.ExitStatusName[metrics.Success])
+	}
+	if got, want := len(target.Journal.Filenames), 50; got != want {
+		t.Errorf("runProbeForTarget() bootstrapped %d files, want %d", got, want)
+	}
+
+	// Files not created by Hermes are reported, but do not stop the run.
+	w := mp.client.Bucket(bucket).Object("notes.txt").NewWriter(ctx)
+	if _, err := w.Write([]byte("not a Hermes file")); err != nil {
+		t.Fatalf("failed to write a foreign file: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to write a foreign file: %v", err)
+	}
+	if status, err := mp.runProbeForTarget(ctx, target, nil); status != metrics.Success {
+		t.Errorf("runProbeForTarget() with a foreign file = %q, %v, want %q", metrics.ExitStatusName[status], err, metrics.ExitStatusName[metrics.Success])
+	}
+
+	if err := mp.client.Bucket(bucket).Object(target.Journal.Filenames[1]).Delete(ctx); err != nil {
+		t.Fatalf("failed to delete file 1: %v", err)
+	}
+	if status, _ := mp.runProbeForTarget(ctx, target, nil); status != metrics.FileMissing {
+		t.Errorf("runProbeForTarget() with a missing file = %q, want %q", metrics.ExitStatusName[status], metrics.ExitStatusName[metrics.FileMissing])
+	}
+}
+
+func TestRunProbeForTargetConsistencyLag(t *testing.T) {
+	name := "testProbeConsistencyLag"
+	ctx := context.Background()
+	mp := &Probe{}
+	_, cfg := GenTestConfig(name)
+	cfg.GetTargets()[0].BootstrapFilesPerSec = 1000
+	if err := mp.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	client := fakegcs.NewClient()
+	mp.client = client
+	bucket := cfg.GetTargets()[0].GetBucketName()
+	if err := client.Bucket(bucket).Create(ctx, bucket, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	target := mp.targets[0]
+	if status, err := mp.runProbeForTarget(ctx, target, nil); status != metrics.Success {
+		t.Fatalf("runProbeForTarget() on an empty bucket = %q, %v, want %q", metrics.ExitStatusName[status], err, metrics.ExitStatusName[metrics.Success])
+	}
+
+	// Listings take longer than the lag SLO, so the file deleted by the run is unlisted too late.
+	mp.client = probetest.NewSlowListClient(client, 20*time.Millisecond)
+	target.Target.Consistency = &monitorpb.Target_ConsistencyConfig{InitialBackoffMs: 1, MaxBackoffMs: 1, TimeoutSec: 5, LagSloSec: 0.001}
+	if status, err := mp.runProbeForTarget(ctx, target, nil); status != metrics.ConsistencyLagExceeded {
+		t.Fatalf("runProbeForTarget() with slow listings = %q, %v, want %q", metrics.ExitStatusName[status], err, metrics.ExitStatusName[metrics.ConsistencyLagExceeded])
+	}
+
+	// The journal written by the failed run matches the bucket, so the next run passes CheckNil,
+	// including after a restart that loads the journal from the bucket.
+	mp.client = client
+	target.Target.Consistency = nil
+	target.Bootstrapped = false
+	if status, err := mp.runProbeForTarget(ctx, target, nil); status != metrics.Success {
+		t.Errorf("runProbeForTarget() after a run above the lag SLO = %q, %v, want %q", metrics.ExitStatusName[status], err, metrics.ExitStatusName[metrics.Success])
+	}
+}
+
+func TestRunProbeForTargetDurabilityFailure(t *testing.T) {
+	name := "testProbeDurability"
+	ctx := context.Background()
+	mp := &Probe{}
+	_, cfg := GenTestConfig(name)
+	cfg.GetTargets()[0].BootstrapFilesPerSec = 1000
+	if err := mp.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	mp.client = fakegcs.NewClient()
+	bucket := cfg.GetTargets()[0].GetBucketName()
+	if err := mp.client.Bucket(bucket).Create(ctx, bucket, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	target := mp.targets[0]
+	if status, err := mp.runProbeForTarget(ctx, target, nil); status != metrics.Success {
+		t.Fatalf("runProbeForTarget() on an empty bucket = %q, %v, want %q", metrics.ExitStatusName[status], err, metrics.ExitStatusName[metrics.Success])
+	}
+
+	// Permanent file 3 is corrupted and due to be verified.
+	const permanentID = int32(3)
+	w := mp.client.Bucket(bucket).Object(target.Journal.Files[permanentID].GetFilename()).NewWriter(ctx)
+	if _, err := w.Write([]byte("corrupted")); err != nil {
+		t.Fatalf("failed to corrupt file %d: %v", permanentID, err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to corrupt file %d: %v", permanentID, err)
+	}
+	target.Journal.Files[permanentID].LastVerifiedUnixSec = 0
+	before := make(map[int32]string)
+	for id, entry := range target.Journal.Files {
+		before[id] = entry.GetFilename()
+	}
+
+	if status, err := mp.runProbeForTarget(ctx, target, nil); status != metrics.FileCorrupted {
+		t.Fatalf("runProbeForTarget() with a corrupted permanent file = %q, %v, want %q", metrics.ExitStatusName[status], err, metrics.ExitStatusName[metrics.FileCorrupted])
+	}
+	// The rotating file picked by the run is still deleted and created again.
+	var rotated []int32
+	for id, entry := range target.Journal.Files {
+		if entry.GetFilename() != before[id] {
+			rotated = append(rotated, id)
+		}
+	}
+	if len(rotated) != 1 || rotated[0] == permanentID {
+		t.Errorf("runProbeForTarget() with a corrupted permanent file rotated files %v, want one rotating file", rotated)
+	}
+}
+
+func TestRunProbeForTargetLease(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "probe_test")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir() failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	client := fakegcs.NewClient()
+
+	// Two instances share the bucket and the lease directory.
+	var probes []*Probe
+	for _, name := range []string{"active", "standby"} {
+		mp := &Probe{}
+		_, cfg := GenTestConfig(name)
+		cfg.GetTargets()[0].BootstrapFilesPerSec = 1000
+		cfg.Lease = &monitorpb.LeaseConfig{Holder: proto.String(name), Dir: proto.String(dir)}
+		cfg.History = &monitorpb.HistoryConfig{Dir: proto.String(filepath.Join(dir, name))}
+		if err := mp.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+			t.Fatalf("Init() failed: %v", err)
+		}
+		mp.client = client
+		mp.locker = mp.newLocker()
+		if mp.targets[0].LatencyMetrics, err = metrics.NewMetrics(mp.config, mp.targets[0].Target); err != nil {
+			t.Fatalf("metrics.NewMetrics(): %v", err)
+		}
+		probes = append(probes, mp)
+	}
+	bucket := probes[0].targets[0].Target.GetBucketName()
+	if err := client.Bucket(bucket).Create(ctx, bucket, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+
+	active, standby := probes[0], probes[1]
+	if status, err := active.runProbeForTarget(ctx, active.targets[0], nil); status != metrics.Success {
+		t.Errorf("runProbeForTarget() of the active instance = %q, %v, want %q", metrics.ExitStatusName[status], err, metrics.ExitStatusName[metrics.Success])
+	}
+	start := time.Now().Add(-time.Second)
+	if status, err := standby.probeTarget(ctx, standby.targets[0], false, nil); status != metrics.LeaseNotHeld {
+		t.Errorf("probeTarget() of the standby instance = %q, %v, want %q", metrics.ExitStatusName[status], err, metrics.ExitStatusName[metrics.LeaseNotHeld])
+	}
+	// Only the instance holding the lease records the runs of the target.
+	if runs, err := standby.history.Query(standby.targets[0].Target, start, time.Now().Add(time.Second)); err != nil || len(runs) != 0 {
+		t.Errorf("history.Query() of the standby instance = %v, %v, want no runs", runs, err)
+	}
+
+	// The standby takes over once the active instance releases its lease, and loads the journal it wrote.
+	active.releaseLeases()
+	if status, err := standby.runProbeForTarget(ctx, standby.targets[0], nil); status != metrics.Success {
+		t.Errorf("runProbeForTarget() of the standby instance after release = %q, %v, want %q", metrics.ExitStatusName[status], err, metrics.ExitStatusName[metrics.Success])
+	}
+	if got, want := len(standby.targets[0].Journal.Filenames), len(active.targets[0].Journal.Filenames); got != want {
+		t.Errorf("runProbeForTarget() of the standby instance loaded %d files, want %d", got, want)
+	}
+}
+
+func TestRunTargetWorkerPool(t *testing.T) {
+	name := "testProbePool"
+	mp := &Probe{}
+	_, cfg := GenTestConfig(name)
+	cfg.MaxConcurrentTargets = proto.Int32(1)
+	if err := mp.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	mp.client = fakegcs.NewClient()
+
+	// Another target holds the only slot of the pool, so the run waits until it is cancelled.
+	mp.slots <- struct{}{}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	metricChan := make(chan *cpmetrics.EventMetrics, 1000)
+	mp.runTarget(ctx, mp.targets[0], metricChan)
+	if len(metricChan) != 0 {
+		t.Errorf("runTarget() ran a target while the worker pool was full")
+	}
+	if got, want := len(mp.slots), 1; got != want {
+		t.Errorf("runTarget() left %d slots held, want %d", got, want)
+	}
+}
+
+func TestInitSchedule(t *testing.T) {
+	tests := []struct {
+		desc         string
+		intervalSec  int32
+		jitter       float64
+		probeJitter  *float64
+		leaseSec     int32
+		wantErr      bool
+		wantInterval time.Duration
+		wantJitter   float64
+	}{
+		{
+			desc:         "probe interval and default jitter",
+			wantInterval: time.Hour,
+			wantJitter:   defaultIntervalJitter,
+		},
+		{
+			desc:         "probe jitter disabled",
+			probeJitter:  proto.Float64(0),
+			wantInterval: time.Hour,
+			wantJitter:   0,
+		},
+		{
+			desc:         "target overrides",
+			intervalSec:  60,
+			jitter:       0.5,
+			probeJitter:  proto.Float64(0),
+			wantInterval: time.Minute,
+			wantJitter:   0.5,
+		},
+		{
+			desc:        "negative target interval",
+			intervalSec: -1,
+			wantErr:     true,
+		},
+		{
+			desc:    "target jitter out of range",
+			jitter:  1.5,
+			wantErr: true,
+		},
+		{
+			desc:        "lease shorter than a target interval",
+			intervalSec: 7200,
+			leaseSec:    5400,
+			wantErr:     true,
+		},
+		{
+			desc:     "lease shorter than the jittered probe interval plus the timeout",
+			leaseSec: 3900,
+			wantErr:  true,
+		},
+		{
+			desc:         "lease longer than the jittered probe interval plus the timeout",
+			leaseSec:     4200,
+			wantInterval: time.Hour,
+			wantJitter:   defaultIntervalJitter,
+		},
+	}
+
+	for _, tc := range tests {
+		name := "testProbeSchedule"
+		mp := &Probe{}
+		_, cfg := GenTestConfig(name)
+		cfg.IntervalJitter = tc.probeJitter
+		cfg.GetTargets()[0].IntervalSec = tc.intervalSec
+		cfg.GetTargets()[0].IntervalJitter = tc.jitter
+		if tc.leaseSec != 0 {
+			cfg.Lease = &monitorpb.LeaseConfig{DurationSec: proto.Int32(tc.leaseSec)}
+		}
+		err := mp.Init(name, GenOptsFromConfig(t, cfg))
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: Init() = %v, want error %t", tc.desc, err, tc.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		target := mp.targets[0]
+		if got := mp.schedules[target].Interval(); got != tc.wantInterval {
+			t.Errorf("%s: schedule interval = %v, want %v", tc.desc, got, tc.wantInterval)
+		}
+		if got := mp.targetJitter(target); got != tc.wantJitter {
+			t.Errorf("%s: targetJitter() = %v, want %v", tc.desc, got, tc.wantJitter)
+		}
+	}
+}
+
+func TestLaunchSkipsOverlappingRun(t *testing.T) {
+	name := "testProbeLaunch"
+	mp := &Probe{}
+	_, cfg := GenTestConfig(name)
+	cfg.MaxConcurrentTargets = proto.Int32(1)
+	if err := mp.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	mp.client = fakegcs.NewClient()
+	target := mp.targets[0]
+
+	// The only slot of the pool is held, so the first run stays in flight until it is cancelled.
+	mp.slots <- struct{}{}
+	ctx, cancel := context.WithCancel(context.Background())
+	metricChan := make(chan *cpmetrics.EventMetrics, 1000)
+	if !mp.launch(ctx, target, metricChan) {
+		t.Fatalf("launch() skipped the first run of the target")
+	}
+	for i := 0; i < 2; i++ {
+		if mp.launch(ctx, target, metricChan) {
+			t.Errorf("launch() started a run while the previous run was in flight")
+		}
+	}
+	if got, want := target.LatencyMetrics.SkippedRuns.Metric(skippedRuns).(*cpmetrics.AtomicInt).Int64(), int64(2); got != want {
+		t.Errorf("launch() counted %d skipped runs, want %d", got, want)
+	}
+
+	cancel()
+	mp.runs.Wait()
+	<-mp.slots
+	if !mp.launch(context.Background(), target, metricChan) {
+		t.Errorf("launch() skipped a run after the previous run completed")
+	}
+	mp.runs.Wait()
+}
+
+func TestProbeTargetAlerts(t *testing.T) {
+	var mu sync.Mutex
+	var states []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := &alert.Notification{}
+		if err := json.NewDecoder(r.Body).Decode(n); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		states = append(states, n.State)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	name := "testProbeAlerts"
+	mp := &Probe{}
+	_, cfg := GenTestConfig(name)
+	cfg.GetTargets()[0].BootstrapFilesPerSec = 1000
+	cfg.Alerting = &monitorpb.AlertingConfig{
+		WebhookUrl: proto.String(server.URL),
+		Rules:      []*monitorpb.AlertRule{{Name: proto.String("failing"), ConsecutiveFailures: proto.Int32(1)}},
+	}
+	if err := mp.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	mp.client = fakegcs.NewClient()
+	target := mp.targets[0]
+	bucket := target.Target.GetBucketName()
+
+	// The bucket does not exist yet, so the runs fail, but a run during maintenance does not fire alerts.
+	if status, _ := mp.probeTarget(ctx, target, true, nil); status != metrics.Maintenance {
+		t.Fatalf("probeTarget() during maintenance = %q, want %q", metrics.ExitStatusName[status], metrics.ExitStatusName[metrics.Maintenance])
+	}
+	if status, _ := mp.probeTarget(ctx, target, false, nil); status == metrics.Success {
+		t.Fatalf("probeTarget() without a bucket succeeded, want a failure")
+	}
+	// Notifications are sent in the background, the changes made while one is sent are sent together after it.
+	mp.sends.Wait()
+	if err := mp.client.Bucket(bucket).Create(ctx, bucket, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	if status, err := mp.probeTarget(ctx, target, false, nil); status != metrics.Success {
+		t.Fatalf("probeTarget() = %q, %v, want %q", metrics.ExitStatusName[status], err, metrics.ExitStatusName[metrics.Success])
+	}
+	mp.sends.Wait()
+	// The target fails again, then moves to another shard, which resolves its alert.
+	mp.client = fakegcs.NewClient()
+	if status, _ := mp.probeTarget(ctx, target, false, nil); status == metrics.Success {
+		t.Fatalf("probeTarget() without a bucket succeeded, want a failure")
+	}
+	mp.sends.Wait()
+	mp.disown(ctx, target, "other")
+	mp.sends.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	if got, want := fmt.Sprint(states), "[firing resolved firing resolved]"; got != want {
+		t.Errorf("probeTarget() sent notifications %s, want %s", got, want)
+	}
+
+	_, cfg = GenTestConfig(name)
+	cfg.Alerting = &monitorpb.AlertingConfig{
+		WebhookUrl: proto.String(server.URL),
+		Rules:      []*monitorpb.AlertRule{{Name: proto.String("burn"), BurnRate: proto.Float64(10), BurnRateWindowSec: proto.Int64(3600)}},
+	}
+	if err := (&Probe{}).Init(name, GenOptsFromConfig(t, cfg)); err == nil {
+		t.Errorf("Init() with a burn rate rule without an SLO config succeeded, want an error")
+	}
+}
+
 // TODO(evanSpendlove): Add more tests for monitor.go methods.
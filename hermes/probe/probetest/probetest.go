@@ -0,0 +1,130 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package probetest implements the probe configs and targets shared by the tests
+// of the probe packages.
+package probetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/golang/protobuf/proto"
+	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+
+	metricpb "github.com/google/cloudprober/metrics/proto"
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+	journalpb "github.com/googleinterns/step224-2020/hermes/proto"
+)
+
+// latencyBuckets are the bounds of the latency distributions of the test configs in seconds.
+const latencyBuckets = "0.1,0.2,0.4,0.6,0.8,1.6,3.2,6.4,12.8,1000"
+
+// Config generates a test HermesProbeDef, probing the targets given once an hour with a timeout of a minute.
+// Arguments:
+//	- name: the name of the probe.
+//	- targets: the configs of the targets of the probe.
+// Returns:
+//	- cfg: returns the probe config.
+func Config(name string, targets ...*probepb.Target) *probepb.HermesProbeDef {
+	return &probepb.HermesProbeDef{
+		ProbeName:    proto.String(name),
+		Targets:      targets,
+		TargetSystem: probepb.HermesProbeDef_GCS.Enum(),
+		IntervalSec:  proto.Int32(3600),
+		TimeoutSec:   proto.Int32(60),
+		ProbeLatencyDistribution: &metricpb.Dist{
+			Buckets: &metricpb.Dist_ExplicitBuckets{ExplicitBuckets: latencyBuckets},
+		},
+		ApiCallLatencyDistribution: &metricpb.Dist{
+			Buckets: &metricpb.Dist_ExplicitBuckets{ExplicitBuckets: latencyBuckets},
+		},
+	}
+}
+
+// TargetConfig generates the config of a test target named hermes, probing a GCS bucket with 100 MiB allocated.
+// Arguments:
+//	- bucket: the name of the bucket of the target.
+// Returns:
+//	- conf: returns the target config.
+func TargetConfig(bucket string) *probepb.Target {
+	return &probepb.Target{
+		Name:                   "hermes",
+		TargetSystem:           probepb.Target_GOOGLE_CLOUD_STORAGE,
+		TotalSpaceAllocatedMib: 100,
+		BucketName:             bucket,
+	}
+}
+
+// NewTarget generates a target of a test probe, with its metrics initialised and an empty journal.
+// Arguments:
+//	- t: the test, which fails if the metrics cannot be initialised.
+//	- name: the name of the probe.
+//	- conf: the config of the target.
+// Returns:
+//	- target: returns the target.
+func NewTarget(t *testing.T, name string, conf *probepb.Target) *target.Target {
+	t.Helper()
+	m, err := metrics.NewMetrics(Config(name, conf), conf)
+	if err != nil {
+		t.Fatalf("metrics.NewMetrics(): %v", err)
+	}
+	return &target.Target{
+		Target: conf,
+		Journal: &journalpb.StateJournal{
+			Intent:    &journalpb.Intent{},
+			Filenames: make(map[int32]string),
+			Files:     make(map[int32]*journalpb.FileEntry),
+		},
+		LatencyMetrics: m,
+	}
+}
+
+// slowListClient is a client whose bucket listings are delayed.
+type slowListClient struct {
+	stiface.Client
+	delay time.Duration
+}
+
+// slowListBucket is a bucket handle whose listings are delayed.
+type slowListBucket struct {
+	stiface.BucketHandle
+	delay time.Duration
+}
+
+// NewSlowListClient returns a client that waits for the delay given before every listing of a bucket,
+// e.g. to simulate a target that takes longer than the consistency lag SLO to reflect changes.
+// Arguments:
+//	- client: the client the calls are made to.
+//	- delay: the time to wait before each listing.
+// Returns:
+//	- client: returns the client with delayed listings.
+func NewSlowListClient(client stiface.Client, delay time.Duration) stiface.Client {
+	return &slowListClient{Client: client, delay: delay}
+}
+
+// Bucket returns a handle of the bucket whose listings are delayed.
+func (c *slowListClient) Bucket(name string) stiface.BucketHandle {
+	return &slowListBucket{BucketHandle: c.Client.Bucket(name), delay: c.delay}
+}
+
+// Objects waits for the delay, then lists the objects of the bucket.
+func (b *slowListBucket) Objects(ctx context.Context, q *storage.Query) stiface.ObjectIterator {
+	time.Sleep(b.delay)
+	return b.BucketHandle.Objects(ctx, q)
+}
@@ -0,0 +1,308 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Client implements a storage client whose API calls are limited by a set of limiters.
+
+package ratelimit
+
+import (
+	"context"
+
+	"cloud.google.com/go/storage"
+	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+)
+
+// NewClient returns a storage client that waits for every limiter given before each API call.
+// Calls that only build handles, e.g. Bucket and Object, are not limited. A write is limited once
+// for every chunk it uploads and a listing once for every page it fetches, and the error of waiting
+// for the limiters is returned by the writer or the iterator.
+// Arguments:
+//	- client: the storage client making the API calls.
+//	- limiters: the limiters of the API calls, nil limiters are ignored.
+// Returns:
+//	- client: returns the limited client, or the client given if no limiters are set.
+func NewClient(client stiface.Client, limiters ...*Limiter) stiface.Client {
+	var set []*Limiter
+	for _, l := range limiters {
+		if l != nil {
+			set = append(set, l)
+		}
+	}
+	if len(set) == 0 {
+		return client
+	}
+	return &limitedClient{Client: client, limiters: set}
+}
+
+// defaultChunkSize is the chunk size of a storage.Writer whose chunk size is not set.
+const defaultChunkSize = 16 * 1024 * 1024
+
+type limitedClient struct {
+	stiface.Client
+	limiters []*Limiter
+}
+
+func (c *limitedClient) Bucket(name string) stiface.BucketHandle {
+	return &limitedBucket{BucketHandle: c.Client.Bucket(name), limiters: c.limiters}
+}
+
+type limitedBucket struct {
+	stiface.BucketHandle
+	limiters []*Limiter
+}
+
+func (b *limitedBucket) Create(ctx context.Context, projectID string, attrs *storage.BucketAttrs) error {
+	if err := waitAll(ctx, b.limiters); err != nil {
+		return err
+	}
+	return b.BucketHandle.Create(ctx, projectID, attrs)
+}
+
+func (b *limitedBucket) Delete(ctx context.Context) error {
+	if err := waitAll(ctx, b.limiters); err != nil {
+		return err
+	}
+	return b.BucketHandle.Delete(ctx)
+}
+
+func (b *limitedBucket) Attrs(ctx context.Context) (*storage.BucketAttrs, error) {
+	if err := waitAll(ctx, b.limiters); err != nil {
+		return nil, err
+	}
+	return b.BucketHandle.Attrs(ctx)
+}
+
+func (b *limitedBucket) Update(ctx context.Context, attrs storage.BucketAttrsToUpdate) (*storage.BucketAttrs, error) {
+	if err := waitAll(ctx, b.limiters); err != nil {
+		return nil, err
+	}
+	return b.BucketHandle.Update(ctx, attrs)
+}
+
+func (b *limitedBucket) If(conds storage.BucketConditions) stiface.BucketHandle {
+	return &limitedBucket{BucketHandle: b.BucketHandle.If(conds), limiters: b.limiters}
+}
+
+func (b *limitedBucket) UserProject(projectID string) stiface.BucketHandle {
+	return &limitedBucket{BucketHandle: b.BucketHandle.UserProject(projectID), limiters: b.limiters}
+}
+
+func (b *limitedBucket) Objects(ctx context.Context, q *storage.Query) stiface.ObjectIterator {
+	return &limitedIterator{ObjectIterator: b.BucketHandle.Objects(ctx, q), ctx: ctx, limiters: b.limiters, fetch: true}
+}
+
+// limitedIterator waits for the limiters before every page the iterator fetches.
+// A page is known to follow once the iterator has fetched the page before it and holds its token,
+// so it is waited for on the call to Next after that.
+type limitedIterator struct {
+	stiface.ObjectIterator
+	ctx      context.Context
+	limiters []*Limiter
+	// token is the token of the next page, as of the last call to Next.
+	token string
+	// fetch is true if a page is to be fetched that has not been waited for.
+	fetch bool
+	// err is the error of waiting for the limiters, returned by every later call to Next.
+	err error
+}
+
+func (it *limitedIterator) Next() (*storage.ObjectAttrs, error) {
+	if it.err != nil {
+		return nil, it.err
+	}
+	if it.fetch {
+		if err := waitAll(it.ctx, it.limiters); err != nil {
+			it.err = err
+			return nil, err
+		}
+		it.fetch = false
+	}
+	attrs, err := it.ObjectIterator.Next()
+	if info := it.ObjectIterator.PageInfo(); info != nil && info.Token != it.token {
+		it.token = info.Token
+		it.fetch = info.Token != ""
+	}
+	return attrs, err
+}
+
+func (b *limitedBucket) Object(name string) stiface.ObjectHandle {
+	return &limitedObject{ObjectHandle: b.BucketHandle.Object(name), limiters: b.limiters}
+}
+
+type limitedObject struct {
+	stiface.ObjectHandle
+	limiters []*Limiter
+}
+
+// unwrap returns the handle of the underlying client, which it expects as the source of copies.
+func unwrap(o stiface.ObjectHandle) stiface.ObjectHandle {
+	if l, ok := o.(*limitedObject); ok {
+		return l.ObjectHandle
+	}
+	return o
+}
+
+func (o *limitedObject) wrap(h stiface.ObjectHandle) stiface.ObjectHandle {
+	return &limitedObject{ObjectHandle: h, limiters: o.limiters}
+}
+
+func (o *limitedObject) Generation(gen int64) stiface.ObjectHandle {
+	return o.wrap(o.ObjectHandle.Generation(gen))
+}
+
+func (o *limitedObject) If(conds storage.Conditions) stiface.ObjectHandle {
+	return o.wrap(o.ObjectHandle.If(conds))
+}
+
+func (o *limitedObject) Key(key []byte) stiface.ObjectHandle {
+	return o.wrap(o.ObjectHandle.Key(key))
+}
+
+func (o *limitedObject) ReadCompressed(compressed bool) stiface.ObjectHandle {
+	return o.wrap(o.ObjectHandle.ReadCompressed(compressed))
+}
+
+func (o *limitedObject) Attrs(ctx context.Context) (*storage.ObjectAttrs, error) {
+	if err := waitAll(ctx, o.limiters); err != nil {
+		return nil, err
+	}
+	return o.ObjectHandle.Attrs(ctx)
+}
+
+func (o *limitedObject) Update(ctx context.Context, attrs storage.ObjectAttrsToUpdate) (*storage.ObjectAttrs, error) {
+	if err := waitAll(ctx, o.limiters); err != nil {
+		return nil, err
+	}
+	return o.ObjectHandle.Update(ctx, attrs)
+}
+
+func (o *limitedObject) NewReader(ctx context.Context) (stiface.Reader, error) {
+	if err := waitAll(ctx, o.limiters); err != nil {
+		return nil, err
+	}
+	return o.ObjectHandle.NewReader(ctx)
+}
+
+func (o *limitedObject) NewRangeReader(ctx context.Context, offset, length int64) (stiface.Reader, error) {
+	if err := waitAll(ctx, o.limiters); err != nil {
+		return nil, err
+	}
+	return o.ObjectHandle.NewRangeReader(ctx, offset, length)
+}
+
+func (o *limitedObject) NewWriter(ctx context.Context) stiface.Writer {
+	return &limitedWriter{Writer: o.ObjectHandle.NewWriter(ctx), ctx: ctx, limiters: o.limiters, chunkSize: defaultChunkSize}
+}
+
+// limitedWriter waits for the limiters before every chunk the writer uploads.
+// A writer whose chunk size is not positive uploads the file in a single request.
+type limitedWriter struct {
+	stiface.Writer
+	ctx       context.Context
+	limiters  []*Limiter
+	chunkSize int
+	// written is the number of bytes written so far.
+	written int
+	// charged is the number of chunks waited for so far.
+	charged int
+	// err is the error of waiting for the limiters, returned by every later call to Write and Close.
+	err error
+}
+
+// chunks returns the number of chunks uploaded for a file of size bytes.
+func (w *limitedWriter) chunks(size int) int {
+	if w.chunkSize <= 0 || size <= w.chunkSize {
+		return 1
+	}
+	return (size + w.chunkSize - 1) / w.chunkSize
+}
+
+// wait waits for the limiters once for every chunk of a file of size bytes that was not waited for.
+func (w *limitedWriter) wait(size int) error {
+	if w.err != nil {
+		return w.err
+	}
+	for ; w.charged < w.chunks(size); w.charged++ {
+		if err := waitAll(w.ctx, w.limiters); err != nil {
+			// The upload is aborted by the done context, so the writer is not closed.
+			w.err = err
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *limitedWriter) SetChunkSize(size int) {
+	w.chunkSize = size
+	w.Writer.SetChunkSize(size)
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if err := w.wait(w.written + len(p)); err != nil {
+		return 0, err
+	}
+	n, err := w.Writer.Write(p)
+	w.written += n
+	return n, err
+}
+
+func (w *limitedWriter) Close() error {
+	if err := w.wait(w.written); err != nil {
+		return err
+	}
+	return w.Writer.Close()
+}
+
+func (o *limitedObject) Delete(ctx context.Context) error {
+	if err := waitAll(ctx, o.limiters); err != nil {
+		return err
+	}
+	return o.ObjectHandle.Delete(ctx)
+}
+
+func (o *limitedObject) CopierFrom(src stiface.ObjectHandle) stiface.Copier {
+	return &limitedCopier{Copier: o.ObjectHandle.CopierFrom(unwrap(src)), limiters: o.limiters}
+}
+
+func (o *limitedObject) ComposerFrom(srcs ...stiface.ObjectHandle) stiface.Composer {
+	unwrapped := make([]stiface.ObjectHandle, len(srcs))
+	for i, src := range srcs {
+		unwrapped[i] = unwrap(src)
+	}
+	return &limitedComposer{Composer: o.ObjectHandle.ComposerFrom(unwrapped...), limiters: o.limiters}
+}
+
+type limitedCopier struct {
+	stiface.Copier
+	limiters []*Limiter
+}
+
+func (c *limitedCopier) Run(ctx context.Context) (*storage.ObjectAttrs, error) {
+	if err := waitAll(ctx, c.limiters); err != nil {
+		return nil, err
+	}
+	return c.Copier.Run(ctx)
+}
+
+type limitedComposer struct {
+	stiface.Composer
+	limiters []*Limiter
+}
+
+func (c *limitedComposer) Run(ctx context.Context) (*storage.ObjectAttrs, error) {
+	if err := waitAll(ctx, c.limiters); err != nil {
+		return nil, err
+	}
+	return c.Composer.Run(ctx)
+}
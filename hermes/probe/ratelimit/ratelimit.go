@@ -0,0 +1,102 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ratelimit implements the limits on the rate of the API calls Hermes
+// makes to a target and to a storage system backend as a whole.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter limits the rate of events to a number of queries per second, allowing bursts
+// of up to one second of queries. A nil *Limiter does not limit the rate of events.
+type Limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    int
+	// tat is the theoretical arrival time of the next event if events arrived at exactly the limit.
+	tat time.Time
+}
+
+// New returns a limiter allowing qps queries per second, or nil if qps is not positive.
+func New(qps float64) *Limiter {
+	if qps <= 0 {
+		return nil
+	}
+	return &Limiter{
+		interval: time.Duration(float64(time.Second) / qps),
+		burst:    int(math.Max(1, math.Ceil(qps))),
+	}
+}
+
+// reserve reserves the next event and returns how long to wait before it is allowed.
+func (l *Limiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.tat.Before(now) {
+		l.tat = now
+	}
+	wait := l.tat.Sub(now) - time.Duration(l.burst-1)*l.interval
+	l.tat = l.tat.Add(l.interval)
+	return wait
+}
+
+// cancel gives back an event reserved with reserve that did not happen, so that it is not counted against the limit.
+func (l *Limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tat = l.tat.Add(-l.interval)
+}
+
+// Wait blocks until the next event is allowed by the limiter.
+// An event whose context is done before it is allowed is given back and not counted against the limit.
+// Arguments:
+//	- ctx: the context of the event.
+// Returns:
+//	- err: returns the error of the context if it is done before the event is allowed.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	wait := l.reserve(time.Now())
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// waitAll waits for every limiter in turn.
+func waitAll(ctx context.Context, limiters []*Limiter) error {
+	for _, l := range limiters {
+		if err := l.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+	"github.com/googleinterns/step224-2020/hermes/probe/fakegcs"
+	"google.golang.org/api/iterator"
+)
+
+const bucketName = "test_bucket_ratelimit"
+
+func TestWait(t *testing.T) {
+	tests := []struct {
+		desc    string
+		qps     float64
+		events  int
+		minTime time.Duration
+		maxTime time.Duration
+	}{
+		{
+			desc:    "no limit",
+			qps:     0,
+			events:  100,
+			maxTime: 50 * time.Millisecond,
+		},
+		{
+			desc:    "burst within the limit",
+			qps:     20,
+			events:  20,
+			maxTime: 50 * time.Millisecond,
+		},
+		{
+			desc:    "events above the burst are spread at the limit",
+			qps:     20,
+			events:  25,
+			minTime: 5 * 50 * time.Millisecond,
+			maxTime: time.Second,
+		},
+	}
+
+	for _, tc := range tests {
+		ctx := context.Background()
+		l := New(tc.qps)
+		start := time.Now()
+		for i := 0; i < tc.events; i++ {
+			if err := l.Wait(ctx); err != nil {
+				t.Fatalf("%s: Wait() failed: %v", tc.desc, err)
+			}
+		}
+		if got := time.Now().Sub(start); got < tc.minTime || got > tc.maxTime {
+			t.Errorf("%s: %d calls to Wait() took %v, want between %v and %v", tc.desc, tc.events, got, tc.minTime, tc.maxTime)
+		}
+	}
+}
+
+func TestWaitCancelled(t *testing.T) {
+	l := New(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := l.Wait(ctx); err != nil {
+		t.Fatalf("Wait() failed: %v", err)
+	}
+	cancel()
+	if err := l.Wait(ctx); err != context.Canceled {
+		t.Errorf("Wait() with a cancelled context = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestWaitGivesBackCancelledEvents(t *testing.T) {
+	l := New(20)
+	ctx := context.Background()
+	for i := 0; i < 20; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("Wait() failed: %v", err)
+		}
+	}
+	// Events that time out before they are allowed do not delay the events after them.
+	for i := 0; i < 10; i++ {
+		timeoutCtx, cancel := context.WithTimeout(ctx, time.Millisecond)
+		if err := l.Wait(timeoutCtx); err != context.DeadlineExceeded {
+			t.Errorf("Wait() above the burst with a short timeout = %v, want %v", err, context.DeadlineExceeded)
+		}
+		cancel()
+	}
+	start := time.Now()
+	if err := l.Wait(ctx); err != nil {
+		t.Fatalf("Wait() failed: %v", err)
+	}
+	if got, max := time.Now().Sub(start), 200*time.Millisecond; got > max {
+		t.Errorf("Wait() after 10 cancelled events took %v, want at most %v", got, max)
+	}
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	fake := fakegcs.NewClient()
+	if err := fake.Bucket(bucketName).Create(ctx, bucketName, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	if got := NewClient(fake, nil, nil); got != fake {
+		t.Errorf("NewClient() without limiters = %v, want the client given", got)
+	}
+
+	// The limiters are shared, e.g. a target limiter and a backend limiter, and both apply to every call.
+	client := NewClient(fake, New(1000), New(10))
+	object := client.Bucket(bucketName).Object("Hermes_01_a")
+	start := time.Now()
+	w := object.NewWriter(ctx)
+	if _, err := w.Write([]byte("contents")); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	for i := 0; i < 14; i++ {
+		if _, err := object.Attrs(ctx); err != nil {
+			t.Fatalf("Attrs() failed: %v", err)
+		}
+	}
+	r, err := object.NewReader(ctx)
+	if err != nil {
+		t.Fatalf("NewReader() failed: %v", err)
+	}
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	if err != nil || string(data) != "contents" {
+		t.Errorf("ReadAll() = %q, %v, want %q", data, err, "contents")
+	}
+	// 16 calls at 10 QPS with a burst of 10 take at least 600ms.
+	if got, want := time.Now().Sub(start), 600*time.Millisecond; got < want {
+		t.Errorf("16 API calls at 10 QPS took %v, want at least %v", got, want)
+	}
+}
+
+// countingContext counts the calls to Err, which Wait makes once for every event.
+type countingContext struct {
+	context.Context
+	calls int
+}
+
+func (c *countingContext) Err() error {
+	c.calls++
+	return c.Context.Err()
+}
+
+// pagedIterator returns the objects of its pages, fetching one page at a time like a storage.ObjectIterator.
+type pagedIterator struct {
+	stiface.ObjectIterator
+	pages   [][]string
+	fetched int
+	buf     []string
+	info    iterator.PageInfo
+}
+
+func (it *pagedIterator) Next() (*storage.ObjectAttrs, error) {
+	if len(it.buf) == 0 {
+		if it.fetched == len(it.pages) {
+			return nil, iterator.Done
+		}
+		it.buf = it.pages[it.fetched]
+		it.fetched++
+		it.info.Token = ""
+		if it.fetched < len(it.pages) {
+			it.info.Token = fmt.Sprintf("page%d", it.fetched)
+		}
+	}
+	name := it.buf[0]
+	it.buf = it.buf[1:]
+	return &storage.ObjectAttrs{Name: name}, nil
+}
+
+func (it *pagedIterator) PageInfo() *iterator.PageInfo {
+	return &it.info
+}
+
+type pagedBucket struct {
+	stiface.BucketHandle
+	pages [][]string
+}
+
+func (b *pagedBucket) Objects(ctx context.Context, q *storage.Query) stiface.ObjectIterator {
+	return &pagedIterator{pages: b.pages}
+}
+
+func TestClientObjects(t *testing.T) {
+	pages := [][]string{{"a", "b"}, {"c"}, {"d", "e"}}
+	ctx := &countingContext{Context: context.Background()}
+	bucket := &limitedBucket{BucketHandle: &pagedBucket{pages: pages}, limiters: []*Limiter{New(1000)}}
+	it := bucket.Objects(ctx, nil)
+	var names []string
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() failed: %v", err)
+		}
+		names = append(names, attrs.Name)
+	}
+	if got, want := fmt.Sprint(names), "[a b c d e]"; got != want {
+		t.Errorf("Objects() listed %s, want %s", got, want)
+	}
+	if ctx.calls != len(pages) {
+		t.Errorf("Objects() waited for the limiter %d times, want once for each of the %d pages", ctx.calls, len(pages))
+	}
+
+	// The error of a done context is returned instead of the objects.
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	it = bucket.Objects(cancelled, nil)
+	for i := 0; i < 2; i++ {
+		if _, err := it.Next(); err != context.Canceled {
+			t.Errorf("Next() with a cancelled context = %v, want %v", err, context.Canceled)
+		}
+	}
+}
+
+// bufferWriter writes to a buffer.
+type bufferWriter struct {
+	stiface.Writer
+	buf       bytes.Buffer
+	chunkSize int
+	closed    bool
+}
+
+func (w *bufferWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *bufferWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func (w *bufferWriter) SetChunkSize(size int) {
+	w.chunkSize = size
+}
+
+type bufferObject struct {
+	stiface.ObjectHandle
+	w *bufferWriter
+}
+
+func (o *bufferObject) NewWriter(ctx context.Context) stiface.Writer {
+	return o.w
+}
+
+func TestClientNewWriter(t *testing.T) {
+	tests := []struct {
+		desc      string
+		chunkSize int
+		size      int
+		wantWaits int
+	}{
+		{"empty file", 4, 0, 1},
+		{"single chunk", 4, 4, 1},
+		{"several chunks", 4, 10, 3},
+		{"single request", 0, 10, 1},
+		{"default chunk size", -1, 10, 1},
+	}
+	for _, tc := range tests {
+		ctx := &countingContext{Context: context.Background()}
+		bw := &bufferWriter{}
+		object := &limitedObject{ObjectHandle: &bufferObject{w: bw}, limiters: []*Limiter{New(1000)}}
+		w := object.NewWriter(ctx)
+		if tc.chunkSize >= 0 {
+			w.SetChunkSize(tc.chunkSize)
+		}
+		data := bytes.Repeat([]byte("x"), tc.size)
+		for len(data) > 0 {
+			n := 3
+			if n > len(data) {
+				n = len(data)
+			}
+			if _, err := w.Write(data[:n]); err != nil {
+				t.Fatalf("%s: Write() failed: %v", tc.desc, err)
+			}
+			data = data[n:]
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("%s: Close() failed: %v", tc.desc, err)
+		}
+		if ctx.calls != tc.wantWaits || bw.buf.Len() != tc.size || !bw.closed {
+			t.Errorf("%s: writing %d bytes waited for the limiter %d times and wrote %d bytes, want %d waits and all bytes written",
+				tc.desc, tc.size, ctx.calls, bw.buf.Len(), tc.wantWaits)
+		}
+	}
+
+	// The error of a done context is returned by the writer, which does not upload the file.
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	bw := &bufferWriter{}
+	object := &limitedObject{ObjectHandle: &bufferObject{w: bw}, limiters: []*Limiter{New(1000)}}
+	w := object.NewWriter(cancelled)
+	if _, err := w.Write([]byte("contents")); err != context.Canceled {
+		t.Errorf("Write() with a cancelled context = %v, want %v", err, context.Canceled)
+	}
+	if err := w.Close(); err != context.Canceled {
+		t.Errorf("Close() with a cancelled context = %v, want %v", err, context.Canceled)
+	}
+	if bw.buf.Len() != 0 || bw.closed {
+		t.Errorf("writer with a cancelled context wrote %d bytes and closed %t, want nothing written", bw.buf.Len(), bw.closed)
+	}
+}
@@ -21,32 +21,45 @@
 package read
 
 import (
+	"bytes"
 	"context"
-	"crypto/sha1"
+	"crypto/md5"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"math/rand"
+	"strconv"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/storage"
 	"github.com/google/cloudprober/logger"
 	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+	"github.com/googleinterns/step224-2020/hermes/probe/checksum"
+	"github.com/googleinterns/step224-2020/hermes/probe/content"
+	"github.com/googleinterns/step224-2020/hermes/probe/create"
+	"github.com/googleinterns/step224-2020/hermes/probe/journal"
+	"github.com/googleinterns/step224-2020/hermes/probe/layout"
 	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
 	"github.com/googleinterns/step224-2020/hermes/probe/target"
 	"google.golang.org/api/iterator"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
 )
 
 const (
-	// universal format of the names of files in the storage system Hermes_ID_checksum
-	FileNameFormat          = "Hermes_%02d_%x"
-	minFileID               = 1
-	maxFileID               = 50
-	maxFileSizeBytes        = 1000
-	hermesAPILatencySeconds = "hermes_api_latency_seconds"
+	// universal format of the names of files in the storage system Hermes_ID_seed_algorithm_checksum
+	FileNameFormat = "Hermes_%02d_%016x_%s_%x"
+	// universal format of the filename prefix shared by all files with the same ID Hermes_ID_
+	FileNamePrefixFormat     = "Hermes_%02d_"
+	maxFileSizeBytes         = 1000
+	hermesAPILatencySeconds  = "hermes_api_latency_seconds"
+	defaultRangeReadsPerFile = 4
 )
 
 func verifyFileExists(ctx context.Context, client stiface.Client, target *target.Target, fileName string, fileID int32) error {
 	bucket := target.Target.GetBucketName()
-	fileNamePrefix := fmt.Sprintf(FileNameFormat, fileID, "")
+	fileNamePrefix := fmt.Sprintf(FileNamePrefixFormat, fileID)
 	query := &storage.Query{Prefix: fileNamePrefix}
 	start := time.Now()
 	objIter := client.Bucket(bucket).Objects(ctx, query)
@@ -76,6 +89,196 @@ func verifyFileExists(ctx context.Context, client stiface.Client, target *target
 	return nil
 }
 
+// fileNameParts holds the values embedded in the name of a file.
+type fileNameParts struct {
+	// seed used to generate the contents of the file, unknown for legacy files
+	seed int64
+	// legacy is true for files created before the seed was encoded in their name, whose contents
+	// cannot be regenerated and which have no Hermes metadata
+	legacy bool
+	// algorithm used to compute the checksum
+	alg probepb.Target_ChecksumAlgorithm
+	// hex encoded checksum of the contents of the file
+	checksum string
+}
+
+// parseFileName returns the seed, checksum algorithm and hex encoded checksum embedded in a filename.
+// Filenames created before the seed was encoded in them, Hermes_ID_algorithm_checksum and Hermes_ID_checksum,
+// are legacy files. Filenames created before the algorithm was encoded in them use SHA1.
+func parseFileName(fileName string, fileID int32) (*fileNameParts, error) {
+	fileNamePrefix := fmt.Sprintf(FileNamePrefixFormat, fileID)
+	if !strings.HasPrefix(fileName, fileNamePrefix) {
+		return nil, fmt.Errorf("file name %q does not have the prefix %q", fileName, fileNamePrefix)
+	}
+	parts := strings.Split(fileName[len(fileNamePrefix):], "_")
+	switch len(parts) {
+	case 1:
+		return &fileNameParts{legacy: true, alg: probepb.Target_SHA1, checksum: parts[0]}, nil
+	case 2:
+		alg, err := checksum.Parse(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("file name %q: %w", fileName, err)
+		}
+		return &fileNameParts{legacy: true, alg: alg, checksum: parts[1]}, nil
+	case 3:
+		seed, err := strconv.ParseUint(parts[0], 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("file name %q has an invalid seed %q: %w", fileName, parts[0], err)
+		}
+		alg, err := checksum.Parse(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("file name %q: %w", fileName, err)
+		}
+		return &fileNameParts{seed: int64(seed), alg: alg, checksum: parts[2]}, nil
+	default:
+		return nil, fmt.Errorf("file name %q does not match the format %q", fileName, FileNameFormat)
+	}
+}
+
+// FileNameChecksum returns the checksum algorithm and hex encoded checksum embedded in the name of a file,
+// and whether the file is a legacy file, see parseFileName.
+func FileNameChecksum(fileName string, fileID int32) (probepb.Target_ChecksumAlgorithm, string, bool, error) {
+	parts, err := parseFileName(fileName, fileID)
+	if err != nil {
+		return 0, "", false, err
+	}
+	return parts.alg, parts.checksum, parts.legacy, nil
+}
+
+// getFileAttrs gets the attributes the storage system holds for a file.
+func getFileAttrs(ctx context.Context, client stiface.Client, target *target.Target, fileName string) (*storage.ObjectAttrs, error) {
+	start := time.Now()
+	attrs, err := client.Bucket(target.Target.GetBucketName()).Object(fileName).Attrs(ctx)
+	if err != nil {
+		var status metrics.ExitStatus
+		switch err {
+		case storage.ErrObjectNotExist:
+			status = metrics.FileMissing
+		case storage.ErrBucketNotExist:
+			status = metrics.BucketMissing
+		default:
+			status = metrics.APICallFailed
+		}
+		target.LatencyMetrics.APICallLatency[metrics.APIGetFileAttrs][status].Metric(hermesAPILatencySeconds).AddFloat64(time.Now().Sub(start).Seconds())
+		return nil, metrics.NewProbeError(status, fmt.Errorf("could not get attributes of file %q: %w", fileName, err))
+	}
+	target.LatencyMetrics.APICallLatency[metrics.APIGetFileAttrs][metrics.Success].Metric(hermesAPILatencySeconds).AddFloat64(time.Now().Sub(start).Seconds())
+	return attrs, nil
+}
+
+// verifyMetadata compares the size, content type and custom metadata reported by the storage system
+// for a file against the values Hermes set when it created the file.
+func verifyMetadata(attrs *storage.ObjectAttrs, seed int64, fileSize int) error {
+	if attrs.Size != int64(fileSize) {
+		return metrics.NewProbeError(metrics.FileMetadataMismatch, fmt.Errorf("file %q has size %d bytes; want %d bytes", attrs.Name, attrs.Size, fileSize))
+	}
+	if attrs.ContentType != create.ContentType {
+		return metrics.NewProbeError(metrics.FileMetadataMismatch, fmt.Errorf("file %q has content type %q; want %q", attrs.Name, attrs.ContentType, create.ContentType))
+	}
+	if attrs.Metadata[create.MetadataVersionKey] == "" {
+		return metrics.NewProbeError(metrics.FileMetadataMismatch, fmt.Errorf("file %q is missing the metadata key %q", attrs.Name, create.MetadataVersionKey))
+	}
+	want := map[string]string{
+		create.MetadataSeedKey: strconv.FormatInt(seed, 10),
+		create.MetadataSizeKey: strconv.Itoa(fileSize),
+	}
+	for key, value := range want {
+		if got := attrs.Metadata[key]; got != value {
+			return metrics.NewProbeError(metrics.FileMetadataMismatch, fmt.Errorf("file %q has metadata %s = %q; want %q", attrs.Name, key, got, value))
+		}
+	}
+	return nil
+}
+
+// verifyServerChecksums compares the checksums reported by the storage system for a file against the
+// checksums of the contents that were read from it. This catches mismatches between what the storage
+// system believes it stored and what it returns.
+// GCS always reports a CRC32C checksum, but does not report an MD5 hash for composite objects.
+func verifyServerChecksums(attrs *storage.ObjectAttrs, gotCRC32C uint32, gotMD5 []byte) error {
+	if attrs.CRC32C != gotCRC32C {
+		return metrics.NewProbeError(metrics.FileCorrupted, fmt.Errorf("the CRC32C of the contents read from file %q: %08x does not match the CRC32C reported by the server: %08x", attrs.Name, gotCRC32C, attrs.CRC32C))
+	}
+	if len(attrs.MD5) > 0 && !bytes.Equal(attrs.MD5, gotMD5) {
+		return metrics.NewProbeError(metrics.FileCorrupted, fmt.Errorf("the MD5 of the contents read from file %q: %x does not match the MD5 reported by the server: %x", attrs.Name, gotMD5, attrs.MD5))
+	}
+	return nil
+}
+
+// byteRange is a range of bytes within a file.
+type byteRange struct {
+	offset int64
+	length int64
+}
+
+// pickRanges picks n random, non-empty byte ranges within a file of the size given.
+// It returns an error if the file is empty, as an empty file has no byte ranges to pick.
+func pickRanges(rng *rand.Rand, n int, fileSize int64) ([]byteRange, error) {
+	if fileSize <= 0 {
+		return nil, fmt.Errorf("cannot pick byte ranges within a file of %d bytes", fileSize)
+	}
+	ranges := make([]byteRange, n)
+	for i := range ranges {
+		offset := rng.Int63n(fileSize)
+		ranges[i] = byteRange{offset: offset, length: rng.Int63n(fileSize-offset) + 1}
+	}
+	return ranges, nil
+}
+
+// verifyRange reads a byte range of a file and compares it against the deterministic contents expected
+// at the same offset.
+func verifyRange(ctx context.Context, client stiface.Client, target *target.Target, fileName string, seed int64, fileSize int, r byteRange) error {
+	want := make([]byte, r.length)
+	if _, err := content.NewReader(seed, int64(fileSize)).ReadAt(want, r.offset); err != nil {
+		return fmt.Errorf("could not generate the expected contents of file %q at offset %d: %w", fileName, r.offset, err)
+	}
+
+	start := time.Now()
+	reader, err := client.Bucket(target.Target.GetBucketName()).Object(fileName).NewRangeReader(ctx, r.offset, r.length)
+	if err != nil {
+		var status metrics.ExitStatus
+		switch err {
+		case storage.ErrObjectNotExist:
+			status = metrics.FileMissing
+		case storage.ErrBucketNotExist:
+			status = metrics.BucketMissing
+		default:
+			status = metrics.ProbeFailed
+		}
+		target.LatencyMetrics.APICallLatency[metrics.APIGetFileRange][status].Metric(hermesAPILatencySeconds).AddFloat64(time.Now().Sub(start).Seconds())
+		return metrics.NewProbeError(status, fmt.Errorf("could not read bytes [%d, %d) of file %q: %w", r.offset, r.offset+r.length, fileName, err))
+	}
+	defer reader.Close()
+	got, err := ioutil.ReadAll(reader)
+	if err != nil {
+		target.LatencyMetrics.APICallLatency[metrics.APIGetFileRange][metrics.FileReadFailure].Metric(hermesAPILatencySeconds).AddFloat64(time.Now().Sub(start).Seconds())
+		return metrics.NewProbeError(metrics.FileReadFailure, fmt.Errorf("could not read bytes [%d, %d) of file %q: %w", r.offset, r.offset+r.length, fileName, err))
+	}
+	target.LatencyMetrics.APICallLatency[metrics.APIGetFileRange][metrics.Success].Metric(hermesAPILatencySeconds).AddFloat64(time.Now().Sub(start).Seconds())
+	if !bytes.Equal(got, want) {
+		return metrics.NewProbeError(metrics.FileCorrupted, fmt.Errorf("bytes [%d, %d) of file %q do not match the expected contents: got %d bytes, want %d bytes", r.offset, r.offset+r.length, fileName, len(got), len(want)))
+	}
+	return nil
+}
+
+// verifyRanges reads random byte ranges of a file and verifies each of them.
+func verifyRanges(ctx context.Context, client stiface.Client, target *target.Target, fileName string, seed int64, fileSize int) error {
+	n := int(target.Target.GetRangeReadsPerFile())
+	if n <= 0 {
+		n = defaultRangeReadsPerFile
+	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	ranges, err := pickRanges(rng, n, int64(fileSize))
+	if err != nil {
+		return metrics.NewProbeError(metrics.FileCorrupted, fmt.Errorf("file %q: %w", fileName, err))
+	}
+	for _, r := range ranges {
+		if err := verifyRange(ctx, client, target, fileName, seed, fileSize, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ReadFile creates and stores a file with randomized contents in the target storage system.
 // Before it creates and stores a file it logs an intent to do so in the target storage system's journal.
 // It verifies that the creation and storage process was successful.
@@ -83,14 +286,26 @@ func verifyFileExists(ctx context.Context, client stiface.Client, target *target
 // Arguments:
 //          ctx: it carries deadlines and cancellation signals that might orinate from the main probe located in probe.go.
 //          target: contains information about target storage system, carries an intent log in the form of a StateJournal and it used to export metrics.
-//          fileID: the unique identifer of every file, it cannot be repeated. It needs to be in the range [minFileID, maxFileID]. FileID 0 is reserved for a special file called the NIL file.
+//          fileID: the unique identifer of every file, it cannot be repeated. It needs to be in the file layout of the target. FileID 0 is reserved for a special file called the NIL file.
 //          client: is a storage client. It is used as an interface to interact with the target storage system.
 //          logger: a cloudprober logger used to record the exit status of the ReadFile operation in a target bucket. The logger passed MUST be a valid logger.
 // Returns:
 //          error: an error string with detailed information about the status and fileID. Nil is returned when the operation is successful.
+// The exit status of the read is recorded in the journal entry of the file.
 func ReadFile(ctx context.Context, target *target.Target, fileID int32, fileSize int, client stiface.Client, logger *logger.Logger) error {
-	if fileID < minFileID || fileID > maxFileID {
-		return fmt.Errorf("invalid argument: fileID = %d; want %d <= fileID <= %d", fileID, minFileID, maxFileID)
+	err := readFile(ctx, target, fileID, fileSize, client, logger)
+	journal.RecordStatus(target.Journal, fileID, metrics.StatusOf(err), time.Now())
+	return err
+}
+
+// readFile reads and verifies the file with the ID given, see ReadFile.
+func readFile(ctx context.Context, target *target.Target, fileID int32, fileSize int, client stiface.Client, logger *logger.Logger) error {
+	l, err := layout.New(target.Target.GetFileLayout())
+	if err != nil {
+		return metrics.NewProbeError(metrics.InvalidArgument, err)
+	}
+	if !l.Contains(fileID) {
+		return fmt.Errorf("invalid argument: fileID = %d; want %d <= fileID <= %d", fileID, layout.MinFileID, l.MaxFileID())
 	}
 	bucket := target.Target.GetBucketName()
 	// Verify that the file is present in the State Journal
@@ -101,6 +316,28 @@ func ReadFile(ctx context.Context, target *target.Target, fileID int32, fileSize
 	if err := verifyFileExists(ctx, client, target, fileName, fileID); err != nil {
 		return fmt.Errorf("verifyFileExistsCheck (fileID: %d) failed: %w", fileID, err)
 	}
+	parts, err := parseFileName(fileName, fileID)
+	if err != nil {
+		return metrics.NewProbeError(metrics.FileCorrupted, err)
+	}
+	attrs, err := getFileAttrs(ctx, client, target, fileName)
+	if err != nil {
+		return err
+	}
+	// Legacy files were written without Hermes metadata from contents that cannot be regenerated,
+	// so they are only verified against the checksum in their name.
+	if !parts.legacy {
+		if err := verifyMetadata(attrs, parts.seed, fileSize); err != nil {
+			return err
+		}
+	}
+	if target.Target.GetReadMode() == probepb.Target_RANGE && !parts.legacy {
+		if err := verifyRanges(ctx, client, target, fileName, parts.seed, fileSize); err != nil {
+			return err
+		}
+		logger.Infof("verified consistency of byte ranges of object %q in bucket %q", fileName, bucket)
+		return nil
+	}
 	start := time.Now()
 	reader, err := client.Bucket(bucket).Object(fileName).NewReader(ctx)
 	if err != nil {
@@ -114,18 +351,25 @@ func ReadFile(ctx context.Context, target *target.Target, fileID int32, fileSize
 			status = metrics.ProbeFailed
 		}
 		target.LatencyMetrics.APICallLatency[metrics.APIGetFile][status].Metric(hermesAPILatencySeconds).AddFloat64(time.Now().Sub(start).Seconds())
-		return fmt.Errorf("%v: could not read file %q: %w", status, fileName, err)
+		return metrics.NewProbeError(status, fmt.Errorf("could not read file %q: %w", fileName, err))
 	}
 	defer reader.Close()
-	h := sha1.New()
-	if _, err := io.Copy(h, reader); err != nil {
-		return fmt.Errorf("checksum calculation failed io.Copy: %w", err)
-	}
-	gotChecksum := fmt.Sprintf("%x", h.Sum(nil))
-	fileNamePrefix := fmt.Sprintf(FileNameFormat, fileID, "")
-	wantChecksum := fileName[len(fileNamePrefix):]
-	if gotChecksum != wantChecksum {
-		return fmt.Errorf("the calculated checksum: %q does not match the checksum in the file name: %q", gotChecksum, wantChecksum)
+	h, err := checksum.New(parts.alg)
+	if err != nil {
+		return err
+	}
+	crc := checksum.NewCRC32C()
+	md := md5.New()
+	if _, err := io.Copy(io.MultiWriter(h, crc, md), reader); err != nil {
+		target.LatencyMetrics.APICallLatency[metrics.APIGetFile][metrics.FileReadFailure].Metric(hermesAPILatencySeconds).AddFloat64(time.Now().Sub(start).Seconds())
+		return metrics.NewProbeError(metrics.FileReadFailure, fmt.Errorf("checksum calculation failed io.Copy: %w", err))
+	}
+	target.LatencyMetrics.APICallLatency[metrics.APIGetFile][metrics.Success].Metric(hermesAPILatencySeconds).AddFloat64(time.Now().Sub(start).Seconds())
+	if gotChecksum := fmt.Sprintf("%x", h.Sum(nil)); gotChecksum != parts.checksum {
+		return metrics.NewProbeError(metrics.FileCorrupted, fmt.Errorf("the calculated %s checksum: %q does not match the checksum in the file name: %q", checksum.Name(parts.alg), gotChecksum, parts.checksum))
+	}
+	if err := verifyServerChecksums(attrs, crc.Sum32(), md.Sum(nil)); err != nil {
+		return err
 	}
 	logger.Infof("verified consistency for object %q in bucket %q", fileName, bucket)
 	return nil
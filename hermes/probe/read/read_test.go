@@ -20,19 +20,21 @@
 package read
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha1"
+	"fmt"
+	"math/rand"
 	"testing"
 
-	"github.com/golang/protobuf/proto"
+	"cloud.google.com/go/storage"
 	"github.com/google/cloudprober/logger"
 	"github.com/googleinterns/step224-2020/hermes/probe/create"
 	"github.com/googleinterns/step224-2020/hermes/probe/fakegcs"
 	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
-	"github.com/googleinterns/step224-2020/hermes/probe/target"
+	"github.com/googleinterns/step224-2020/hermes/probe/probetest"
 
-	metricpb "github.com/google/cloudprober/metrics/proto"
 	probepb "github.com/googleinterns/step224-2020/config/proto"
-	journalpb "github.com/googleinterns/step224-2020/hermes/proto"
 )
 
 const (
@@ -41,53 +43,7 @@ const (
 )
 
 func TestReadFile(t *testing.T) {
-	target := &target.Target{
-		&probepb.Target{
-			Name:                   "hermes",
-			TargetSystem:           probepb.Target_GOOGLE_CLOUD_STORAGE,
-			TotalSpaceAllocatedMib: 1,
-			BucketName:             "test_bucket_probe0",
-		},
-		&journalpb.StateJournal{
-			Filenames: make(map[int32]string),
-		},
-		&metrics.Metrics{},
-	}
-	hp := &probepb.HermesProbeDef{
-		ProbeName: proto.String("createfile_test"),
-		Targets: []*probepb.Target{
-			&probepb.Target{
-				Name:                   "hermes",
-				TargetSystem:           probepb.Target_GOOGLE_CLOUD_STORAGE,
-				TotalSpaceAllocatedMib: int64(1),
-				BucketName:             "test_bucket_probe0",
-			},
-		},
-		TargetSystem: probepb.HermesProbeDef_GCS.Enum(),
-		IntervalSec:  proto.Int32(3600),
-		TimeoutSec:   proto.Int32(60),
-		ProbeLatencyDistribution: &metricpb.Dist{
-			Buckets: &metricpb.Dist_ExplicitBuckets{
-				ExplicitBuckets: "0.1,0.2,0.4,0.6,0.8,1.6,3.2,6.4,12.8,1",
-			},
-		},
-		ApiCallLatencyDistribution: &metricpb.Dist{
-			Buckets: &metricpb.Dist_ExplicitBuckets{
-				ExplicitBuckets: "0.1,0.2,0.4,0.6,0.8,1.6,3.2,6.4,12.8,1",
-			},
-		},
-	}
-	probeTarget := &probepb.Target{
-		Name:                   "hermes",
-		TargetSystem:           probepb.Target_GOOGLE_CLOUD_STORAGE,
-		TotalSpaceAllocatedMib: 1,
-		BucketName:             "test_bucket_probe0",
-	}
-
-	var err error
-	if target.LatencyMetrics, err = metrics.NewMetrics(hp, probeTarget); err != nil {
-		t.Fatalf("metrics.NewMetrics(): %v", err)
-	}
+	target := probetest.NewTarget(t, readTestProbeName, probetest.TargetConfig("test_bucket_probe0"))
 
 	ctx := context.Background()
 	client := fakegcs.NewClient()
@@ -117,7 +73,250 @@ func TestReadFile(t *testing.T) {
 			t.Fatalf("CreateFile(fileID: %d) set up failed %v", tc.fileIDCreate, err)
 		}
 		if err := ReadFile(ctx, target, tc.fileIDRead, fileSizeBytes, client, logger); (err != nil) != tc.wantErr {
-			t.Errorf("ReadFile(fileID: %d) = %w, want: %v", tc.fileIDRead, err, tc.wantErr)
+			t.Errorf("ReadFile(fileID: %d) = %v, want error: %v", tc.fileIDRead, err, tc.wantErr)
+		}
+		if entry, ok := target.Journal.Files[tc.fileIDRead]; ok && !tc.wantErr && entry.GetLastVerifiedUnixSec() == 0 {
+			t.Errorf("ReadFile(fileID: %d) did not record the verification of the file in the journal: %v", tc.fileIDRead, entry)
+		}
+	}
+}
+
+func TestParseFileName(t *testing.T) {
+	tests := []struct {
+		fileName     string
+		fileID       int32
+		wantSeed     int64
+		wantLegacy   bool
+		wantAlg      probepb.Target_ChecksumAlgorithm
+		wantChecksum string
+		wantErr      bool
+	}{
+		{"Hermes_03_6367c48dd193d56ea7b0baad25b19455e529f5ee", 3, 0, true, probepb.Target_SHA1, "6367c48dd193d56ea7b0baad25b19455e529f5ee", false},
+		{"Hermes_03_sha1_6367c48dd193d56ea7b0baad25b19455e529f5ee", 3, 0, true, probepb.Target_SHA1, "6367c48dd193d56ea7b0baad25b19455e529f5ee", false},
+		{"Hermes_12_crc32c_364b3fb7", 12, 0, true, probepb.Target_CRC32C, "364b3fb7", false},
+		{"Hermes_12_md5_900150983cd24fb0d6963f7d28e17f72", 12, 0, true, probepb.Target_MD5, "900150983cd24fb0d6963f7d28e17f72", false},
+		{"Hermes_12_0000000000c0ffee_crc32c_364b3fb7", 12, 0xc0ffee, false, probepb.Target_CRC32C, "364b3fb7", false},
+		{"Hermes_12_7fffffffffffffff_sha256_364b3fb7", 12, 0x7fffffffffffffff, false, probepb.Target_SHA256, "364b3fb7", false},
+		{"Hermes_12_crc32c_364b3fb7", 11, 0, false, 0, "", true},
+		{"Hermes_12_sha3_364b3fb7", 12, 0, false, 0, "", true},
+		{"Hermes_12_notaseed_crc32c_364b3fb7", 12, 0, false, 0, "", true},
+		{"Hermes_12_0000000000c0ffee_crc32c_364b3fb7_extra", 12, 0, false, 0, "", true},
+	}
+	for _, tc := range tests {
+		got, err := parseFileName(tc.fileName, tc.fileID)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseFileName(%q, %d) = %v, want error: %v", tc.fileName, tc.fileID, err, tc.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got.seed != tc.wantSeed || got.legacy != tc.wantLegacy || got.alg != tc.wantAlg || got.checksum != tc.wantChecksum {
+			t.Errorf("parseFileName(%q, %d) = (%d, %v, %v, %q), want (%d, %v, %v, %q)", tc.fileName, tc.fileID, got.seed, got.legacy, got.alg, got.checksum, tc.wantSeed, tc.wantLegacy, tc.wantAlg, tc.wantChecksum)
+		}
+	}
+}
+
+func TestReadFileChecksumAlgorithms(t *testing.T) {
+	ctx := context.Background()
+	logger, err := logger.NewCloudproberLog(readTestProbeName)
+	if err != nil {
+		t.Fatalf("failed to initialise logger: %v", err)
+	}
+	for _, alg := range []probepb.Target_ChecksumAlgorithm{probepb.Target_SHA1, probepb.Target_SHA256, probepb.Target_CRC32C, probepb.Target_MD5} {
+		target := probetest.NewTarget(t, readTestProbeName, probetest.TargetConfig("test_bucket_probe0"))
+		target.Target.ChecksumAlgorithm = alg
+		bucket := target.Target.GetBucketName()
+		client := fakegcs.NewClient()
+		if err := client.Bucket(bucket).Create(ctx, bucket, nil); err != nil {
+			t.Fatalf("error creating bucket %q: %v", bucket, err)
+		}
+		if err := create.CreateFile(ctx, target, 5, fileSizeBytes, client, logger); err != nil {
+			t.Fatalf("CreateFile(fileID: 5, algorithm: %v) set up failed %v", alg, err)
+		}
+		if err := ReadFile(ctx, target, 5, fileSizeBytes, client, logger); err != nil {
+			t.Errorf("ReadFile(fileID: 5, algorithm: %v) = %v, want nil", alg, err)
+		}
+	}
+}
+
+func TestReadFileLegacyName(t *testing.T) {
+	ctx := context.Background()
+	logger, err := logger.NewCloudproberLog(readTestProbeName)
+	if err != nil {
+		t.Fatalf("failed to initialise logger: %v", err)
+	}
+	contents := []byte("contents written before the seed and metadata were added")
+	for _, mode := range []probepb.Target_ReadMode{probepb.Target_FULL, probepb.Target_RANGE} {
+		target := probetest.NewTarget(t, readTestProbeName, probetest.TargetConfig("test_bucket_probe0"))
+		target.Target.ReadMode = mode
+		bucket := target.Target.GetBucketName()
+		client := fakegcs.NewClient()
+		if err := client.Bucket(bucket).Create(ctx, bucket, nil); err != nil {
+			t.Fatalf("error creating bucket %q: %v", bucket, err)
+		}
+		// Files created before this series were named Hermes_ID_checksum, with the SHA1 of their contents.
+		fileID := int32(4)
+		fileName := fmt.Sprintf("Hermes_%02d_%x", fileID, sha1.Sum(contents))
+		w := client.Bucket(bucket).Object(fileName).NewWriter(ctx)
+		if _, err := w.Write(contents); err != nil {
+			t.Fatalf("failed to write file %q: %v", fileName, err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("failed to write file %q: %v", fileName, err)
+		}
+		target.Journal.Filenames[fileID] = fileName
+		if err := ReadFile(ctx, target, fileID, len(contents), client, logger); err != nil {
+			t.Errorf("ReadFile(%q) in %v mode = %v, want nil", fileName, mode, err)
+		}
+
+		corruptName := fmt.Sprintf("Hermes_%02d_%x", fileID, sha1.Sum([]byte("other contents")))
+		if err := client.Bucket(bucket).Object(fileName).Delete(ctx); err != nil {
+			t.Fatalf("failed to delete file %q: %v", fileName, err)
+		}
+		w = client.Bucket(bucket).Object(corruptName).NewWriter(ctx)
+		if _, err := w.Write(contents); err != nil {
+			t.Fatalf("failed to write file %q: %v", corruptName, err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("failed to write file %q: %v", corruptName, err)
+		}
+		target.Journal.Filenames[fileID] = corruptName
+		err := ReadFile(ctx, target, fileID, len(contents), client, logger)
+		if got := metrics.StatusOf(err); got != metrics.FileCorrupted {
+			t.Errorf("ReadFile(%q) of a file that does not match its checksum in %v mode = %v, want status %q", corruptName, mode, err, metrics.ExitStatusName[metrics.FileCorrupted])
+		}
+	}
+}
+
+func TestVerifyMetadata(t *testing.T) {
+	validMetadata := func() map[string]string {
+		return map[string]string{
+			create.MetadataVersionKey: create.HermesVersion,
+			create.MetadataSeedKey:    "7",
+			create.MetadataSizeKey:    "100",
 		}
 	}
+	tests := []struct {
+		desc       string
+		modify     func(attrs *storage.ObjectAttrs)
+		wantStatus metrics.ExitStatus
+	}{
+		{"valid", func(attrs *storage.ObjectAttrs) {}, metrics.Success},
+		{"wrong size", func(attrs *storage.ObjectAttrs) { attrs.Size = 99 }, metrics.FileMetadataMismatch},
+		{"wrong content type", func(attrs *storage.ObjectAttrs) { attrs.ContentType = "text/plain" }, metrics.FileMetadataMismatch},
+		{"missing version", func(attrs *storage.ObjectAttrs) { delete(attrs.Metadata, create.MetadataVersionKey) }, metrics.FileMetadataMismatch},
+		{"wrong seed", func(attrs *storage.ObjectAttrs) { attrs.Metadata[create.MetadataSeedKey] = "8" }, metrics.FileMetadataMismatch},
+		{"wrong intended size", func(attrs *storage.ObjectAttrs) { attrs.Metadata[create.MetadataSizeKey] = "99" }, metrics.FileMetadataMismatch},
+		{"no metadata", func(attrs *storage.ObjectAttrs) { attrs.Metadata = nil }, metrics.FileMetadataMismatch},
+	}
+	for _, tc := range tests {
+		attrs := &storage.ObjectAttrs{
+			Name:        "Hermes_07_0000000000000007_sha1_abc",
+			Size:        100,
+			ContentType: create.ContentType,
+			Metadata:    validMetadata(),
+		}
+		tc.modify(attrs)
+		if got := metrics.StatusOf(verifyMetadata(attrs, 7, 100)); got != tc.wantStatus {
+			t.Errorf("%s: verifyMetadata() status = %q, want %q", tc.desc, metrics.ExitStatusName[got], metrics.ExitStatusName[tc.wantStatus])
+		}
+	}
+}
+
+func TestPickRanges(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, size := range []int64{1, 2, 100, 1000} {
+		ranges, err := pickRanges(rng, 50, size)
+		if err != nil {
+			t.Errorf("pickRanges(size: %d) = %v, want nil", size, err)
+		}
+		for _, r := range ranges {
+			if r.offset < 0 || r.length <= 0 || r.offset+r.length > size {
+				t.Errorf("pickRanges(size: %d) returned range [%d, %d), want a non-empty range within [0, %d)", size, r.offset, r.offset+r.length, size)
+			}
+		}
+	}
+	for _, size := range []int64{0, -1} {
+		if _, err := pickRanges(rng, 50, size); err == nil {
+			t.Errorf("pickRanges(size: %d) = nil, want error", size)
+		}
+	}
+}
+
+func TestReadFileRangeModeEmptyFile(t *testing.T) {
+	ctx := context.Background()
+	logger, err := logger.NewCloudproberLog(readTestProbeName)
+	if err != nil {
+		t.Fatalf("failed to initialise logger: %v", err)
+	}
+	target := probetest.NewTarget(t, readTestProbeName, probetest.TargetConfig("test_bucket_probe0"))
+	target.Target.ReadMode = probepb.Target_RANGE
+	bucket := target.Target.GetBucketName()
+	client := fakegcs.NewClient()
+	if err := client.Bucket(bucket).Create(ctx, bucket, nil); err != nil {
+		t.Fatalf("error creating bucket %q: %v", bucket, err)
+	}
+	// A 0-byte object whose metadata is consistent with its size, e.g. a truncated file.
+	fileID := int32(9)
+	fileName := fmt.Sprintf(FileNameFormat, fileID, 7, "sha1", sha1.Sum(nil))
+	w := client.Bucket(bucket).Object(fileName).NewWriter(ctx)
+	w.ObjectAttrs().ContentType = create.ContentType
+	w.ObjectAttrs().Metadata = map[string]string{
+		create.MetadataVersionKey: create.HermesVersion,
+		create.MetadataSeedKey:    "7",
+		create.MetadataSizeKey:    "0",
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to write file %q: %v", fileName, err)
+	}
+	target.Journal.Filenames[fileID] = fileName
+	err = ReadFile(ctx, target, fileID, 0, client, logger)
+	if got := metrics.StatusOf(err); got != metrics.FileCorrupted {
+		t.Errorf("ReadFile(fileID: %d) of a 0-byte file in RANGE mode = %v, want status %q", fileID, err, metrics.ExitStatusName[metrics.FileCorrupted])
+	}
+}
+
+func TestReadFileRangeMode(t *testing.T) {
+	ctx := context.Background()
+	logger, err := logger.NewCloudproberLog(readTestProbeName)
+	if err != nil {
+		t.Fatalf("failed to initialise logger: %v", err)
+	}
+	target := probetest.NewTarget(t, readTestProbeName, probetest.TargetConfig("test_bucket_probe0"))
+	target.Target.ReadMode = probepb.Target_RANGE
+	target.Target.RangeReadsPerFile = 16
+	bucket := target.Target.GetBucketName()
+	client := fakegcs.NewClient()
+	if err := client.Bucket(bucket).Create(ctx, bucket, nil); err != nil {
+		t.Fatalf("error creating bucket %q: %v", bucket, err)
+	}
+	fileID := int32(9)
+	if err := create.CreateFile(ctx, target, fileID, fileSizeBytes, client, logger); err != nil {
+		t.Fatalf("CreateFile(fileID: %d) set up failed %v", fileID, err)
+	}
+	if err := ReadFile(ctx, target, fileID, fileSizeBytes, client, logger); err != nil {
+		t.Errorf("ReadFile(fileID: %d) in RANGE mode = %v, want nil", fileID, err)
+	}
+
+	// Overwrite the file with contents that differ from the expected contents, keeping its metadata.
+	fileName := target.Journal.Filenames[fileID]
+	object := client.Bucket(bucket).Object(fileName)
+	attrs, err := object.Attrs(ctx)
+	if err != nil {
+		t.Fatalf("Attrs(%q) failed: %v", fileName, err)
+	}
+	w := object.NewWriter(ctx)
+	w.ObjectAttrs().ContentType = attrs.ContentType
+	w.ObjectAttrs().Metadata = attrs.Metadata
+	if _, err := w.Write(bytes.Repeat([]byte{0}, fileSizeBytes)); err != nil {
+		t.Fatalf("failed to overwrite file %q: %v", fileName, err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to overwrite file %q: %v", fileName, err)
+	}
+	err = ReadFile(ctx, target, fileID, fileSizeBytes, client, logger)
+	if got := metrics.StatusOf(err); got != metrics.FileCorrupted {
+		t.Errorf("ReadFile(fileID: %d) of a corrupted file in RANGE mode = %v, want status %q", fileID, err, metrics.ExitStatusName[metrics.FileCorrupted])
+	}
 }
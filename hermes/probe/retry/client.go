@@ -0,0 +1,374 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Client implements a storage client that retries the API calls that failed with a transient error.
+
+package retry
+
+import (
+	"bytes"
+	"context"
+
+	"cloud.google.com/go/storage"
+	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+	"github.com/googleinterns/step224-2020/hermes/probe/journal"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+	"google.golang.org/api/iterator"
+
+	cpmetrics "github.com/google/cloudprober/metrics"
+)
+
+const apiAttempts = "hermes_api_attempts_total"
+
+// NewClient returns a storage client that retries the API calls of a target as configured by the policies,
+// and counts every attempt in the API attempts metrics of the target.
+// A write keeps the contents written if its policy retries, so that they can be uploaded again, and a
+// listing is restarted after the last object it returned. Reads and writes of the NIL file are counted
+// as the read_journal and write_journal API calls.
+// Arguments:
+//	- client: the storage client making the API calls.
+//	- policies: the retry policies of the API calls.
+//	- target: the target the API calls are made to.
+// Returns:
+//	- client: returns the retrying client. It counts the attempts without retrying if the policies are nil.
+func NewClient(client stiface.Client, policies *Policies, target *target.Target) stiface.Client {
+	return &retryClient{Client: client, r: &retrier{policies: policies, target: target}}
+}
+
+// retrier retries the API calls of a target.
+type retrier struct {
+	policies *Policies
+	target   *target.Target
+}
+
+// do makes an API call, retrying it as configured by its policy, and records every attempt.
+func (r *retrier) do(ctx context.Context, call metrics.APICall, f func() error) error {
+	return Do(ctx, r.policies.For(call), func(result metrics.AttemptResult) {
+		r.count(call, result)
+	}, f)
+}
+
+// count counts an attempt of an API call with the result given.
+func (r *retrier) count(call metrics.APICall, result metrics.AttemptResult) {
+	r.target.LatencyMetrics.APIAttempts[call][result].Metric(apiAttempts).(*cpmetrics.AtomicInt).Inc()
+}
+
+type retryClient struct {
+	stiface.Client
+	r *retrier
+}
+
+func (c *retryClient) Bucket(name string) stiface.BucketHandle {
+	return &retryBucket{BucketHandle: c.Client.Bucket(name), r: c.r}
+}
+
+type retryBucket struct {
+	stiface.BucketHandle
+	r *retrier
+}
+
+func (b *retryBucket) If(conds storage.BucketConditions) stiface.BucketHandle {
+	return &retryBucket{BucketHandle: b.BucketHandle.If(conds), r: b.r}
+}
+
+func (b *retryBucket) UserProject(projectID string) stiface.BucketHandle {
+	return &retryBucket{BucketHandle: b.BucketHandle.UserProject(projectID), r: b.r}
+}
+
+func (b *retryBucket) Object(name string) stiface.ObjectHandle {
+	o := &retryObject{ObjectHandle: b.BucketHandle.Object(name), r: b.r, read: metrics.APIGetFile, write: metrics.APICreateFile}
+	if name == journal.NilFileName {
+		o.read, o.write = metrics.APIReadJournal, metrics.APIWriteJournal
+	}
+	return o
+}
+
+func (b *retryBucket) Objects(ctx context.Context, q *storage.Query) stiface.ObjectIterator {
+	return &retryIterator{ObjectIterator: b.BucketHandle.Objects(ctx, q), ctx: ctx, bucket: b.BucketHandle, query: q, r: b.r}
+}
+
+// retryIterator restarts a listing that failed with a transient error, skipping the objects it already
+// returned, as the storage system lists objects in lexicographic order. A listing is counted as one
+// attempt once it is done or has failed, and once more for every restart.
+type retryIterator struct {
+	stiface.ObjectIterator
+	ctx    context.Context
+	bucket stiface.BucketHandle
+	query  *storage.Query
+	r      *retrier
+	// last is the name of the last object returned.
+	last string
+	// restarted is true if the listing was restarted, so that the objects up to last are skipped.
+	restarted bool
+	// done is true once the listing is done or has failed.
+	done bool
+}
+
+// next returns the next object of the listing that was not returned yet.
+func (it *retryIterator) next() (*storage.ObjectAttrs, error) {
+	for {
+		attrs, err := it.ObjectIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		name := attrs.Name
+		if name == "" {
+			name = attrs.Prefix
+		}
+		if it.restarted && name <= it.last {
+			continue
+		}
+		it.last = name
+		return attrs, nil
+	}
+}
+
+func (it *retryIterator) Next() (*storage.ObjectAttrs, error) {
+	if it.done {
+		return it.ObjectIterator.Next()
+	}
+	var attrs *storage.ObjectAttrs
+	attempt := 0
+	listed := false
+	err := Do(it.ctx, it.r.policies.For(metrics.APIListFiles), func(result metrics.AttemptResult) {
+		if result != metrics.AttemptSuccess || listed {
+			it.r.count(metrics.APIListFiles, result)
+		}
+	}, func() error {
+		attempt++
+		if attempt > 1 {
+			it.ObjectIterator = it.bucket.Objects(it.ctx, it.query)
+			it.restarted = true
+		}
+		var err error
+		attrs, err = it.next()
+		if err == iterator.Done {
+			listed = true
+			return nil
+		}
+		return err
+	})
+	if listed {
+		it.done = true
+		return nil, iterator.Done
+	}
+	if err != nil {
+		it.done = true
+	}
+	return attrs, err
+}
+
+type retryObject struct {
+	stiface.ObjectHandle
+	r *retrier
+	// read and write are the API calls the reads and writes of the object are counted as.
+	read, write metrics.APICall
+}
+
+// unwrap returns the handle of the underlying client, which it expects as the source of copies.
+func unwrap(o stiface.ObjectHandle) stiface.ObjectHandle {
+	if r, ok := o.(*retryObject); ok {
+		return r.ObjectHandle
+	}
+	return o
+}
+
+func (o *retryObject) wrap(h stiface.ObjectHandle) stiface.ObjectHandle {
+	return &retryObject{ObjectHandle: h, r: o.r, read: o.read, write: o.write}
+}
+
+func (o *retryObject) Generation(gen int64) stiface.ObjectHandle {
+	return o.wrap(o.ObjectHandle.Generation(gen))
+}
+
+func (o *retryObject) If(conds storage.Conditions) stiface.ObjectHandle {
+	return o.wrap(o.ObjectHandle.If(conds))
+}
+
+func (o *retryObject) Key(key []byte) stiface.ObjectHandle {
+	return o.wrap(o.ObjectHandle.Key(key))
+}
+
+func (o *retryObject) ReadCompressed(compressed bool) stiface.ObjectHandle {
+	return o.wrap(o.ObjectHandle.ReadCompressed(compressed))
+}
+
+func (o *retryObject) Attrs(ctx context.Context) (*storage.ObjectAttrs, error) {
+	var attrs *storage.ObjectAttrs
+	err := o.r.do(ctx, metrics.APIGetFileAttrs, func() error {
+		var err error
+		attrs, err = o.ObjectHandle.Attrs(ctx)
+		return err
+	})
+	return attrs, err
+}
+
+func (o *retryObject) NewReader(ctx context.Context) (stiface.Reader, error) {
+	var r stiface.Reader
+	err := o.r.do(ctx, o.read, func() error {
+		var err error
+		r, err = o.ObjectHandle.NewReader(ctx)
+		return err
+	})
+	return r, err
+}
+
+func (o *retryObject) NewRangeReader(ctx context.Context, offset, length int64) (stiface.Reader, error) {
+	var r stiface.Reader
+	err := o.r.do(ctx, metrics.APIGetFileRange, func() error {
+		var err error
+		r, err = o.ObjectHandle.NewRangeReader(ctx, offset, length)
+		return err
+	})
+	return r, err
+}
+
+func (o *retryObject) Delete(ctx context.Context) error {
+	attempt := 0
+	return o.r.do(ctx, metrics.APIDeleteFile, func() error {
+		attempt++
+		err := o.ObjectHandle.Delete(ctx)
+		if attempt > 1 && err == storage.ErrObjectNotExist {
+			// An earlier attempt deleted the object but its response was lost.
+			return nil
+		}
+		return err
+	})
+}
+
+func (o *retryObject) NewWriter(ctx context.Context) stiface.Writer {
+	w := &retryWriter{Writer: o.ObjectHandle.NewWriter(ctx), ctx: ctx, object: o.ObjectHandle, r: o.r, call: o.write}
+	if o.r.policies.For(o.write).MaxAttempts > 1 {
+		w.buf = &bytes.Buffer{}
+	}
+	return w
+}
+
+// retryWriter uploads the contents written again with a new writer if the upload failed with a transient error.
+// An error of a write that is retried is returned once the writer is closed. A write is counted as one attempt
+// once it has failed or has been closed, and once more for every upload.
+type retryWriter struct {
+	stiface.Writer
+	ctx    context.Context
+	object stiface.ObjectHandle
+	r      *retrier
+	call   metrics.APICall
+	// buf holds the contents written, nil if the policy of the write does not retry.
+	buf *bytes.Buffer
+	// settings replays the settings of the writer on the writer of every upload.
+	settings []func(stiface.Writer)
+	// pending is the transient error the upload failed with while it was written to.
+	pending error
+	// failed is true once a write failed with an error that is not retried, which was returned.
+	failed bool
+}
+
+func (w *retryWriter) SetChunkSize(size int) {
+	w.settings = append(w.settings, func(nw stiface.Writer) { nw.SetChunkSize(size) })
+	w.Writer.SetChunkSize(size)
+}
+
+func (w *retryWriter) SetProgressFunc(f func(int64)) {
+	w.settings = append(w.settings, func(nw stiface.Writer) { nw.SetProgressFunc(f) })
+	w.Writer.SetProgressFunc(f)
+}
+
+func (w *retryWriter) SetCRC32C(sum uint32) {
+	w.settings = append(w.settings, func(nw stiface.Writer) { nw.SetCRC32C(sum) })
+	w.Writer.SetCRC32C(sum)
+}
+
+func (w *retryWriter) Write(p []byte) (int, error) {
+	if w.failed {
+		return w.Writer.Write(p)
+	}
+	if w.pending != nil {
+		return w.buf.Write(p)
+	}
+	n, err := w.Writer.Write(p)
+	if err == nil {
+		if w.buf != nil {
+			w.buf.Write(p)
+		}
+		return n, nil
+	}
+	if w.buf != nil && w.r.policies.For(w.call).retries(err) {
+		w.pending = err
+		return w.buf.Write(p)
+	}
+	w.failed = true
+	w.r.count(w.call, resultOf(err))
+	return n, err
+}
+
+// upload uploads the contents written with a new writer, which replaces the writer.
+func (w *retryWriter) upload() error {
+	nw := w.object.NewWriter(w.ctx)
+	*nw.ObjectAttrs() = *w.Writer.ObjectAttrs()
+	for _, set := range w.settings {
+		set(nw)
+	}
+	w.Writer = nw
+	if _, err := nw.Write(w.buf.Bytes()); err != nil {
+		nw.Close()
+		return err
+	}
+	return nw.Close()
+}
+
+func (w *retryWriter) Close() error {
+	if w.failed {
+		return w.Writer.Close()
+	}
+	attempt := 0
+	return w.r.do(w.ctx, w.call, func() error {
+		attempt++
+		if attempt > 1 {
+			return w.upload()
+		}
+		if w.pending != nil {
+			w.Writer.Close()
+			return w.pending
+		}
+		return w.Writer.Close()
+	})
+}
+
+func (o *retryObject) CopierFrom(src stiface.ObjectHandle) stiface.Copier {
+	return &retryCopier{Copier: o.ObjectHandle.CopierFrom(unwrap(src)), r: o.r}
+}
+
+func (o *retryObject) ComposerFrom(srcs ...stiface.ObjectHandle) stiface.Composer {
+	unwrapped := make([]stiface.ObjectHandle, len(srcs))
+	for i, src := range srcs {
+		unwrapped[i] = unwrap(src)
+	}
+	return o.ObjectHandle.ComposerFrom(unwrapped...)
+}
+
+type retryCopier struct {
+	stiface.Copier
+	r *retrier
+}
+
+func (c *retryCopier) Run(ctx context.Context) (*storage.ObjectAttrs, error) {
+	var attrs *storage.ObjectAttrs
+	err := c.r.do(ctx, metrics.APICopyFile, func() error {
+		var err error
+		attrs, err = c.Copier.Run(ctx)
+		return err
+	})
+	return attrs, err
+}
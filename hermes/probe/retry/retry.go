@@ -0,0 +1,221 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retry implements the retries of the API calls that failed with a
+// transient error, so that a single failed request is not reported as an outage.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"google.golang.org/api/googleapi"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+const (
+	defaultInitialBackoff    = 100 * time.Millisecond
+	defaultMaxBackoff        = 5 * time.Second
+	defaultBackoffMultiplier = 2
+)
+
+// Policy holds the number of attempts, the backoff and the classes of errors retried for an API call.
+type Policy struct {
+	// MaxAttempts is the maximum number of attempts, including the first attempt.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay between two attempts.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the delay is multiplied by after every retry.
+	Multiplier float64
+	// Retryable holds the classes of transient errors that are retried.
+	Retryable map[probepb.RetryPolicy_ErrorClass]bool
+}
+
+// retries reports whether the policy retries an API call that failed with the error given.
+func (p *Policy) retries(err error) bool {
+	class, transient := Classify(err)
+	return transient && p.Retryable[class] && p.MaxAttempts > 1
+}
+
+// noRetry is the policy of the API calls without a retry policy.
+var noRetry = &Policy{MaxAttempts: 1}
+
+// NewPolicy creates a new *Policy from the config given, applying the default of every unset field.
+func NewPolicy(conf *probepb.RetryPolicy) *Policy {
+	p := &Policy{
+		MaxAttempts:    1,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		Multiplier:     defaultBackoffMultiplier,
+		Retryable:      make(map[probepb.RetryPolicy_ErrorClass]bool),
+	}
+	if n := conf.GetMaxAttempts(); n > 1 {
+		p.MaxAttempts = int(n)
+	}
+	if ms := conf.GetInitialBackoffMs(); ms > 0 {
+		p.InitialBackoff = time.Duration(ms) * time.Millisecond
+	}
+	if ms := conf.GetMaxBackoffMs(); ms > 0 {
+		p.MaxBackoff = time.Duration(ms) * time.Millisecond
+	}
+	if m := conf.GetBackoffMultiplier(); m >= 1 {
+		p.Multiplier = m
+	}
+	classes := conf.GetRetryableErrors()
+	if len(classes) == 0 {
+		classes = []probepb.RetryPolicy_ErrorClass{probepb.RetryPolicy_RATE_LIMITED, probepb.RetryPolicy_SERVER_ERROR, probepb.RetryPolicy_NETWORK_ERROR}
+	}
+	for _, c := range classes {
+		p.Retryable[c] = true
+	}
+	return p
+}
+
+// Policies holds the retry policy of every API call. A nil *Policies does not retry any API call.
+type Policies struct {
+	calls map[metrics.APICall]*Policy
+	// fallback is the policy of the API calls without a policy of their own.
+	fallback *Policy
+}
+
+// NewPolicies creates the retry policies of the API calls from the configs given.
+// Arguments:
+//	- confs: the retry policies, at most one per API call and one without an API call.
+// Returns:
+//	- policies: returns the policies, or nil if none of them retries.
+//	- err: returns an error if a policy names an unknown API call or an API call has two policies.
+func NewPolicies(confs []*probepb.RetryPolicy) (*Policies, error) {
+	names := make(map[string]metrics.APICall, len(metrics.APICallName))
+	for call, name := range metrics.APICallName {
+		names[name] = call
+	}
+	p := &Policies{calls: make(map[metrics.APICall]*Policy), fallback: noRetry}
+	retries := false
+	seen := make(map[string]bool)
+	for _, conf := range confs {
+		name := conf.GetApiCall()
+		if seen[name] {
+			return nil, fmt.Errorf("invalid retry policy: api_call %q has more than one policy", name)
+		}
+		seen[name] = true
+		if conf.GetMaxAttempts() < 0 {
+			return nil, fmt.Errorf("invalid retry policy: api_call %q: max_attempts = %d; want a non-negative value", name, conf.GetMaxAttempts())
+		}
+		policy := NewPolicy(conf)
+		retries = retries || policy.MaxAttempts > 1
+		if name == "" {
+			p.fallback = policy
+			continue
+		}
+		call, ok := names[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid retry policy: unknown api_call %q", name)
+		}
+		p.calls[call] = policy
+	}
+	if !retries {
+		return nil, nil
+	}
+	return p, nil
+}
+
+// For returns the retry policy of an API call.
+func (p *Policies) For(call metrics.APICall) *Policy {
+	if p == nil {
+		return noRetry
+	}
+	if policy, ok := p.calls[call]; ok {
+		return policy
+	}
+	return p.fallback
+}
+
+// Classify returns the class of a transient error.
+// Arguments:
+//	- err: the error an API call failed with.
+// Returns:
+//	- class: returns the class of the error if it is transient.
+//	- ok: returns false if the error is not transient, e.g. a missing object or a cancelled context.
+func Classify(err error) (probepb.RetryPolicy_ErrorClass, bool) {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return probepb.RetryPolicy_ERROR_CLASS_UNSPECIFIED, false
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.Code == 429:
+			return probepb.RetryPolicy_RATE_LIMITED, true
+		case apiErr.Code >= 500:
+			return probepb.RetryPolicy_SERVER_ERROR, true
+		}
+		return probepb.RetryPolicy_ERROR_CLASS_UNSPECIFIED, false
+	}
+	var netErr net.Error
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.As(err, &netErr) {
+		return probepb.RetryPolicy_NETWORK_ERROR, true
+	}
+	return probepb.RetryPolicy_ERROR_CLASS_UNSPECIFIED, false
+}
+
+// resultOf returns the result of an attempt of an API call that failed with the error given, nil on success.
+func resultOf(err error) metrics.AttemptResult {
+	if err == nil {
+		return metrics.AttemptSuccess
+	}
+	if _, transient := Classify(err); transient {
+		return metrics.AttemptTransientError
+	}
+	return metrics.AttemptError
+}
+
+// Do calls f until it succeeds, fails with an error that the policy does not retry, or every attempt
+// of the policy failed. The result of every attempt is passed to record.
+// Arguments:
+//	- ctx: the context of the API call, the retries stop if it is done.
+//	- policy: the retry policy of the API call.
+//	- record: records the result of an attempt, it may be nil.
+//	- f: makes one attempt of the API call.
+// Returns:
+//	- err: returns the error of the last attempt, nil if it succeeded.
+func Do(ctx context.Context, policy *Policy, record func(metrics.AttemptResult), f func() error) error {
+	backoff := policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if record != nil {
+			record(resultOf(err))
+		}
+		if !policy.retries(err) || attempt >= policy.MaxAttempts {
+			return err
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		if backoff = time.Duration(float64(backoff) * policy.Multiplier); backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
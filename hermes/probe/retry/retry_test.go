@@ -0,0 +1,464 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"syscall"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"github.com/golang/protobuf/proto"
+	"github.com/googleapis/google-cloud-go-testing/storage/stiface"
+	"github.com/googleinterns/step224-2020/hermes/probe/fakegcs"
+	"github.com/googleinterns/step224-2020/hermes/probe/journal"
+	"github.com/googleinterns/step224-2020/hermes/probe/metrics"
+	"github.com/googleinterns/step224-2020/hermes/probe/probetest"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+
+	cpmetrics "github.com/google/cloudprober/metrics"
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+const bucketName = "test_bucket_retry"
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		desc          string
+		err           error
+		wantClass     probepb.RetryPolicy_ErrorClass
+		wantTransient bool
+	}{
+		{desc: "no error"},
+		{desc: "service unavailable", err: &googleapi.Error{Code: 503}, wantClass: probepb.RetryPolicy_SERVER_ERROR, wantTransient: true},
+		{desc: "wrapped internal error", err: fmt.Errorf("Attrs() failed: %w", &googleapi.Error{Code: 500}), wantClass: probepb.RetryPolicy_SERVER_ERROR, wantTransient: true},
+		{desc: "too many requests", err: &googleapi.Error{Code: 429}, wantClass: probepb.RetryPolicy_RATE_LIMITED, wantTransient: true},
+		{desc: "precondition failed", err: &googleapi.Error{Code: 412}},
+		{desc: "object not found", err: storage.ErrObjectNotExist},
+		{desc: "connection reset", err: fmt.Errorf("read: %w", syscall.ECONNRESET), wantClass: probepb.RetryPolicy_NETWORK_ERROR, wantTransient: true},
+		{desc: "unexpected EOF", err: io.ErrUnexpectedEOF, wantClass: probepb.RetryPolicy_NETWORK_ERROR, wantTransient: true},
+		{desc: "deadline exceeded", err: context.DeadlineExceeded},
+		{desc: "cancelled", err: context.Canceled},
+	}
+	for _, tc := range tests {
+		class, transient := Classify(tc.err)
+		if class != tc.wantClass || transient != tc.wantTransient {
+			t.Errorf("%s: Classify(%v) = %v, %t, want %v, %t", tc.desc, tc.err, class, transient, tc.wantClass, tc.wantTransient)
+		}
+	}
+}
+
+func TestDo(t *testing.T) {
+	unavailable := &googleapi.Error{Code: 503}
+	notFound := &googleapi.Error{Code: 404}
+	throttled := &googleapi.Error{Code: 429}
+	tests := []struct {
+		desc        string
+		conf        *probepb.RetryPolicy
+		errs        []error
+		wantErr     error
+		wantResults string
+	}{
+		{
+			desc:        "success",
+			conf:        &probepb.RetryPolicy{MaxAttempts: proto.Int32(3)},
+			errs:        []error{nil},
+			wantResults: "[success]",
+		},
+		{
+			desc:        "transient errors are retried",
+			conf:        &probepb.RetryPolicy{MaxAttempts: proto.Int32(3), InitialBackoffMs: proto.Int32(1)},
+			errs:        []error{unavailable, unavailable, nil},
+			wantResults: "[transient_error transient_error success]",
+		},
+		{
+			desc:        "attempts are exhausted",
+			conf:        &probepb.RetryPolicy{MaxAttempts: proto.Int32(2), InitialBackoffMs: proto.Int32(1)},
+			errs:        []error{unavailable, unavailable, nil},
+			wantErr:     unavailable,
+			wantResults: "[transient_error transient_error]",
+		},
+		{
+			desc:        "permanent errors are not retried",
+			conf:        &probepb.RetryPolicy{MaxAttempts: proto.Int32(3), InitialBackoffMs: proto.Int32(1)},
+			errs:        []error{notFound, nil},
+			wantErr:     notFound,
+			wantResults: "[error]",
+		},
+		{
+			desc: "classes that are not retryable are not retried",
+			conf: &probepb.RetryPolicy{
+				MaxAttempts:      proto.Int32(3),
+				InitialBackoffMs: proto.Int32(1),
+				RetryableErrors:  []probepb.RetryPolicy_ErrorClass{probepb.RetryPolicy_SERVER_ERROR},
+			},
+			errs:        []error{throttled, nil},
+			wantErr:     throttled,
+			wantResults: "[transient_error]",
+		},
+		{
+			desc:        "no retries by default",
+			conf:        &probepb.RetryPolicy{},
+			errs:        []error{unavailable, nil},
+			wantErr:     unavailable,
+			wantResults: "[transient_error]",
+		},
+	}
+	for _, tc := range tests {
+		var results []string
+		attempt := 0
+		err := Do(context.Background(), NewPolicy(tc.conf), func(r metrics.AttemptResult) {
+			results = append(results, metrics.AttemptResultName[r])
+		}, func() error {
+			err := tc.errs[attempt]
+			attempt++
+			return err
+		})
+		if err != tc.wantErr {
+			t.Errorf("%s: Do() = %v, want %v", tc.desc, err, tc.wantErr)
+		}
+		if got := fmt.Sprint(results); got != tc.wantResults {
+			t.Errorf("%s: Do() recorded attempts %s, want %s", tc.desc, got, tc.wantResults)
+		}
+	}
+}
+
+func TestNewPolicies(t *testing.T) {
+	if p, err := NewPolicies([]*probepb.RetryPolicy{{ApiCall: proto.String("get_file")}}); p != nil || err != nil {
+		t.Errorf("NewPolicies() without retries = %v, %v, want nil, nil", p, err)
+	}
+	for _, confs := range [][]*probepb.RetryPolicy{
+		{{ApiCall: proto.String("no_such_call"), MaxAttempts: proto.Int32(2)}},
+		{{ApiCall: proto.String("get_file"), MaxAttempts: proto.Int32(2)}, {ApiCall: proto.String("get_file")}},
+		{{MaxAttempts: proto.Int32(-1)}},
+	} {
+		if _, err := NewPolicies(confs); err == nil {
+			t.Errorf("NewPolicies(%v) succeeded, want an error", confs)
+		}
+	}
+
+	p, err := NewPolicies([]*probepb.RetryPolicy{
+		{MaxAttempts: proto.Int32(3)},
+		{ApiCall: proto.String("delete_file"), MaxAttempts: proto.Int32(1)},
+	})
+	if err != nil {
+		t.Fatalf("NewPolicies() failed: %v", err)
+	}
+	if got := p.For(metrics.APIGetFile).MaxAttempts; got != 3 {
+		t.Errorf("For(get_file).MaxAttempts = %d, want the default policy of 3 attempts", got)
+	}
+	if got := p.For(metrics.APIDeleteFile).MaxAttempts; got != 1 {
+		t.Errorf("For(delete_file).MaxAttempts = %d, want the policy of delete_file of 1 attempt", got)
+	}
+}
+
+// flakyClient fails the first attempts of the attribute reads and deletes of every object with HTTP 503.
+// A flaky delete deletes the object before failing, as if its response was lost.
+// The first uploads of every object fail when they are closed.
+type flakyClient struct {
+	stiface.Client
+	failures      int
+	closeFailures int
+}
+
+func (c *flakyClient) Bucket(name string) stiface.BucketHandle {
+	return &flakyBucket{BucketHandle: c.Client.Bucket(name), c: c}
+}
+
+type flakyBucket struct {
+	stiface.BucketHandle
+	c *flakyClient
+}
+
+func (b *flakyBucket) Object(name string) stiface.ObjectHandle {
+	return &flakyObject{ObjectHandle: b.BucketHandle.Object(name), c: b.c}
+}
+
+type flakyObject struct {
+	stiface.ObjectHandle
+	c *flakyClient
+}
+
+func (o *flakyObject) Attrs(ctx context.Context) (*storage.ObjectAttrs, error) {
+	if o.c.failures > 0 {
+		o.c.failures--
+		return nil, &googleapi.Error{Code: 503}
+	}
+	return o.ObjectHandle.Attrs(ctx)
+}
+
+func (o *flakyObject) Delete(ctx context.Context) error {
+	err := o.ObjectHandle.Delete(ctx)
+	if o.c.failures > 0 {
+		o.c.failures--
+		return &googleapi.Error{Code: 503}
+	}
+	return err
+}
+
+func (o *flakyObject) NewWriter(ctx context.Context) stiface.Writer {
+	return &flakyWriter{Writer: o.ObjectHandle.NewWriter(ctx), c: o.c}
+}
+
+type flakyWriter struct {
+	stiface.Writer
+	c *flakyClient
+}
+
+func (w *flakyWriter) Close() error {
+	if w.c.closeFailures > 0 {
+		w.c.closeFailures--
+		return &googleapi.Error{Code: 503}
+	}
+	return w.Writer.Close()
+}
+
+// listBucket lists its objects in order. The first listing fails with HTTP 503 after failAfter objects.
+type listBucket struct {
+	stiface.BucketHandle
+	names     []string
+	failAfter int
+	listings  int
+}
+
+func (b *listBucket) Objects(ctx context.Context, q *storage.Query) stiface.ObjectIterator {
+	b.listings++
+	return &listIterator{b: b, fail: b.listings == 1}
+}
+
+type listIterator struct {
+	stiface.ObjectIterator
+	b    *listBucket
+	next int
+	fail bool
+}
+
+func (it *listIterator) Next() (*storage.ObjectAttrs, error) {
+	if it.fail && it.next == it.b.failAfter {
+		return nil, &googleapi.Error{Code: 503}
+	}
+	if it.next == len(it.b.names) {
+		return nil, iterator.Done
+	}
+	it.next++
+	return &storage.ObjectAttrs{Name: it.b.names[it.next-1]}, nil
+}
+
+type listClient struct {
+	stiface.Client
+	b *listBucket
+}
+
+func (c *listClient) Bucket(name string) stiface.BucketHandle {
+	return c.b
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	fake := fakegcs.NewClient()
+	if err := fake.Bucket(bucketName).Create(ctx, bucketName, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	w := fake.Bucket(bucketName).Object("Hermes_01_a").NewWriter(ctx)
+	if _, err := w.Write([]byte("contents")); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	target := probetest.NewTarget(t, "retry_test", probetest.TargetConfig(bucketName))
+	attempts := func(call metrics.APICall, r metrics.AttemptResult) int64 {
+		return target.LatencyMetrics.APIAttempts[call][r].Metric(apiAttempts).(*cpmetrics.AtomicInt).Int64()
+	}
+	// Without policies, the attempts are counted but not retried.
+	if _, err := NewClient(&flakyClient{Client: fake, failures: 1}, nil, target).Bucket(bucketName).Object("Hermes_01_a").Attrs(ctx); err == nil {
+		t.Errorf("Attrs() without policies after a transient error succeeded, want the error")
+	}
+	if got, want := attempts(metrics.APIGetFileAttrs, metrics.AttemptTransientError), int64(1); got != want {
+		t.Errorf("Attrs() without policies recorded %d transient errors, want %d", got, want)
+	}
+	target = probetest.NewTarget(t, "retry_test", probetest.TargetConfig(bucketName))
+	policies, err := NewPolicies([]*probepb.RetryPolicy{{MaxAttempts: proto.Int32(3), InitialBackoffMs: proto.Int32(1)}})
+	if err != nil {
+		t.Fatalf("NewPolicies() failed: %v", err)
+	}
+	flaky := &flakyClient{Client: fake, failures: 2}
+	object := NewClient(flaky, policies, target).Bucket(bucketName).Object("Hermes_01_a")
+	if _, err := object.Attrs(ctx); err != nil {
+		t.Errorf("Attrs() after two transient errors = %v, want it retried until it succeeds", err)
+	}
+	if got, want := attempts(metrics.APIGetFileAttrs, metrics.AttemptTransientError), int64(2); got != want {
+		t.Errorf("Attrs() recorded %d transient errors, want %d", got, want)
+	}
+	if got, want := attempts(metrics.APIGetFileAttrs, metrics.AttemptSuccess), int64(1); got != want {
+		t.Errorf("Attrs() recorded %d successful attempts, want %d", got, want)
+	}
+
+	// The first attempt deletes the object but fails, so the retry finds it missing.
+	flaky.failures = 1
+	if err := object.Delete(ctx); err != nil {
+		t.Errorf("Delete() whose first response was lost = %v, want nil", err)
+	}
+	if _, err := fake.Bucket(bucketName).Object("Hermes_01_a").Attrs(ctx); err != storage.ErrObjectNotExist {
+		t.Errorf("Attrs() after Delete() = %v, want %v", err, storage.ErrObjectNotExist)
+	}
+	if err := object.Delete(ctx); err != storage.ErrObjectNotExist {
+		t.Errorf("Delete() of a missing object = %v, want %v", err, storage.ErrObjectNotExist)
+	}
+}
+
+func TestClientCountsWritesAndListings(t *testing.T) {
+	ctx := context.Background()
+	fake := fakegcs.NewClient()
+	if err := fake.Bucket(bucketName).Create(ctx, bucketName, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	target := probetest.NewTarget(t, "retry_test", probetest.TargetConfig(bucketName))
+	client := NewClient(fake, nil, target)
+	attempts := func(call metrics.APICall, r metrics.AttemptResult) int64 {
+		return target.LatencyMetrics.APIAttempts[call][r].Metric(apiAttempts).(*cpmetrics.AtomicInt).Int64()
+	}
+
+	w := client.Bucket(bucketName).Object("Hermes_01_a").NewWriter(ctx)
+	for i := 0; i < 3; i++ {
+		if _, err := w.Write([]byte("contents")); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	if got, want := attempts(metrics.APICreateFile, metrics.AttemptSuccess), int64(1); got != want {
+		t.Errorf("a write recorded %d successful attempts, want %d", got, want)
+	}
+
+	objects := client.Bucket(bucketName).Objects(ctx, nil)
+	for {
+		if _, err := objects.Next(); err != nil {
+			break
+		}
+	}
+	objects.Next()
+	if got, want := attempts(metrics.APIListFiles, metrics.AttemptSuccess), int64(1); got != want {
+		t.Errorf("a listing recorded %d successful attempts, want %d", got, want)
+	}
+	client.Bucket("missing_bucket").Objects(ctx, nil).Next()
+	if got, want := attempts(metrics.APIListFiles, metrics.AttemptError), int64(1); got != want {
+		t.Errorf("a failed listing recorded %d failed attempts, want %d", got, want)
+	}
+}
+
+func TestClientRetriesWritesAndListings(t *testing.T) {
+	ctx := context.Background()
+	fake := fakegcs.NewClient()
+	if err := fake.Bucket(bucketName).Create(ctx, bucketName, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	target := probetest.NewTarget(t, "retry_test", probetest.TargetConfig(bucketName))
+	attempts := func(call metrics.APICall, r metrics.AttemptResult) int64 {
+		return target.LatencyMetrics.APIAttempts[call][r].Metric(apiAttempts).(*cpmetrics.AtomicInt).Int64()
+	}
+	policies, err := NewPolicies([]*probepb.RetryPolicy{{MaxAttempts: proto.Int32(3), InitialBackoffMs: proto.Int32(1)}})
+	if err != nil {
+		t.Fatalf("NewPolicies() failed: %v", err)
+	}
+
+	// The first upload fails when it is closed, so the contents are uploaded again.
+	client := NewClient(&flakyClient{Client: fake, closeFailures: 1}, policies, target)
+	w := client.Bucket(bucketName).Object("Hermes_01_a").NewWriter(ctx)
+	for _, part := range []string{"con", "tents"} {
+		if _, err := w.Write([]byte(part)); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() after a transient error = %v, want the upload retried", err)
+	}
+	if w.Attrs() == nil {
+		t.Errorf("Attrs() after a retried upload = nil, want the attributes of the object uploaded")
+	}
+	r, err := fake.Bucket(bucketName).Object("Hermes_01_a").NewReader(ctx)
+	if err != nil {
+		t.Fatalf("NewReader() failed: %v", err)
+	}
+	defer r.Close()
+	if data, err := ioutil.ReadAll(r); err != nil || string(data) != "contents" {
+		t.Errorf("ReadAll() after a retried upload = %q, %v, want %q", data, err, "contents")
+	}
+	if got := attempts(metrics.APICreateFile, metrics.AttemptTransientError) + attempts(metrics.APICreateFile, metrics.AttemptSuccess); got != 2 {
+		t.Errorf("a retried write recorded %d attempts, want 2", got)
+	}
+
+	// The first listing fails after two objects, so the listing is restarted after them.
+	bucket := &listBucket{names: []string{"Hermes_01_a", "Hermes_02_b", "Hermes_03_c"}, failAfter: 2}
+	objects := NewClient(&listClient{b: bucket}, policies, target).Bucket(bucketName).Objects(ctx, nil)
+	var names []string
+	for {
+		attrs, err := objects.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() after a transient error = %v, want the listing restarted", err)
+		}
+		names = append(names, attrs.Name)
+	}
+	if got, want := fmt.Sprint(names), fmt.Sprint(bucket.names); got != want {
+		t.Errorf("a restarted listing listed %s, want %s", got, want)
+	}
+	if got, want := attempts(metrics.APIListFiles, metrics.AttemptTransientError), int64(1); got != want {
+		t.Errorf("a restarted listing recorded %d transient errors, want %d", got, want)
+	}
+	if got, want := attempts(metrics.APIListFiles, metrics.AttemptSuccess), int64(1); got != want {
+		t.Errorf("a restarted listing recorded %d successful attempts, want %d", got, want)
+	}
+}
+
+func TestClientCountsJournalCalls(t *testing.T) {
+	ctx := context.Background()
+	fake := fakegcs.NewClient()
+	if err := fake.Bucket(bucketName).Create(ctx, bucketName, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	target := probetest.NewTarget(t, "retry_test", probetest.TargetConfig(bucketName))
+	attempts := func(call metrics.APICall, r metrics.AttemptResult) int64 {
+		return target.LatencyMetrics.APIAttempts[call][r].Metric(apiAttempts).(*cpmetrics.AtomicInt).Int64()
+	}
+	client := NewClient(fake, nil, target)
+	if err := journal.Write(ctx, target, client); err != nil {
+		t.Fatalf("journal.Write() failed: %v", err)
+	}
+	if _, err := journal.Read(ctx, target, client); err != nil {
+		t.Fatalf("journal.Read() failed: %v", err)
+	}
+	for _, c := range []struct {
+		call metrics.APICall
+		want int64
+	}{
+		{metrics.APIWriteJournal, 1},
+		{metrics.APIReadJournal, 1},
+		{metrics.APICreateFile, 0},
+		{metrics.APIGetFile, 0},
+	} {
+		if got := attempts(c.call, metrics.AttemptSuccess); got != c.want {
+			t.Errorf("reading and writing the journal recorded %d attempts of %s, want %d", got, metrics.APICallName[c.call], c.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schedule implements the schedule on which each target is probed.
+// Targets start at a random phase offset within their interval and every
+// interval is randomised by a jitter, so that targets sharing a backend do not
+// send their API calls in synchronised bursts.
+package schedule
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Schedule holds the interval between the runs of a target and the jitter applied to it.
+// A Schedule is not safe for concurrent use, each target is scheduled by a single goroutine.
+type Schedule struct {
+	interval time.Duration
+	jitter   float64
+	rng      *rand.Rand
+}
+
+// New returns the schedule of a target.
+// Arguments:
+//	- interval: the mean time between the start of two runs of the target.
+//	- jitter: the fraction of the interval by which each interval is randomly lengthened or shortened, in [0, 1).
+//	- seed: the seed of the random offset and jitter.
+// Returns:
+//	- schedule: returns the schedule of the target.
+//	- err: returns an error if the interval is not positive or the jitter is out of range.
+func New(interval time.Duration, jitter float64, seed int64) (*Schedule, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid schedule: interval = %v; want a positive interval", interval)
+	}
+	if jitter < 0 || jitter >= 1 {
+		return nil, fmt.Errorf("invalid schedule: jitter = %v; want a jitter in [0, 1)", jitter)
+	}
+	return &Schedule{interval: interval, jitter: jitter, rng: rand.New(rand.NewSource(seed))}, nil
+}
+
+// Interval returns the mean time between the start of two runs.
+func (s *Schedule) Interval() time.Duration {
+	return s.interval
+}
+
+// Offset returns the time until the first run, uniformly distributed in [0, interval).
+func (s *Schedule) Offset() time.Duration {
+	return time.Duration(s.rng.Int63n(int64(s.interval)))
+}
+
+// Next returns the time until the next run, uniformly distributed in
+// [interval * (1 - jitter), interval * (1 + jitter)].
+func (s *Schedule) Next() time.Duration {
+	if s.jitter == 0 {
+		return s.interval
+	}
+	factor := 1 + s.jitter*(2*s.rng.Float64()-1)
+	return time.Duration(float64(s.interval) * factor)
+}
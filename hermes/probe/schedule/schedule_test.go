@@ -0,0 +1,77 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		desc     string
+		interval time.Duration
+		jitter   float64
+		wantErr  bool
+	}{
+		{desc: "valid schedule", interval: time.Minute, jitter: 0.2},
+		{desc: "no jitter", interval: time.Minute},
+		{desc: "zero interval", interval: 0, wantErr: true},
+		{desc: "negative jitter", interval: time.Minute, jitter: -0.1, wantErr: true},
+		{desc: "jitter of a whole interval", interval: time.Minute, jitter: 1, wantErr: true},
+	}
+	for _, tc := range tests {
+		if _, err := New(tc.interval, tc.jitter, 1); (err != nil) != tc.wantErr {
+			t.Errorf("%s: New(%v, %v) = %v, want error %t", tc.desc, tc.interval, tc.jitter, err, tc.wantErr)
+		}
+	}
+}
+
+func TestOffsetAndNext(t *testing.T) {
+	interval := time.Minute
+	jitter := 0.25
+	s, err := New(interval, jitter, 42)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	min, max := time.Duration(float64(interval)*(1-jitter)), time.Duration(float64(interval)*(1+jitter))
+	offsets := make(map[time.Duration]bool)
+	nexts := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		offset := s.Offset()
+		if offset < 0 || offset >= interval {
+			t.Errorf("Offset() = %v, want an offset in [0, %v)", offset, interval)
+		}
+		offsets[offset] = true
+		next := s.Next()
+		if next < min || next > max {
+			t.Errorf("Next() = %v, want an interval in [%v, %v]", next, min, max)
+		}
+		nexts[next] = true
+	}
+	// The offsets and intervals are randomised rather than constant.
+	if len(offsets) < 90 || len(nexts) < 90 {
+		t.Errorf("Offset() and Next() returned %d and %d distinct values in 100 calls, want randomised values", len(offsets), len(nexts))
+	}
+
+	fixed, err := New(interval, 0, 42)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if got := fixed.Next(); got != interval {
+		t.Errorf("Next() without jitter = %v, want %v", got, interval)
+	}
+}
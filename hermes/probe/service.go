@@ -0,0 +1,346 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Service implements the control-plane RPCs of the Hermes service that are answered by the probe.
+
+package probe
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/googleinterns/step224-2020/hermes/probe/maintenance"
+	"github.com/googleinterns/step224-2020/hermes/probe/target"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+// The probe answers the RPCs of the Hermes service, see RegisterHermesServer.
+var _ probepb.HermesServer = (*Probe)(nil)
+
+// monitoredTargets returns every target of the probe with the shard owning it.
+func (p *Probe) monitoredTargets() []*probepb.MonitoredTarget {
+	var monitored []*probepb.MonitoredTarget
+	for _, t := range p.targets {
+		owner, local := p.owner(t)
+		monitored = append(monitored, &probepb.MonitoredTarget{Target: t.Target, Shard: owner, Local: local})
+	}
+	return monitored
+}
+
+// ListMonitoredStorageSystems lists the targets of the probe and the shard owning each of them.
+// Arguments:
+//	- ctx: the context of the RPC.
+//	- req: the request, which has no fields.
+// Returns:
+//	- resp: returns the targets and the shard owning each of them.
+//	- err: returns nil, listing the targets cannot fail.
+func (p *Probe) ListMonitoredStorageSystems(ctx context.Context, req *probepb.ListMonitoredSystemsRequest) (*probepb.ListMonitoredSystemsResponse, error) {
+	resp := &probepb.ListMonitoredSystemsResponse{MonitoredTargets: p.monitoredTargets()}
+	for _, t := range p.targets {
+		resp.Targets = append(resp.Targets, t.Target)
+	}
+	return resp, nil
+}
+
+// UpdateShardMembers adds the instances that joined the fleet to the shard ring and removes the
+// instances that left it. The targets this instance no longer owns are released on the next probe run.
+// The update only changes the ring of this instance, and is not propagated to the other members: it
+// must be sent to every member of the ring, which then agree on the owner of every target. Until
+// then, a target may be owned by two members, whose runs do not overlap if leasing is configured.
+// This instance cannot be removed from its own ring, and the ring cannot be left without members.
+// Arguments:
+//	- ctx: the context of the RPC.
+//	- req: the instances that joined and left the fleet.
+// Returns:
+//	- resp: returns the members of the ring and the shard owning each target after the update.
+//	- err: returns an error if sharding is not configured, the update removes this instance or leaves the ring empty.
+func (p *Probe) UpdateShardMembers(ctx context.Context, req *probepb.UpdateShardMembersRequest) (*probepb.UpdateShardMembersResponse, error) {
+	p.shardMu.Lock()
+	if p.ring == nil {
+		p.shardMu.Unlock()
+		return nil, status.Errorf(codes.FailedPrecondition, "probe %q is not configured with a shard config", p.name)
+	}
+	ring := p.ring.Update(req.GetJoined(), req.GetLeft())
+	if len(ring.Members()) == 0 {
+		p.shardMu.Unlock()
+		return nil, status.Errorf(codes.InvalidArgument, "the update leaves the ring without members")
+	}
+	for _, m := range req.GetLeft() {
+		if m == p.instance {
+			p.shardMu.Unlock()
+			return nil, status.Errorf(codes.InvalidArgument, "instance %q cannot remove itself from the ring, send the update to the remaining members", m)
+		}
+	}
+	p.ring = ring
+	members := p.ring.Members()
+	p.shardMu.Unlock()
+
+	p.logger.Infof("Shard members updated, joined: %v, left: %v, members: %v.", req.GetJoined(), req.GetLeft(), members)
+	return &probepb.UpdateShardMembersResponse{Members: members, MonitoredTargets: p.monitoredTargets()}, nil
+}
+
+// findTargets returns the targets of the probe with the names and bucket names of the targets given.
+// Arguments:
+//	- targets: the targets to find, identified by their name and bucket name.
+// Returns:
+//	- found: returns the targets of the probe, in the order given.
+//	- err: returns an error if no targets are given or a target is not monitored by the probe.
+func (p *Probe) findTargets(targets []*probepb.Target) ([]*target.Target, error) {
+	if len(targets) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "no targets given")
+	}
+	var found []*target.Target
+	for _, want := range targets {
+		var match *target.Target
+		for _, t := range p.targets {
+			if t.Target.GetName() == want.GetName() && t.Target.GetBucketName() == want.GetBucketName() {
+				match = t
+				break
+			}
+		}
+		if match == nil {
+			return nil, status.Errorf(codes.NotFound, "target %q with bucket %q is not monitored by probe %q", want.GetName(), want.GetBucketName(), p.name)
+		}
+		found = append(found, match)
+	}
+	return found, nil
+}
+
+// maintenanceState returns whether a target is paused or in one of its maintenance windows at the time given.
+// A pause that expired by that time is removed.
+func (p *Probe) maintenanceState(target *target.Target, now time.Time) *probepb.TargetMaintenanceState {
+	state := &probepb.TargetMaintenanceState{
+		Target:              target.Target,
+		InMaintenanceWindow: maintenance.Active(p.windows[target], now),
+	}
+	p.pauseMu.Lock()
+	defer p.pauseMu.Unlock()
+	pause, ok := p.pauses[target]
+	if !ok {
+		return state
+	}
+	if !pause.until.IsZero() && !now.Before(pause.until) {
+		delete(p.pauses, target)
+		return state
+	}
+	state.Paused = true
+	state.Reason = pause.reason
+	if !pause.until.IsZero() {
+		state.ResumeUnixSec = pause.until.Unix()
+	}
+	return state
+}
+
+// PauseTarget pauses the targets given until ResumeTarget is called or the duration of the pause expires.
+// While a target is paused, it is not probed or its results are marked, as configured by its maintenance mode.
+// Pausing a paused target replaces its reason and duration.
+// Arguments:
+//	- ctx: the context of the RPC.
+//	- req: the targets to pause, why and for how long.
+// Returns:
+//	- resp: returns the maintenance state of the targets.
+//	- err: returns an error if the duration is negative or a target is not monitored by the probe.
+func (p *Probe) PauseTarget(ctx context.Context, req *probepb.PauseTargetRequest) (*probepb.PauseTargetResponse, error) {
+	if req.GetDurationSec() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "duration_sec = %d; want a non-negative duration", req.GetDurationSec())
+	}
+	targets, err := p.findTargets(req.GetTargets())
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var until time.Time
+	if req.GetDurationSec() > 0 {
+		until = now.Add(time.Duration(req.GetDurationSec()) * time.Second)
+	}
+	p.pauseMu.Lock()
+	for _, t := range targets {
+		p.pauses[t] = &pause{reason: req.GetReason(), until: until}
+	}
+	p.pauseMu.Unlock()
+
+	resp := &probepb.PauseTargetResponse{}
+	for _, t := range targets {
+		state := p.maintenanceState(t, now)
+		p.logger.Infof("Target %q paused, resume_unix_sec: %d, reason: %q.", t.Target.GetName(), state.GetResumeUnixSec(), req.GetReason())
+		resp.Targets = append(resp.Targets, state)
+	}
+	return resp, nil
+}
+
+// ResumeTarget resumes the paused targets given. Targets that are not paused are left unchanged
+// and targets in one of their maintenance windows stay in maintenance until the window ends.
+// Arguments:
+//	- ctx: the context of the RPC.
+//	- req: the targets to resume.
+// Returns:
+//	- resp: returns the maintenance state of the targets.
+//	- err: returns an error if a target is not monitored by the probe.
+func (p *Probe) ResumeTarget(ctx context.Context, req *probepb.ResumeTargetRequest) (*probepb.ResumeTargetResponse, error) {
+	targets, err := p.findTargets(req.GetTargets())
+	if err != nil {
+		return nil, err
+	}
+	p.pauseMu.Lock()
+	for _, t := range targets {
+		delete(p.pauses, t)
+	}
+	p.pauseMu.Unlock()
+
+	now := time.Now()
+	resp := &probepb.ResumeTargetResponse{}
+	for _, t := range targets {
+		p.logger.Infof("Target %q resumed.", t.Target.GetName())
+		resp.Targets = append(resp.Targets, p.maintenanceState(t, now))
+	}
+	return resp, nil
+}
+
+// RunProbe runs the probe once against a target right away and streams the result of every operation
+// of the run, then the result of the run. It waits for a scheduled run of the target in flight to
+// complete, and scheduled runs of the target are skipped while it runs, so the two never overlap.
+// The run is recorded in the metrics of the target, which are surfaced on its next scheduled run.
+// Arguments:
+//	- req: the target to probe.
+//	- stream: the stream the results are sent on.
+// Returns:
+//	- err: returns an error if the target is not probed by this instance, the context of the stream
+//	is done before the run starts or a result could not be sent. Failures of the run are streamed.
+func (p *Probe) RunProbe(req *probepb.RunProbeRequest, stream probepb.Hermes_RunProbeServer) error {
+	if req.GetTarget() == nil {
+		return status.Errorf(codes.InvalidArgument, "no target given")
+	}
+	targets, err := p.findTargets([]*probepb.Target{req.GetTarget()})
+	if err != nil {
+		return err
+	}
+	target := targets[0]
+	if owner, ok := p.owner(target); !ok {
+		return status.Errorf(codes.FailedPrecondition, "target %q is probed by shard %q", target.Target.GetName(), owner)
+	}
+
+	ctx := stream.Context()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case p.tokens[target] <- struct{}{}:
+	}
+	defer func() { <-p.tokens[target] }()
+	release, err := p.acquireSlot(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	state := p.maintenanceState(target, time.Now())
+	var sendErr error
+	report := func(result *probepb.RunProbeResult) {
+		if sendErr == nil {
+			sendErr = stream.Send(result)
+		}
+	}
+	p.logger.Infof("Target %q: running the probe on demand.", target.Target.GetName())
+	if _, err := p.probeTarget(ctx, target, state.GetPaused() || state.GetInMaintenanceWindow(), report); err != nil {
+		p.logger.Warningf("Target %q: on-demand run failed: %v", target.Target.GetName(), err)
+	}
+	return sendErr
+}
+
+// GetTargetSLO returns the service level indicators of the targets given over every SLO window:
+// the ratio of successful runs, the ratio of runs that met the latency SLO and the error budgets left.
+// The indicators cover the scheduled runs of this instance, and the runs of the previous owners of the
+// target recorded in the run history.
+// Arguments:
+//	- ctx: the context of the RPC.
+//	- req: the targets to return the indicators of, every target owned by this instance if empty.
+// Returns:
+//	- resp: returns the indicators of the targets.
+//	- err: returns an error if no SLO is configured or a target is not monitored by the probe.
+func (p *Probe) GetTargetSLO(ctx context.Context, req *probepb.GetTargetSLORequest) (*probepb.GetTargetSLOResponse, error) {
+	if p.config.GetSlo() == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "probe %q is not configured with an SLO config", p.name)
+	}
+	var targets []*target.Target
+	if len(req.GetTargets()) == 0 {
+		for _, t := range p.targets {
+			if _, ok := p.owner(t); ok {
+				targets = append(targets, t)
+			}
+		}
+	} else {
+		found, err := p.findTargets(req.GetTargets())
+		if err != nil {
+			return nil, err
+		}
+		targets = found
+	}
+	now := time.Now()
+	resp := &probepb.GetTargetSLOResponse{}
+	for _, t := range targets {
+		resp.Targets = append(resp.Targets, &probepb.TargetSLO{Target: t.Target, Indicators: p.slos[t].Indicators(now)})
+	}
+	return resp, nil
+}
+
+// GetRunHistory returns the runs of the targets given that started within the time range of the request,
+// from the run history of this instance, ordered by start time.
+// Arguments:
+//	- ctx: the context of the RPC.
+//	- req: the targets, every target of the probe if empty, the time range and the maximum number of runs.
+// Returns:
+//	- resp: returns the runs, the latest ones if there are more than the limit.
+//	- err: returns an error if the run history is not configured, the request is invalid,
+//	  a target is not monitored by the probe or the history could not be read.
+func (p *Probe) GetRunHistory(ctx context.Context, req *probepb.GetRunHistoryRequest) (*probepb.GetRunHistoryResponse, error) {
+	if p.history == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "probe %q is not configured with a history config", p.name)
+	}
+	start := time.Unix(req.GetStartUnixSec(), 0)
+	end := time.Now()
+	if req.GetEndUnixSec() != 0 {
+		end = time.Unix(req.GetEndUnixSec(), 0)
+	}
+	if end.Before(start) {
+		return nil, status.Errorf(codes.InvalidArgument, "end_unix_sec = %d is before start_unix_sec = %d", req.GetEndUnixSec(), req.GetStartUnixSec())
+	}
+	if req.GetLimit() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "limit = %d; want a non-negative value", req.GetLimit())
+	}
+	targets := p.targets
+	if len(req.GetTargets()) > 0 {
+		found, err := p.findTargets(req.GetTargets())
+		if err != nil {
+			return nil, err
+		}
+		targets = found
+	}
+	var runs []*probepb.RunRecord
+	for _, t := range targets {
+		targetRuns, err := p.history.Query(t.Target, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("target %q: %w", t.Target.GetName(), err)
+		}
+		runs = append(runs, targetRuns...)
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].GetStartUnixMs() < runs[j].GetStartUnixMs() })
+	if limit := int(req.GetLimit()); limit > 0 && len(runs) > limit {
+		runs = runs[len(runs)-limit:]
+	}
+	return &probepb.GetRunHistoryResponse{Runs: runs}, nil
+}
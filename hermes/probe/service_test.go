@@ -0,0 +1,583 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probe
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/googleinterns/step224-2020/hermes/probe/fakegcs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	cpmetrics "github.com/google/cloudprober/metrics"
+	monitorpb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+// genShardedProbe initialises a probe with the number of targets given, sharded across the members given.
+func genShardedProbe(t *testing.T, numTargets int, instance string, members ...string) (*Probe, error) {
+	t.Helper()
+	name := "testShardedProbe"
+	_, cfg := GenTestConfig(name)
+	for i := 1; i < numTargets; i++ {
+		target := proto.Clone(cfg.GetTargets()[0]).(*monitorpb.Target)
+		target.BucketName = fmt.Sprintf("test_bucket_shard_%d", i)
+		cfg.Targets = append(cfg.Targets, target)
+	}
+	cfg.Shard = &monitorpb.ShardConfig{Instance: proto.String(instance), Members: members}
+	mp := &Probe{}
+	return mp, mp.Init(name, GenOptsFromConfig(t, cfg))
+}
+
+func TestInitShard(t *testing.T) {
+	if _, err := genShardedProbe(t, 1, "a", "b", "c"); err == nil {
+		t.Errorf("Init() with an instance that is not a shard member succeeded, want an error")
+	}
+	mp, err := genShardedProbe(t, 1, "a")
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if owner, local := mp.owner(mp.targets[0]); owner != "a" || !local {
+		t.Errorf("owner() = %q, %t on a single member ring, want %q, true", owner, local, "a")
+	}
+}
+
+func TestListMonitoredStorageSystems(t *testing.T) {
+	ctx := context.Background()
+	mp, err := genShardedProbe(t, 20, "a", "a", "b")
+	if err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	resp, err := mp.ListMonitoredStorageSystems(ctx, &monitorpb.ListMonitoredSystemsRequest{})
+	if err != nil {
+		t.Fatalf("ListMonitoredStorageSystems() failed: %v", err)
+	}
+	if got, want := len(resp.GetTargets()), 20; got != want {
+		t.Errorf("ListMonitoredStorageSystems() returned %d targets, want %d", got, want)
+	}
+	shards := make(map[string]int)
+	for _, m := range resp.GetMonitoredTargets() {
+		shards[m.GetShard()]++
+		if m.GetLocal() != (m.GetShard() == "a") {
+			t.Errorf("ListMonitoredStorageSystems() reported target %q owned by %q with local = %t", m.GetTarget().GetBucketName(), m.GetShard(), m.GetLocal())
+		}
+	}
+	if shards["a"] == 0 || shards["b"] == 0 || shards["a"]+shards["b"] != 20 {
+		t.Errorf("ListMonitoredStorageSystems() reported targets owned by shards %v, want the 20 targets shared by shards a and b", shards)
+	}
+
+	// An instance cannot remove itself, and the ring cannot be emptied.
+	for _, left := range [][]string{{"a"}, {"a", "b"}} {
+		if _, err := mp.UpdateShardMembers(ctx, &monitorpb.UpdateShardMembersRequest{Left: left}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("UpdateShardMembers(left: %v) = %v, want code %v", left, err, codes.InvalidArgument)
+		}
+	}
+	if got, want := fmt.Sprint(mp.ring.Members()), "[a b]"; got != want {
+		t.Errorf("rejected UpdateShardMembers() changed the members to %s, want %s", got, want)
+	}
+
+	// Every target moves to a once b leaves the fleet.
+	update, err := mp.UpdateShardMembers(ctx, &monitorpb.UpdateShardMembersRequest{Left: []string{"b"}})
+	if err != nil {
+		t.Fatalf("UpdateShardMembers() failed: %v", err)
+	}
+	if got, want := fmt.Sprint(update.GetMembers()), "[a]"; got != want {
+		t.Errorf("UpdateShardMembers() members = %s, want %s", got, want)
+	}
+	for _, m := range update.GetMonitoredTargets() {
+		if m.GetShard() != "a" || !m.GetLocal() {
+			t.Errorf("UpdateShardMembers() reported target %q owned by %q, want it owned by a", m.GetTarget().GetBucketName(), m.GetShard())
+		}
+	}
+
+	unsharded := &Probe{}
+	_, cfg := GenTestConfig("testUnshardedProbe")
+	if err := unsharded.Init("testUnshardedProbe", GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if _, err := unsharded.UpdateShardMembers(ctx, &monitorpb.UpdateShardMembersRequest{Joined: []string{"b"}}); err == nil {
+		t.Errorf("UpdateShardMembers() without a shard config succeeded, want an error")
+	}
+}
+
+func TestPauseResumeTarget(t *testing.T) {
+	ctx := context.Background()
+	name := "testProbePause"
+	mp := &Probe{}
+	_, cfg := GenTestConfig(name)
+	if err := mp.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	target := mp.targets[0]
+	ref := []*monitorpb.Target{{Name: target.Target.GetName(), BucketName: target.Target.GetBucketName()}}
+
+	if _, err := mp.PauseTarget(ctx, &monitorpb.PauseTargetRequest{}); err == nil {
+		t.Errorf("PauseTarget() without targets succeeded, want an error")
+	}
+	if _, err := mp.PauseTarget(ctx, &monitorpb.PauseTargetRequest{Targets: []*monitorpb.Target{{Name: "unknown"}}}); err == nil {
+		t.Errorf("PauseTarget() of an unknown target succeeded, want an error")
+	}
+
+	resp, err := mp.PauseTarget(ctx, &monitorpb.PauseTargetRequest{Targets: ref, Reason: "storage upgrade"})
+	if err != nil {
+		t.Fatalf("PauseTarget() failed: %v", err)
+	}
+	if state := resp.GetTargets()[0]; !state.GetPaused() || state.GetReason() != "storage upgrade" || state.GetResumeUnixSec() != 0 {
+		t.Errorf("PauseTarget() = %v, want the target paused until resumed", state)
+	}
+
+	// The paused target is not probed, it has no client to probe it with, and the paused gauge is set.
+	metricChan := make(chan *cpmetrics.EventMetrics, 1000)
+	mp.runTarget(ctx, target, metricChan)
+	if got := target.LatencyMetrics.Paused.Metric("hermes_target_paused").(*cpmetrics.Int).Int64(); got != 1 {
+		t.Errorf("runTarget() of a paused target set the paused gauge to %d, want 1", got)
+	}
+	if mp.client != nil || len(metricChan) == 0 {
+		t.Errorf("runTarget() of a paused target probed the target or did not report its metrics")
+	}
+
+	resume, err := mp.ResumeTarget(ctx, &monitorpb.ResumeTargetRequest{Targets: ref})
+	if err != nil {
+		t.Fatalf("ResumeTarget() failed: %v", err)
+	}
+	if state := resume.GetTargets()[0]; state.GetPaused() || state.GetInMaintenanceWindow() {
+		t.Errorf("ResumeTarget() = %v, want the target resumed", state)
+	}
+
+	// A pause with a duration expires on its own.
+	resp, err = mp.PauseTarget(ctx, &monitorpb.PauseTargetRequest{Targets: ref, DurationSec: 60})
+	if err != nil {
+		t.Fatalf("PauseTarget() failed: %v", err)
+	}
+	if state := resp.GetTargets()[0]; !state.GetPaused() || state.GetResumeUnixSec() == 0 {
+		t.Errorf("PauseTarget() = %v, want the target paused for 60 seconds", state)
+	}
+	if state := mp.maintenanceState(target, time.Now().Add(time.Minute)); state.GetPaused() {
+		t.Errorf("maintenanceState() after the pause expired = %v, want the target resumed", state)
+	}
+}
+
+func TestMaintenanceWindows(t *testing.T) {
+	name := "testProbeMaintenance"
+	now := time.Now()
+	_, cfg := GenTestConfig(name)
+	cfg.MaintenanceWindows = []*monitorpb.MaintenanceWindow{{StartUnixSec: now.Add(-time.Hour).Unix(), EndUnixSec: now.Add(time.Hour).Unix()}}
+	mp := &Probe{}
+	if err := mp.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if state := mp.maintenanceState(mp.targets[0], now); !state.GetInMaintenanceWindow() || state.GetPaused() {
+		t.Errorf("maintenanceState() within a maintenance window of the probe = %v, want the target in a maintenance window", state)
+	}
+	if state := mp.maintenanceState(mp.targets[0], now.Add(2*time.Hour)); state.GetInMaintenanceWindow() {
+		t.Errorf("maintenanceState() after the maintenance window = %v, want the target out of maintenance", state)
+	}
+
+	_, cfg = GenTestConfig(name)
+	cfg.GetTargets()[0].MaintenanceWindows = []*monitorpb.MaintenanceWindow{{Schedule: "0 2 * *"}}
+	if err := (&Probe{}).Init(name, GenOptsFromConfig(t, cfg)); err == nil {
+		t.Errorf("Init() with an invalid maintenance window succeeded, want an error")
+	}
+}
+
+// fakeRunProbeStream collects the results sent on the stream of a RunProbe RPC.
+type fakeRunProbeStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	results []*monitorpb.RunProbeResult
+}
+
+func (s *fakeRunProbeStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeRunProbeStream) Send(result *monitorpb.RunProbeResult) error {
+	s.results = append(s.results, result)
+	return nil
+}
+
+func TestRunProbe(t *testing.T) {
+	ctx := context.Background()
+	name := "testProbeRunOnce"
+	mp := &Probe{}
+	_, cfg := GenTestConfig(name)
+	cfg.GetTargets()[0].BootstrapFilesPerSec = 1000
+	if err := mp.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	mp.client = fakegcs.NewClient()
+	target := mp.targets[0]
+	bucket := target.Target.GetBucketName()
+	if err := mp.client.Bucket(bucket).Create(ctx, bucket, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	req := &monitorpb.RunProbeRequest{Target: &monitorpb.Target{Name: target.Target.GetName(), BucketName: bucket}}
+
+	if err := mp.RunProbe(&monitorpb.RunProbeRequest{}, &fakeRunProbeStream{ctx: ctx}); err == nil {
+		t.Errorf("RunProbe() without a target succeeded, want an error")
+	}
+
+	stream := &fakeRunProbeStream{ctx: ctx}
+	if err := mp.RunProbe(req, stream); err != nil {
+		t.Fatalf("RunProbe() failed: %v", err)
+	}
+	var ops []string
+	for _, r := range stream.results {
+		ops = append(ops, r.GetOperation())
+		if r.GetStatus() != "success" || r.GetError() != "" {
+			t.Errorf("RunProbe() streamed %v, want a successful operation", r)
+		}
+	}
+	if got, want := fmt.Sprint(ops), "[bootstrap check_nil verify_durability delete_file create_file read_file total_probe_run]"; got != want {
+		t.Errorf("RunProbe() streamed operations %s, want %s", got, want)
+	}
+	if last := stream.results[len(stream.results)-1]; !last.GetFinal() {
+		t.Errorf("RunProbe() streamed %v last, want the final result of the run", last)
+	}
+	// The file deleted by the run is created again and tracked in the journal.
+	deleted := target.Journal.GetLastDeletedFileId()
+	if _, ok := target.Journal.GetFiles()[deleted]; deleted == 0 || !ok {
+		t.Errorf("RunProbe() left last deleted file %d untracked in the journal, want it created again", deleted)
+	}
+
+	// A scheduled run is in flight, so the on-demand run waits for it rather than overlapping it.
+	mp.tokens[target] <- struct{}{}
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	stream = &fakeRunProbeStream{ctx: waitCtx}
+	if err := mp.RunProbe(req, stream); err != context.DeadlineExceeded || len(stream.results) != 0 {
+		t.Errorf("RunProbe() during a scheduled run = %v with %d results, want %v before running", err, len(stream.results), context.DeadlineExceeded)
+	}
+	<-mp.tokens[target]
+	if got, want := len(mp.slots), 0; got != want {
+		t.Errorf("RunProbe() left %d slots held, want %d", got, want)
+	}
+}
+
+func TestRunProbeResumable(t *testing.T) {
+	ctx := context.Background()
+	name := "testProbeRunResumable"
+	mp := &Probe{}
+	_, cfg := GenTestConfig(name)
+	cfg.GetTargets()[0].BootstrapFilesPerSec = 1000
+	cfg.GetTargets()[0].UploadMode = monitorpb.Target_RESUMABLE
+	cfg.GetTargets()[0].UploadChunkSizeBytes = 100
+	if err := mp.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	mp.client = fakegcs.NewClient()
+	target := mp.targets[0]
+	bucket := target.Target.GetBucketName()
+	if err := mp.client.Bucket(bucket).Create(ctx, bucket, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	req := &monitorpb.RunProbeRequest{Target: &monitorpb.Target{Name: target.Target.GetName(), BucketName: bucket}}
+	stream := &fakeRunProbeStream{ctx: ctx}
+	if err := mp.RunProbe(req, stream); err != nil {
+		t.Fatalf("RunProbe() failed: %v", err)
+	}
+	var ops []string
+	for _, r := range stream.results {
+		ops = append(ops, r.GetOperation())
+	}
+	// The abort of an incomplete upload is reported separately from the creation of the file.
+	if got, want := fmt.Sprint(ops), "[bootstrap check_nil verify_durability delete_file create_file abort_upload read_file total_probe_run]"; got != want {
+		t.Errorf("RunProbe() streamed operations %s, want %s", got, want)
+	}
+}
+
+func TestHermesServer(t *testing.T) {
+	ctx := context.Background()
+	name := "testProbeServer"
+	mp := &Probe{}
+	_, cfg := GenTestConfig(name)
+	cfg.GetTargets()[0].BootstrapFilesPerSec = 1000
+	if err := mp.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	mp.client = fakegcs.NewClient()
+	target := mp.targets[0]
+	bucket := target.Target.GetBucketName()
+	if err := mp.client.Bucket(bucket).Create(ctx, bucket, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	server := grpc.NewServer()
+	monitorpb.RegisterHermesServer(server, mp)
+	go server.Serve(lis)
+	defer server.Stop()
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("grpc.Dial() failed: %v", err)
+	}
+	defer conn.Close()
+	client := monitorpb.NewHermesClient(conn)
+
+	list, err := client.ListMonitoredStorageSystems(ctx, &monitorpb.ListMonitoredSystemsRequest{})
+	if err != nil {
+		t.Fatalf("ListMonitoredStorageSystems() failed: %v", err)
+	}
+	if got := len(list.GetTargets()); got != 1 || !proto.Equal(list.GetTargets()[0], target.Target) {
+		t.Errorf("ListMonitoredStorageSystems() = %v, want target %v", list.GetTargets(), target.Target)
+	}
+	if _, err := client.UpdateShardMembers(ctx, &monitorpb.UpdateShardMembersRequest{Joined: []string{"b"}}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("UpdateShardMembers() without a shard config = %v, want code %v", err, codes.FailedPrecondition)
+	}
+	if _, err := client.StartMonitoringStorageSystem(ctx, &monitorpb.HermesProbeRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("StartMonitoringStorageSystem() = %v, want code %v", err, codes.Unimplemented)
+	}
+
+	stream, err := client.RunProbe(ctx, &monitorpb.RunProbeRequest{Target: &monitorpb.Target{Name: target.Target.GetName(), BucketName: bucket}})
+	if err != nil {
+		t.Fatalf("RunProbe() failed: %v", err)
+	}
+	var results []*monitorpb.RunProbeResult
+	for {
+		result, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("RunProbe() stream failed: %v", err)
+		}
+		results = append(results, result)
+	}
+	if len(results) == 0 || !results[len(results)-1].GetFinal() || results[len(results)-1].GetStatus() != "success" {
+		t.Errorf("RunProbe() streamed %v, want a successful run ending with its final result", results)
+	}
+}
+
+func TestGetTargetSLO(t *testing.T) {
+	ctx := context.Background()
+	name := "testProbeSLO"
+	mp := &Probe{}
+	_, cfg := GenTestConfig(name)
+	if err := mp.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if _, err := mp.GetTargetSLO(ctx, &monitorpb.GetTargetSLORequest{}); err == nil {
+		t.Errorf("GetTargetSLO() without an SLO config succeeded, want an error")
+	}
+
+	_, cfg = GenTestConfig(name)
+	cfg.Slo = &monitorpb.SLOConfig{AvailabilityTarget: proto.Float64(1.5)}
+	if err := (&Probe{}).Init(name, GenOptsFromConfig(t, cfg)); err == nil {
+		t.Errorf("Init() with an invalid SLO config succeeded, want an error")
+	}
+
+	_, cfg = GenTestConfig(name)
+	cfg.GetTargets()[0].BootstrapFilesPerSec = 1000
+	cfg.Slo = &monitorpb.SLOConfig{AvailabilityTarget: proto.Float64(0.99), WindowSec: []int64{3600}}
+	mp = &Probe{}
+	if err := mp.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	mp.client = fakegcs.NewClient()
+	target := mp.targets[0]
+	bucket := target.Target.GetBucketName()
+	if err := mp.client.Bucket(bucket).Create(ctx, bucket, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	if _, err := mp.probeTarget(ctx, target, false, nil); err != nil {
+		t.Fatalf("probeTarget() failed: %v", err)
+	}
+	// Runs during maintenance do not count against the SLO.
+	if _, err := mp.probeTarget(ctx, target, true, nil); err != nil {
+		t.Fatalf("probeTarget() during maintenance failed: %v", err)
+	}
+
+	resp, err := mp.GetTargetSLO(ctx, &monitorpb.GetTargetSLORequest{})
+	if err != nil {
+		t.Fatalf("GetTargetSLO() failed: %v", err)
+	}
+	if got, want := len(resp.GetTargets()), 1; got != want {
+		t.Fatalf("GetTargetSLO() returned %d targets, want %d", got, want)
+	}
+	slis := resp.GetTargets()[0].GetIndicators()
+	if len(slis) != 1 || slis[0].GetWindowSec() != 3600 || slis[0].GetRuns() != 1 || slis[0].GetSuccessRatio() != 1 || slis[0].GetErrorBudgetRemaining() != 1 {
+		t.Errorf("GetTargetSLO() = %v, want one successful run in the 1h window", slis)
+	}
+
+	mp.updateSLOGauges(target, time.Now())
+	if got := len(target.LatencyMetrics.SLO); got != 1 {
+		t.Fatalf("updateSLOGauges() created %d gauges, want 1", got)
+	}
+	if got := target.LatencyMetrics.SLO[0].Metric("hermes_slo_success_ratio").(*cpmetrics.Float).Float64(); got != 1 {
+		t.Errorf("hermes_slo_success_ratio = %v, want 1", got)
+	}
+
+	if _, err := mp.GetTargetSLO(ctx, &monitorpb.GetTargetSLORequest{Targets: []*monitorpb.Target{{Name: "unknown"}}}); err == nil {
+		t.Errorf("GetTargetSLO() of an unknown target succeeded, want an error")
+	}
+}
+
+func TestGetTargetSLOFromHistory(t *testing.T) {
+	ctx := context.Background()
+	name := "testProbeSLOHistory"
+	dir, err := ioutil.TempDir("", "service_test")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir() failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	_, cfg := GenTestConfig(name)
+	cfg.GetTargets()[0].BootstrapFilesPerSec = 1000
+	cfg.Slo = &monitorpb.SLOConfig{AvailabilityTarget: proto.Float64(0.99), WindowSec: []int64{3600}}
+	cfg.History = &monitorpb.HistoryConfig{Dir: proto.String(dir)}
+	mp := &Probe{}
+	if err := mp.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	client := fakegcs.NewClient()
+	mp.client = client
+	target := mp.targets[0]
+	bucket := target.Target.GetBucketName()
+	if err := client.Bucket(bucket).Create(ctx, bucket, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	if _, err := mp.probeTarget(ctx, target, false, nil); err != nil {
+		t.Fatalf("probeTarget() failed: %v", err)
+	}
+	// On-demand runs are recorded in the history, but do not count towards the SLO.
+	if _, err := mp.probeTarget(ctx, target, false, func(*monitorpb.RunProbeResult) {}); err != nil {
+		t.Fatalf("probeTarget() on demand failed: %v", err)
+	}
+	runs := func(p *Probe) int64 {
+		t.Helper()
+		resp, err := p.GetTargetSLO(ctx, &monitorpb.GetTargetSLORequest{})
+		if err != nil {
+			t.Fatalf("GetTargetSLO() failed: %v", err)
+		}
+		return resp.GetTargets()[0].GetIndicators()[0].GetRuns()
+	}
+	if got, want := runs(mp), int64(1); got != want {
+		t.Errorf("GetTargetSLO() counted %d runs, want %d", got, want)
+	}
+	resp, err := mp.GetRunHistory(ctx, &monitorpb.GetRunHistoryRequest{})
+	if err != nil {
+		t.Fatalf("GetRunHistory() failed: %v", err)
+	}
+	if got := resp.GetRuns(); len(got) != 2 || got[0].GetOnDemand() || !got[1].GetOnDemand() {
+		t.Errorf("GetRunHistory() = %v, want a scheduled run followed by an on-demand run", got)
+	}
+
+	// An instance taking over the target, e.g. after a restart, rebuilds the SLO windows from the history.
+	next := &Probe{}
+	if err := next.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	next.client = client
+	if _, err := next.probeTarget(ctx, next.targets[0], false, nil); err != nil {
+		t.Fatalf("probeTarget() after the takeover failed: %v", err)
+	}
+	if got, want := runs(next), int64(2); got != want {
+		t.Errorf("GetTargetSLO() after the takeover counted %d runs, want %d", got, want)
+	}
+}
+
+func TestGetRunHistory(t *testing.T) {
+	ctx := context.Background()
+	name := "testProbeHistory"
+	mp := &Probe{}
+	_, cfg := GenTestConfig(name)
+	if err := mp.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if _, err := mp.GetRunHistory(ctx, &monitorpb.GetRunHistoryRequest{}); err == nil {
+		t.Errorf("GetRunHistory() without a history config succeeded, want an error")
+	}
+
+	dir, err := ioutil.TempDir("", "service_test")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir() failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	_, cfg = GenTestConfig(name)
+	cfg.GetTargets()[0].BootstrapFilesPerSec = 1000
+	cfg.History = &monitorpb.HistoryConfig{Dir: proto.String(dir)}
+	mp = &Probe{}
+	if err := mp.Init(name, GenOptsFromConfig(t, cfg)); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	mp.client = fakegcs.NewClient()
+	target := mp.targets[0]
+	bucket := target.Target.GetBucketName()
+	if err := mp.client.Bucket(bucket).Create(ctx, bucket, nil); err != nil {
+		t.Fatalf("failed to create fake bucket: %v", err)
+	}
+	start := time.Now().Add(-time.Second)
+	if _, err := mp.probeTarget(ctx, target, false, nil); err != nil {
+		t.Fatalf("probeTarget() failed: %v", err)
+	}
+	if _, err := mp.probeTarget(ctx, target, true, nil); err != nil {
+		t.Fatalf("probeTarget() during maintenance failed: %v", err)
+	}
+
+	resp, err := mp.GetRunHistory(ctx, &monitorpb.GetRunHistoryRequest{StartUnixSec: start.Unix()})
+	if err != nil {
+		t.Fatalf("GetRunHistory() failed: %v", err)
+	}
+	var statuses []string
+	for _, run := range resp.GetRuns() {
+		statuses = append(statuses, run.GetStatus())
+		if run.GetTarget().GetBucketName() != bucket || run.GetEndUnixMs() < run.GetStartUnixMs() {
+			t.Errorf("GetRunHistory() returned run %v, want a run of target %q", run, bucket)
+		}
+	}
+	// The first run bootstrapped the target, creating its files.
+	if got, want := len(resp.GetRuns()[0].GetFiles()), 51; got < want {
+		t.Errorf("GetRunHistory() returned a bootstrap run that accessed %d files, want at least %d", got, want)
+	}
+	if got, want := fmt.Sprint(statuses), "[success maintenance]"; got != want {
+		t.Fatalf("GetRunHistory() returned runs with statuses %s, want %s", got, want)
+	}
+	var ops []string
+	for _, op := range resp.GetRuns()[0].GetOperations() {
+		ops = append(ops, op.GetOperation())
+	}
+	if got, want := fmt.Sprint(ops), "[bootstrap check_nil verify_durability delete_file create_file read_file]"; got != want {
+		t.Errorf("GetRunHistory() returned a run with operations %s, want %s", got, want)
+	}
+
+	resp, err = mp.GetRunHistory(ctx, &monitorpb.GetRunHistoryRequest{
+		Targets:      []*monitorpb.Target{{Name: target.Target.GetName(), BucketName: bucket}},
+		StartUnixSec: start.Unix(),
+		Limit:        1,
+	})
+	if err != nil {
+		t.Fatalf("GetRunHistory() failed: %v", err)
+	}
+	if len(resp.GetRuns()) != 1 || resp.GetRuns()[0].GetStatus() != "maintenance" {
+		t.Errorf("GetRunHistory() with a limit of 1 = %v, want the latest run", resp.GetRuns())
+	}
+	if resp, err := mp.GetRunHistory(ctx, &monitorpb.GetRunHistoryRequest{EndUnixSec: start.Unix()}); err != nil || len(resp.GetRuns()) != 0 {
+		t.Errorf("GetRunHistory() before the runs = %v, %v, want no runs", resp.GetRuns(), err)
+	}
+	if _, err := mp.GetRunHistory(ctx, &monitorpb.GetRunHistoryRequest{StartUnixSec: start.Unix(), EndUnixSec: start.Unix() - 1}); err == nil {
+		t.Errorf("GetRunHistory() with an end before its start succeeded, want an error")
+	}
+}
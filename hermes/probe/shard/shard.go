@@ -0,0 +1,125 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shard implements the consistent-hash ring used to shard the targets
+// across a fleet of Hermes instances. When an instance joins or leaves the
+// ring, only the targets owned by that instance move.
+package shard
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+// DefaultVirtualNodes is the default number of points each member has on the ring.
+// More points spread the targets more evenly across the members.
+const DefaultVirtualNodes = 100
+
+// Ring is a consistent-hash ring of the members the targets are sharded across.
+// A Ring is immutable, membership changes return a new Ring.
+type Ring struct {
+	members      []string
+	virtualNodes int
+	// points holds the hashes of the virtual nodes of every member in ascending order.
+	points []uint32
+	// owners maps every point to the member it belongs to.
+	owners map[uint32]string
+}
+
+// hash returns the position of a key on the ring, the first 4 bytes of its SHA-256 hash.
+// Non-cryptographic hashes such as FNV spread similar keys, e.g. bucket_1 and bucket_2, unevenly.
+func hash(key string) uint32 {
+	sum := sha256.Sum256([]byte(key))
+	return binary.BigEndian.Uint32(sum[:4])
+}
+
+// New returns a ring of the members given.
+// Arguments:
+//	- members: the names of the members, duplicates are ignored.
+//	- virtualNodes: the number of points of each member on the ring, DefaultVirtualNodes if not positive.
+// Returns:
+//	- ring: returns the ring of the members.
+func New(members []string, virtualNodes int) *Ring {
+	if virtualNodes <= 0 {
+		virtualNodes = DefaultVirtualNodes
+	}
+	r := &Ring{virtualNodes: virtualNodes, owners: make(map[uint32]string)}
+	seen := make(map[string]bool, len(members))
+	for _, m := range members {
+		if m == "" || seen[m] {
+			continue
+		}
+		seen[m] = true
+		r.members = append(r.members, m)
+	}
+	sort.Strings(r.members)
+	for _, m := range r.members {
+		for i := 0; i < virtualNodes; i++ {
+			p := hash(fmt.Sprintf("%s#%d", m, i))
+			// On the unlikely collision of two points, the smallest member name keeps the point
+			// so that every instance builds the same ring.
+			if _, ok := r.owners[p]; ok {
+				continue
+			}
+			r.owners[p] = m
+			r.points = append(r.points, p)
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+	return r
+}
+
+// Members returns the names of the members of the ring in ascending order.
+func (r *Ring) Members() []string {
+	return append([]string(nil), r.members...)
+}
+
+// Update returns a ring with the members joined added and the members left removed.
+func (r *Ring) Update(joined, left []string) *Ring {
+	gone := make(map[string]bool, len(left))
+	for _, m := range left {
+		gone[m] = true
+	}
+	var members []string
+	for _, m := range append(r.Members(), joined...) {
+		if !gone[m] {
+			members = append(members, m)
+		}
+	}
+	return New(members, r.virtualNodes)
+}
+
+// Owner returns the member owning the key, the member of the first point at or after the hash of the key.
+// It returns an empty string if the ring has no members.
+func (r *Ring) Owner(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+	h := hash(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
+
+// Key returns the key of a target on the ring.
+// Targets are identified by their name and bucket, so that renaming either moves the target.
+func Key(target *probepb.Target) string {
+	return target.GetName() + "/" + target.GetBucketName()
+}
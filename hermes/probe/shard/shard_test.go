@@ -0,0 +1,109 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shard
+
+import (
+	"fmt"
+	"testing"
+)
+
+// genKeys returns n distinct target keys.
+func genKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("hermes/bucket_%d", i)
+	}
+	return keys
+}
+
+// owners returns the owner of every key.
+func owners(r *Ring, keys []string) map[string]string {
+	o := make(map[string]string, len(keys))
+	for _, k := range keys {
+		o[k] = r.Owner(k)
+	}
+	return o
+}
+
+func TestOwner(t *testing.T) {
+	if got := New(nil, 0).Owner("hermes/bucket"); got != "" {
+		t.Errorf("Owner() on an empty ring = %q, want \"\"", got)
+	}
+
+	keys := genKeys(1000)
+	r := New([]string{"c", "a", "b", "a"}, 0)
+	if got, want := fmt.Sprint(r.Members()), "[a b c]"; got != want {
+		t.Errorf("Members() = %s, want %s", got, want)
+	}
+	counts := make(map[string]int)
+	for _, owner := range owners(r, keys) {
+		counts[owner]++
+	}
+	for _, m := range r.Members() {
+		// With 100 virtual nodes per member, every member owns a fair share of the keys.
+		if counts[m] < len(keys)/6 {
+			t.Errorf("Owner() assigned %d of %d keys to member %q, want at least %d", counts[m], len(keys), m, len(keys)/6)
+		}
+	}
+
+	// Rings of the same members built in a different order assign the same owners.
+	again := New([]string{"b", "c", "a"}, 0)
+	for k, owner := range owners(r, keys) {
+		if got := again.Owner(k); got != owner {
+			t.Errorf("Owner(%q) = %q on a ring built in a different order, want %q", k, got, owner)
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	keys := genKeys(1000)
+	r := New([]string{"a", "b", "c"}, 0)
+	before := owners(r, keys)
+
+	tests := []struct {
+		desc   string
+		joined []string
+		left   []string
+		// moved reports whether a key may move from the owner before to the owner after the update.
+		moved func(before, after string) bool
+	}{
+		{
+			desc:   "member joins",
+			joined: []string{"d"},
+			moved:  func(before, after string) bool { return after == "d" },
+		},
+		{
+			desc:  "member leaves",
+			left:  []string{"b"},
+			moved: func(before, after string) bool { return before == "b" },
+		},
+	}
+	for _, tc := range tests {
+		after := r.Update(tc.joined, tc.left)
+		moved := 0
+		for k, owner := range owners(after, keys) {
+			if owner == before[k] {
+				continue
+			}
+			moved++
+			if !tc.moved(before[k], owner) {
+				t.Errorf("%s: Owner(%q) moved from %q to %q", tc.desc, k, before[k], owner)
+			}
+		}
+		if moved == 0 {
+			t.Errorf("%s: Update() did not move any keys", tc.desc)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package slo implements the service level indicators of a target over
+// rolling windows: the ratio of successful probe runs, the ratio of runs that
+// succeeded within the latency SLO, and the error budgets left.
+package slo
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+// bucketsPerWindow is the number of buckets the runs of a window are counted in.
+// The window rolls forward one bucket at a time, so its length is accurate to within one bucket.
+const bucketsPerWindow = 60
+
+// defaultWindows are the lengths of the windows if none are configured.
+var defaultWindows = []time.Duration{time.Hour, 24 * time.Hour, 28 * 24 * time.Hour}
+
+// counts holds the number of runs in a bucket of a window.
+type counts struct {
+	// index is the number of bucket widths between the Unix epoch and the start of the bucket.
+	index int64
+	runs  int64
+	// succeeded counts the successful runs and fast the successful runs within the latency threshold.
+	succeeded int64
+	fast      int64
+}
+
+// window counts the runs in a rolling window.
+type window struct {
+	length  time.Duration
+	width   time.Duration
+	buckets [bucketsPerWindow]counts
+}
+
+func (w *window) record(at time.Time, success, fast bool) {
+	index := at.UnixNano() / int64(w.width)
+	b := &w.buckets[index%bucketsPerWindow]
+	if b.index != index {
+		*b = counts{index: index}
+	}
+	b.runs++
+	if success {
+		b.succeeded++
+	}
+	if fast {
+		b.fast++
+	}
+}
+
+// sum returns the number of runs in the window ending at the time given.
+func (w *window) sum(now time.Time) counts {
+	index := now.UnixNano() / int64(w.width)
+	var total counts
+	for _, b := range w.buckets {
+		if b.runs == 0 || index-b.index >= bucketsPerWindow || b.index > index {
+			continue
+		}
+		total.runs += b.runs
+		total.succeeded += b.succeeded
+		total.fast += b.fast
+	}
+	return total
+}
+
+// Run is a probe run counted by a tracker.
+type Run struct {
+	// End is the time the run ended.
+	End     time.Time
+	Success bool
+	Latency time.Duration
+}
+
+// Tracker tracks the runs of a target and computes its service level indicators.
+// It is safe for concurrent use.
+type Tracker struct {
+	availabilityTarget float64
+	latencyTarget      float64
+	latencyThreshold   time.Duration
+
+	mu      sync.Mutex
+	windows []*window
+}
+
+// New returns a tracker of the objectives of the SLO config given.
+// Arguments:
+//	- conf: the SLO config of the probe.
+//	- timeout: the probe timeout, the default latency threshold.
+// Returns:
+//	- tracker: returns the tracker, or nil if conf is nil.
+//	- err: returns an error if a target ratio is not in (0, 1) or a window is shorter than a minute.
+func New(conf *probepb.SLOConfig, timeout time.Duration) (*Tracker, error) {
+	if conf == nil {
+		return nil, nil
+	}
+	t := &Tracker{
+		availabilityTarget: conf.GetAvailabilityTarget(),
+		latencyTarget:      conf.GetAvailabilityTarget(),
+		latencyThreshold:   timeout,
+	}
+	if conf.LatencyTarget != nil {
+		t.latencyTarget = conf.GetLatencyTarget()
+	}
+	if t.availabilityTarget <= 0 || t.availabilityTarget >= 1 {
+		return nil, fmt.Errorf("invalid SLO: availability_target = %v; want a ratio in (0, 1)", t.availabilityTarget)
+	}
+	if t.latencyTarget <= 0 || t.latencyTarget >= 1 {
+		return nil, fmt.Errorf("invalid SLO: latency_target = %v; want a ratio in (0, 1)", t.latencyTarget)
+	}
+	if sec := conf.GetLatencyThresholdSec(); sec > 0 {
+		t.latencyThreshold = time.Duration(sec * float64(time.Second))
+	}
+	for _, sec := range Windows(conf) {
+		if sec < bucketsPerWindow {
+			return nil, fmt.Errorf("invalid SLO: window_sec = %d; want a window of at least %d seconds", sec, bucketsPerWindow)
+		}
+		length := time.Duration(sec) * time.Second
+		t.windows = append(t.windows, &window{length: length, width: length / bucketsPerWindow})
+	}
+	return t, nil
+}
+
+// Windows returns the lengths in seconds of the windows of the SLO config given, nil if conf is nil.
+func Windows(conf *probepb.SLOConfig) []int64 {
+	if conf == nil {
+		return nil
+	}
+	if len(conf.GetWindowSec()) > 0 {
+		return conf.GetWindowSec()
+	}
+	var windows []int64
+	for _, length := range defaultWindows {
+		windows = append(windows, int64(length/time.Second))
+	}
+	return windows
+}
+
+// Longest returns the length of the longest window of the tracker.
+func (t *Tracker) Longest() time.Duration {
+	var longest time.Duration
+	for _, w := range t.windows {
+		if w.length > longest {
+			longest = w.length
+		}
+	}
+	return longest
+}
+
+// Record counts a probe run in every window.
+// Arguments:
+//	- at: the time the run ended.
+//	- success: whether the run succeeded.
+//	- latency: the time taken by the run.
+func (t *Tracker) Record(at time.Time, success bool, latency time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, w := range t.windows {
+		w.record(at, success, success && latency <= t.latencyThreshold)
+	}
+}
+
+// Rebuild replaces the runs counted in every window with the runs given, e.g. the runs
+// of the target read back from the run history.
+// Arguments:
+//	- runs: the runs to count, a run is counted at the time it ended.
+func (t *Tracker) Rebuild(runs []Run) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, w := range t.windows {
+		w.buckets = [bucketsPerWindow]counts{}
+		for _, r := range runs {
+			w.record(r.End, r.Success, r.Success && r.Latency <= t.latencyThreshold)
+		}
+	}
+}
+
+// budgetRemaining returns the fraction of the error budget of an objective left at the ratio given.
+func budgetRemaining(ratio, target float64) float64 {
+	return 1 - (1-ratio)/(1-target)
+}
+
+// Indicators returns the service level indicators of every window ending at the time given.
+func (t *Tracker) Indicators(now time.Time) []*probepb.SLI {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var slis []*probepb.SLI
+	for _, w := range t.windows {
+		c := w.sum(now)
+		sli := &probepb.SLI{
+			WindowSec:    int64(w.length / time.Second),
+			Runs:         c.runs,
+			SuccessRatio: 1,
+			LatencyRatio: 1,
+		}
+		if c.runs > 0 {
+			sli.SuccessRatio = float64(c.succeeded) / float64(c.runs)
+			sli.LatencyRatio = float64(c.fast) / float64(c.runs)
+		}
+		sli.ErrorBudgetRemaining = budgetRemaining(sli.SuccessRatio, t.availabilityTarget)
+		sli.LatencyBudgetRemaining = budgetRemaining(sli.LatencyRatio, t.latencyTarget)
+		slis = append(slis, sli)
+	}
+	return slis
+}
+
+// WindowName returns the label of a window, e.g. 1h, 1d or 28d.
+func WindowName(sec int64) string {
+	switch {
+	case sec%86400 == 0:
+		return fmt.Sprintf("%dd", sec/86400)
+	case sec%3600 == 0:
+		return fmt.Sprintf("%dh", sec/3600)
+	case sec%60 == 0:
+		return fmt.Sprintf("%dm", sec/60)
+	}
+	return fmt.Sprintf("%ds", sec)
+}
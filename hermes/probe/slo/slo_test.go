@@ -0,0 +1,159 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slo
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestNew(t *testing.T) {
+	if tr, err := New(nil, time.Minute); tr != nil || err != nil {
+		t.Errorf("New(nil) = %v, %v, want nil, nil", tr, err)
+	}
+	for _, conf := range []*probepb.SLOConfig{
+		{},
+		{AvailabilityTarget: proto.Float64(1)},
+		{AvailabilityTarget: proto.Float64(0.99), LatencyTarget: proto.Float64(0)},
+		{AvailabilityTarget: proto.Float64(0.99), WindowSec: []int64{30}},
+	} {
+		if _, err := New(conf, time.Minute); err == nil {
+			t.Errorf("New(%v) succeeded, want an error", conf)
+		}
+	}
+	tr, err := New(&probepb.SLOConfig{AvailabilityTarget: proto.Float64(0.99)}, time.Minute)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	var windows []string
+	for _, sli := range tr.Indicators(time.Now()) {
+		windows = append(windows, WindowName(sli.GetWindowSec()))
+	}
+	if got, want := len(windows), 3; got != want || windows[0] != "1h" || windows[1] != "1d" || windows[2] != "28d" {
+		t.Errorf("New() default windows = %v, want [1h 1d 28d]", windows)
+	}
+}
+
+func TestIndicators(t *testing.T) {
+	tr, err := New(&probepb.SLOConfig{
+		AvailabilityTarget:  proto.Float64(0.9),
+		LatencyTarget:       proto.Float64(0.5),
+		LatencyThresholdSec: proto.Float64(2),
+		WindowSec:           []int64{3600, 86400},
+	}, time.Minute)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	start := time.Date(2020, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, sli := range tr.Indicators(start) {
+		if sli.GetRuns() != 0 || sli.GetSuccessRatio() != 1 || sli.GetErrorBudgetRemaining() != 1 {
+			t.Errorf("Indicators() without runs = %v, want every ratio and budget 1", sli)
+		}
+	}
+
+	// Two hours ago: one failed run, only in the 1d window.
+	tr.Record(start.Add(-2*time.Hour), false, time.Second)
+	// Within the last hour: a fast success, a slow success and a failure.
+	tr.Record(start.Add(-30*time.Minute), true, time.Second)
+	tr.Record(start.Add(-20*time.Minute), true, 5*time.Second)
+	tr.Record(start.Add(-10*time.Minute), false, time.Second)
+
+	tests := []struct {
+		window      int64
+		runs        int64
+		success     float64
+		latency     float64
+		errorBudget float64
+	}{
+		{window: 3600, runs: 3, success: 2.0 / 3, latency: 1.0 / 3, errorBudget: 1 - (1.0/3)/0.1},
+		{window: 86400, runs: 4, success: 2.0 / 4, latency: 1.0 / 4, errorBudget: 1 - (2.0/4)/0.1},
+	}
+	slis := tr.Indicators(start)
+	if len(slis) != len(tests) {
+		t.Fatalf("Indicators() returned %d windows, want %d", len(slis), len(tests))
+	}
+	for i, tc := range tests {
+		sli := slis[i]
+		if sli.GetWindowSec() != tc.window || sli.GetRuns() != tc.runs {
+			t.Errorf("Indicators()[%d] = window %d with %d runs, want window %d with %d runs", i, sli.GetWindowSec(), sli.GetRuns(), tc.window, tc.runs)
+		}
+		if !approxEqual(sli.GetSuccessRatio(), tc.success) || !approxEqual(sli.GetLatencyRatio(), tc.latency) {
+			t.Errorf("%s: success ratio, latency ratio = %v, %v, want %v, %v", WindowName(tc.window), sli.GetSuccessRatio(), sli.GetLatencyRatio(), tc.success, tc.latency)
+		}
+		if !approxEqual(sli.GetErrorBudgetRemaining(), tc.errorBudget) {
+			t.Errorf("%s: error budget remaining = %v, want %v", WindowName(tc.window), sli.GetErrorBudgetRemaining(), tc.errorBudget)
+		}
+		if want := 1 - (1-tc.latency)/0.5; !approxEqual(sli.GetLatencyBudgetRemaining(), want) {
+			t.Errorf("%s: latency budget remaining = %v, want %v", WindowName(tc.window), sli.GetLatencyBudgetRemaining(), want)
+		}
+	}
+
+	// A day later every run has left both windows.
+	for _, sli := range tr.Indicators(start.Add(25 * time.Hour)) {
+		if sli.GetRuns() != 0 {
+			t.Errorf("%s: Indicators() a day later counted %d runs, want 0", WindowName(sli.GetWindowSec()), sli.GetRuns())
+		}
+	}
+}
+
+func TestRebuild(t *testing.T) {
+	tr, err := New(&probepb.SLOConfig{
+		AvailabilityTarget:  proto.Float64(0.9),
+		LatencyThresholdSec: proto.Float64(2),
+		WindowSec:           []int64{3600, 86400},
+	}, time.Minute)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if got, want := tr.Longest(), 24*time.Hour; got != want {
+		t.Errorf("Longest() = %v, want %v", got, want)
+	}
+	start := time.Date(2020, time.August, 1, 0, 0, 0, 0, time.UTC)
+	tr.Record(start.Add(-time.Minute), false, time.Second)
+
+	// The runs recorded before are replaced by the runs given.
+	tr.Rebuild([]Run{
+		{End: start.Add(-2 * time.Hour), Success: true, Latency: time.Second},
+		{End: start.Add(-30 * time.Minute), Success: true, Latency: 5 * time.Second},
+	})
+	slis := tr.Indicators(start)
+	if len(slis) != 2 {
+		t.Fatalf("Indicators() returned %d windows, want 2", len(slis))
+	}
+	if sli := slis[0]; sli.GetRuns() != 1 || sli.GetSuccessRatio() != 1 || sli.GetLatencyRatio() != 0 {
+		t.Errorf("1h window after Rebuild() = %v, want one slow successful run", sli)
+	}
+	if sli := slis[1]; sli.GetRuns() != 2 || sli.GetSuccessRatio() != 1 || sli.GetLatencyRatio() != 0.5 {
+		t.Errorf("1d window after Rebuild() = %v, want two successful runs, one of them fast", sli)
+	}
+}
+
+func TestWindowName(t *testing.T) {
+	for sec, want := range map[int64]string{3600: "1h", 86400: "1d", 2419200: "28d", 900: "15m", 90: "90s"} {
+		if got := WindowName(sec); got != want {
+			t.Errorf("WindowName(%d) = %q, want %q", sec, got, want)
+		}
+	}
+}
@@ -36,4 +36,8 @@ type Target struct {
 	// LatencyMetrics stores the API call and probe operation latency for a given target run.
 	// Metrics are stored with additional labels to record operation type and exit status.
 	LatencyMetrics *metrics.Metrics
+
+	// Bootstrapped records whether the target has been bootstrapped since the probe started
+	// or since this instance took over the lease of the target.
+	Bootstrapped bool
 }
@@ -16,6 +16,14 @@
 //
 // Main program loop for Hermes. This initialises Cloudprober so that Hermes can
 // interact with it through gRPCs.
+//
+// With -probe_config set, Hermes also runs the probe of the config given and serves
+// its Hermes gRPC service on -hermes_rpc_port.
+//
+// With -run_probe_target set, Hermes instead asks the instance serving on -hermes_server
+// to run its probe once against a target and prints the result of every operation of the run.
+// With -history_target set, it prints the runs of a target from the run history
+// of the probe config given.
 
 package main
 
@@ -23,19 +31,58 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
 	"github.com/google/cloudprober"
+	"github.com/google/cloudprober/logger"
+	"github.com/google/cloudprober/metrics"
+	"github.com/google/cloudprober/probes/options"
 	"github.com/google/cloudprober/web"
+	"github.com/googleinterns/step224-2020/hermes/probe"
+	"google.golang.org/grpc"
+
+	probepb "github.com/googleinterns/step224-2020/config/proto"
 )
 
 var (
-	rpcPort = flag.Int("rpc_port", 9314, "The port that the gRPC server of Cloudprober will run on.")
+	rpcPort       = flag.Int("rpc_port", 9314, "The port that the gRPC server of Cloudprober will run on.")
+	hermesRPCPort = flag.Int("hermes_rpc_port", 9315, "The port that the Hermes gRPC service of the probe of -probe_config will run on.")
+
+	probeConfig    = flag.String("probe_config", "", "Path to a HermesProbeDef in text format, the probe served on -hermes_rpc_port and used by -history_target.")
+	hermesServer   = flag.String("hermes_server", "localhost:9315", "Address of the Hermes gRPC service that -run_probe_target runs the probe on.")
+	runProbeTarget = flag.String("run_probe_target", "", "If set, run the probe of -hermes_server once against its target with this name and exit.")
+	runProbeBucket = flag.String("run_probe_bucket", "", "The bucket name of the target run by -run_probe_target.")
+
+	historyTarget = flag.String("history_target", "", "If set, print the runs of the target of -probe_config with this name from the run history and exit.")
+	historyBucket = flag.String("history_bucket", "", "The bucket name of the target of -history_target.")
+	historyStart  = flag.String("history_start", "", "Start of the runs printed by -history_target in RFC 3339 format, default = 24 hours before -history_end.")
+	historyEnd    = flag.String("history_end", "", "End of the runs printed by -history_target in RFC 3339 format, default = now.")
 )
 
 func main() {
 	flag.Parse()
 
+	if *runProbeTarget != "" {
+		if err := runProbeOnce(context.Background(), os.Stdout); err != nil {
+			glog.Exitf("probe run on target %q failed: %v", *runProbeTarget, err)
+		}
+		return
+	}
+
+	if *historyTarget != "" {
+		if err := printHistory(context.Background(), os.Stdout); err != nil {
+			glog.Exitf("could not print the run history of target %q: %v", *historyTarget, err)
+		}
+		return
+	}
+
 	if err := cloudprober.InitFromConfig(buildConfig()); err != nil {
 		glog.Exitf("cloudprober could not be initialised from config: grpc_port: %d, err:%v", *rpcPort, err)
 	}
@@ -45,6 +92,12 @@ func main() {
 
 	cloudprober.Start(context.Background())
 
+	if *probeConfig != "" {
+		if err := serveProbe(context.Background()); err != nil {
+			glog.Exitf("could not serve the probe of -probe_config: %v", err)
+		}
+	}
+
 	// Wait forever
 	select {}
 }
@@ -55,3 +108,175 @@ func main() {
 func buildConfig() string {
 	return fmt.Sprintf("grpc_port: %d", *rpcPort)
 }
+
+// serveProbe starts the probe of -probe_config and serves its Hermes gRPC service on -hermes_rpc_port.
+// The metrics of the probe runs are logged.
+// Arguments:
+//	- ctx: the context of the probe, the probe stops when it is cancelled.
+// Returns:
+//	- err: returns an error if the probe could not be initialised or the port could not be listened on.
+func serveProbe(ctx context.Context) error {
+	p, err := loadProbe()
+	if err != nil {
+		return err
+	}
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", *hermesRPCPort))
+	if err != nil {
+		return fmt.Errorf("could not listen on -hermes_rpc_port %d: %w", *hermesRPCPort, err)
+	}
+	server := grpc.NewServer()
+	probepb.RegisterHermesServer(server, p)
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			glog.Errorf("Hermes gRPC server stopped: %v", err)
+		}
+	}()
+
+	metricChan := make(chan *metrics.EventMetrics, 100)
+	go func() {
+		for em := range metricChan {
+			glog.Info(em.String())
+		}
+	}()
+	go p.Start(ctx, metricChan)
+	return nil
+}
+
+// formatResult formats the result of an operation of a probe run as a line.
+func formatResult(result *probepb.RunProbeResult) string {
+	return fmt.Sprintf("%-20s %-25s %9.3fs %s", result.GetOperation(), result.GetStatus(), result.GetLatencySec(), result.GetError())
+}
+
+// loadProbe initialises a probe from the HermesProbeDef of -probe_config.
+// Returns:
+//	- p: returns the probe.
+//	- err: returns an error if the config could not be read or the probe could not be initialised.
+func loadProbe() (*probe.Probe, error) {
+	data, err := ioutil.ReadFile(*probeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not read -probe_config: %w", err)
+	}
+	cfg := &probepb.HermesProbeDef{}
+	if err := proto.UnmarshalText(string(data), cfg); err != nil {
+		return nil, fmt.Errorf("could not parse -probe_config %q: %w", *probeConfig, err)
+	}
+
+	opts := &options.Options{
+		Interval:  time.Duration(cfg.GetIntervalSec()) * time.Second,
+		Timeout:   time.Duration(cfg.GetTimeoutSec()) * time.Second,
+		ProbeConf: cfg,
+	}
+	if opts.Logger, err = logger.NewCloudproberLog(cfg.GetProbeName()); err != nil {
+		return nil, fmt.Errorf("could not create logger: %w", err)
+	}
+	p := &probe.Probe{}
+	if err := p.Init(cfg.GetProbeName(), opts); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// runProbeOnce asks the instance serving on -hermes_server to run its probe once against the target
+// of -run_probe_target, and prints the results of the run as they are streamed. The run is coordinated
+// with the scheduled runs of the serving instance, so the two never overlap, and it is run under the
+// lease of that instance, so no lease is left behind when the command exits.
+// Arguments:
+//	- ctx: the context of the run.
+//	- w: the writer the results of the run are printed to.
+// Returns:
+//	- err: returns an error if the RPC failed or the run did not succeed.
+func runProbeOnce(ctx context.Context, w io.Writer) error {
+	conn, err := grpc.DialContext(ctx, *hermesServer, grpc.WithInsecure())
+	if err != nil {
+		return fmt.Errorf("could not connect to -hermes_server %q: %w", *hermesServer, err)
+	}
+	defer conn.Close()
+
+	req := &probepb.RunProbeRequest{Target: &probepb.Target{Name: *runProbeTarget, BucketName: *runProbeBucket}}
+	stream, err := probepb.NewHermesClient(conn).RunProbe(ctx, req)
+	if err != nil {
+		return err
+	}
+	failed := false
+	for {
+		result, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if result.GetFinal() && result.GetStatus() != "success" {
+			failed = true
+		}
+		if _, err := fmt.Fprintln(w, formatResult(result)); err != nil {
+			return err
+		}
+	}
+	if failed {
+		return fmt.Errorf("the run did not succeed")
+	}
+	return nil
+}
+
+// parseTime parses a time flag in RFC 3339 format, returning the default given if the flag is empty.
+func parseTime(name, value string, def time.Time) (time.Time, error) {
+	if value == "" {
+		return def, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not parse -%s: %w", name, err)
+	}
+	return t, nil
+}
+
+// printHistory prints the runs of the target of -history_target that started between -history_start
+// and -history_end, from the run history of -probe_config, with the result of every operation of
+// each run and the files it accessed. Only the runs of the instances sharing the history directory
+// are printed, to query a serving instance use its GetRunHistory RPC instead.
+// Arguments:
+//	- ctx: the context of the query.
+//	- w: the writer the runs are printed to.
+// Returns:
+//	- err: returns an error if the probe could not be initialised or the history could not be read.
+func printHistory(ctx context.Context, w io.Writer) error {
+	end, err := parseTime("history_end", *historyEnd, time.Now())
+	if err != nil {
+		return err
+	}
+	start, err := parseTime("history_start", *historyStart, end.Add(-24*time.Hour))
+	if err != nil {
+		return err
+	}
+	p, err := loadProbe()
+	if err != nil {
+		return err
+	}
+	resp, err := p.GetRunHistory(ctx, &probepb.GetRunHistoryRequest{
+		Targets:      []*probepb.Target{{Name: *historyTarget, BucketName: *historyBucket}},
+		StartUnixSec: start.Unix(),
+		EndUnixSec:   end.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, run := range resp.GetRuns() {
+		started := time.Unix(0, run.GetStartUnixMs()*int64(time.Millisecond)).UTC()
+		latency := time.Duration(run.GetEndUnixMs()-run.GetStartUnixMs()) * time.Millisecond
+		if _, err := fmt.Fprintf(w, "%s %-25s %9.3fs %s\n", started.Format(time.RFC3339), run.GetStatus(), latency.Seconds(), run.GetError()); err != nil {
+			return err
+		}
+		for _, op := range run.GetOperations() {
+			if _, err := fmt.Fprintf(w, "  %s\n", formatResult(op)); err != nil {
+				return err
+			}
+		}
+		if len(run.GetFiles()) > 0 {
+			if _, err := fmt.Fprintf(w, "  files: %s\n", strings.Join(run.GetFiles(), " ")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -21,7 +21,7 @@
 # Compile all protos
 cd $HOME/go/src
 
-protoc -I=. --go_out=. github.com/googleinterns/step224-2020/config/proto/*.proto
+protoc -I=. --go_out=. --go-grpc_out=. github.com/googleinterns/step224-2020/config/proto/*.proto
 
 # Run go fmt on all .go files to format them
 go fmt github.com/googleinterns/step224-2020/...